@@ -17,29 +17,1728 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// CRDScope is the scope of the CustomResourceDefinitions generated by a ReconTest.
+// +kubebuilder:validation:Enum=Namespaced;Cluster
+type CRDScope string
+
+const (
+	// NamespacedScope generates namespace scoped CRDs.
+	NamespacedScope CRDScope = "Namespaced"
+	// ClusterScope generates cluster scoped CRDs.
+	ClusterScope CRDScope = "Cluster"
+)
+
+// CleanupPolicy decides what happens to the generated CRDs of a run when its ReconTest is deleted.
+// +kubebuilder:validation:Enum=Delete;Retain;Orphan
+type CleanupPolicy string
+
+const (
+	// CleanupDelete deletes every CRD of the run and waits until they are gone.
+	CleanupDelete CleanupPolicy = "Delete"
+	// CleanupRetain leaves the CRDs of the run, and their run labels, in place.
+	CleanupRetain CleanupPolicy = "Retain"
+	// CleanupOrphan leaves the CRDs of the run in place and removes their run labels.
+	CleanupOrphan CleanupPolicy = "Orphan"
+)
+
+// Labels, annotations and finalizers the controller puts on objects it manages.
+const (
+	// RunLabel is set on every generated CRD to the UID of the owning ReconTest.
+	RunLabel = "example.anirudh.io/run"
+	// OwnerAnnotation is set on every generated CRD to the <namespace>/<name> of the owning ReconTest.
+	OwnerAnnotation = "example.anirudh.io/owner"
+	// CleanupFinalizer holds a ReconTest until its CleanupPolicy has been applied.
+	CleanupFinalizer = "example.anirudh.io/cleanup"
+	// SpecHashAnnotation is set on every generated CRD to the hash of its intended spec.
+	SpecHashAnnotation = "example.anirudh.io/spec-hash"
+	// ProbeWrittenAnnotation is set on the watch probe of every watched kind to the time it was last written.
+	ProbeWrittenAnnotation = "example.anirudh.io/probe-written"
+	// SchemaRevisionAnnotation is set on a generated CRD to the number of schema churn updates applied to it.
+	SchemaRevisionAnnotation = "example.anirudh.io/schema-revision"
+	// CompetitorAnnotation is set by the competing field manager to the number of the round that last applied the CRD.
+	CompetitorAnnotation = "example.anirudh.io/competitor-round"
+)
+
+// DriftPolicy decides what happens to a generated CRD whose live spec no longer matches its intended spec.
+// +kubebuilder:validation:Enum=Ignore;Update;Recreate
+type DriftPolicy string
+
+const (
+	// DriftIgnore only counts drifted CRDs.
+	DriftIgnore DriftPolicy = "Ignore"
+	// DriftUpdate updates drifted CRDs back to their intended spec.
+	DriftUpdate DriftPolicy = "Update"
+	// DriftRecreate deletes drifted CRDs so they are created again from their intended spec.
+	DriftRecreate DriftPolicy = "Recreate"
+)
+
+// SchemaPreset names a built-in schema for the generated CRDs.
+// +kubebuilder:validation:Enum=Complex
+type SchemaPreset string
+
+const (
+	// SchemaPresetComplex is the organization/projectMetadata schema.
+	SchemaPresetComplex SchemaPreset = "Complex"
+)
+
+// SchemaSpec describes the OpenAPI v3 schema of the generated CRDs. When
+// Preset is unset the schema is generated from the remaining fields.
+type SchemaSpec struct {
+	// Preset selects a built-in schema. The remaining fields, except CEL, are
+	// ignored when it is set.
+	// +optional
+	Preset SchemaPreset `json:"preset,omitempty"`
+
+	// Depth is the nesting depth of objects under spec.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +kubebuilder:default=3
+	// +optional
+	Depth int32 `json:"depth,omitempty"`
+
+	// FanOut is the number of properties of every object.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +kubebuilder:default=4
+	// +optional
+	FanOut int32 `json:"fanOut,omitempty"`
+
+	// ArrayNesting is the number of arrays wrapped around the first nested
+	// object of every object.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=8
+	// +optional
+	ArrayNesting int32 `json:"arrayNesting,omitempty"`
+
+	// Enums is the number of string properties restricted to an enum.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Enums int32 `json:"enums,omitempty"`
+
+	// EnumSize is the number of values of every enum.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=4
+	// +optional
+	EnumSize int32 `json:"enumSize,omitempty"`
+
+	// Patterns is the number of string properties validated by a pattern.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Patterns int32 `json:"patterns,omitempty"`
+
+	// Formats is the number of string properties validated by a format.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Formats int32 `json:"formats,omitempty"`
+
+	// TargetBytes pads the schema with extra properties until its serialized
+	// size reaches this many bytes. Every served version of a CRD holds a copy
+	// of the schema, so the copies of all versions and the rest of the CRD must
+	// fit in the 1.5MiB request size limit of etcd: it is capped at 1MiB for a
+	// single version, and runs whose versions do not fit are rejected.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1048576
+	// +optional
+	TargetBytes int32 `json:"targetBytes,omitempty"`
+
+	// CEL adds x-kubernetes-validations rules to the schema, whether it is a
+	// preset or generated.
+	// +optional
+	CEL *CELSpec `json:"cel,omitempty"`
+}
+
+// CELCost is the most expensive class of the generated CEL validation rules.
+// +kubebuilder:validation:Enum=Low;Medium;High
+type CELCost string
+
+const (
+	// CELCostLow generates comparisons between fields, checks that strings have no
+	// surrounding whitespace and that lists without a minItems are not empty, whose
+	// cost does not depend on the size of lists.
+	CELCostLow CELCost = "Low"
+	// CELCostMedium adds regular expression matches and checks of every item of a list.
+	CELCostMedium CELCost = "Medium"
+	// CELCostHigh adds uniqueness checks of list items, whose cost grows with the
+	// square of the list size.
+	CELCostHigh CELCost = "High"
+)
+
+// CELSpec controls the x-kubernetes-validations rules added to the schema of the
+// generated CRDs. Rules are only added below spec, and generated custom resources
+// are synthesized to satisfy them.
+type CELSpec struct {
+	// Density is the percentage of the objects and arrays below spec that carry rules.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=100
+	// +optional
+	Density int32 `json:"density,omitempty"`
+
+	// RulesPerNode is the maximum number of rules of every object or array that carries rules.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +kubebuilder:default=1
+	// +optional
+	RulesPerNode int32 `json:"rulesPerNode,omitempty"`
+
+	// Cost is the most expensive class of rules that is generated. More
+	// expensive rules are preferred when a node has room for fewer rules than
+	// it could carry.
+	// +kubebuilder:default=Low
+	// +optional
+	Cost CELCost `json:"cost,omitempty"`
+
+	// MaxItems bounds every array of the schema. The API server rejects rules
+	// whose estimated cost is too high, and cannot estimate the cost of rules
+	// on or below unbounded arrays.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1024
+	// +kubebuilder:default=16
+	// +optional
+	MaxItems int32 `json:"maxItems,omitempty"`
+}
+
+// RampProfile is the shape of the rate increase during a ramp-up.
+// +kubebuilder:validation:Enum=Linear;Step
+type RampProfile string
+
+const (
+	// RampLinear raises the rate linearly to the target rate.
+	RampLinear RampProfile = "Linear"
+	// RampStep raises the rate to the target rate in equal steps.
+	RampStep RampProfile = "Step"
+)
+
+// RampSpec describes how the request rate rises to its target.
+type RampSpec struct {
+	// Profile is the shape of the rate increase.
+	Profile RampProfile `json:"profile"`
+
+	// Duration is how long the rate takes to reach the target rate.
+	Duration metav1.Duration `json:"duration"`
+
+	// Steps is the number of steps of a Step ramp.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=4
+	// +optional
+	Steps int32 `json:"steps,omitempty"`
+}
+
+// RateSpec controls how fast the requests of a run are issued.
+type RateSpec struct {
+	// Concurrency is the number of requests in flight at once.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+
+	// QPS is the target number of requests per second. Requests are not rate
+	// limited beyond the client defaults when it is unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+
+	// Burst is the number of requests that may be issued at once above QPS.
+	// Defaults to Concurrency.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+
+	// RampUp raises the rate to QPS gradually at the start of every pass.
+	// +optional
+	RampUp *RampSpec `json:"rampUp,omitempty"`
+}
+
+// ErrorClass is a class of errors returned by the API server that are handled alike.
+// +kubebuilder:validation:Enum=Conflict;Throttled;Timeout;Invalid;Forbidden;ServerError;RequestTooLarge;Other
+type ErrorClass string
+
+const (
+	// ErrorConflict is a 409 Conflict, e.g. a stale resourceVersion.
+	ErrorConflict ErrorClass = "Conflict"
+	// ErrorThrottled is a 429 Too Many Requests from API priority and fairness or rate limits.
+	ErrorThrottled ErrorClass = "Throttled"
+	// ErrorTimeout is a server or client timeout.
+	ErrorTimeout ErrorClass = "Timeout"
+	// ErrorInvalid is a request the API server rejected as invalid or malformed.
+	ErrorInvalid ErrorClass = "Invalid"
+	// ErrorForbidden is a request the operator is not authorized to make.
+	ErrorForbidden ErrorClass = "Forbidden"
+	// ErrorServerError is a 5xx error other than a timeout.
+	ErrorServerError ErrorClass = "ServerError"
+	// ErrorRequestTooLarge is an object too large for the API server or etcd to store.
+	ErrorRequestTooLarge ErrorClass = "RequestTooLarge"
+	// ErrorOther is any other error.
+	ErrorOther ErrorClass = "Other"
+)
+
+// RetryAction is what happens to a request that failed with an error of a class.
+// +kubebuilder:validation:Enum=Retry;GiveUp;Abort
+type RetryAction string
+
+const (
+	// RetryBackoff retries the request at once with exponential backoff, and gives
+	// up once its attempts are exhausted.
+	RetryBackoff RetryAction = "Retry"
+	// RetryGiveUp records the request as failed. It is tried again on the next pass.
+	RetryGiveUp RetryAction = "GiveUp"
+	// RetryAbort stops the run until its spec changes.
+	RetryAbort RetryAction = "Abort"
+)
+
+// RetryRule is the retry policy of an error class.
+type RetryRule struct {
+	// Class is the error class the rule applies to.
+	Class ErrorClass `json:"class"`
+
+	// Action is what happens to a request that failed with an error of the class.
+	Action RetryAction `json:"action"`
+
+	// MaxAttempts is the number of attempts of a request, the first included,
+	// before a Retry rule gives up.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	// +kubebuilder:default=5
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+
+	// InitialBackoff is the delay before the first retry. It doubles with every
+	// further retry. Throttled requests wait at least as long as the API server asks.
+	// +kubebuilder:default="100ms"
+	// +optional
+	InitialBackoff metav1.Duration `json:"initialBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between two attempts.
+	// +kubebuilder:default="10s"
+	// +optional
+	MaxBackoff metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// RetryPolicy decides how the failed requests of a run are handled, by error class.
+// Conflict, Throttled, Timeout and ServerError errors are retried by default, the
+// other classes are given up.
+type RetryPolicy struct {
+	// Rules override the default handling of error classes.
+	// +listType=map
+	// +listMapKey=class
+	// +optional
+	Rules []RetryRule `json:"rules,omitempty"`
+
+	// RequeueAfter is the delay before the next pass after a pass that gave up on
+	// some requests.
+	// +kubebuilder:default="30s"
+	// +optional
+	RequeueAfter metav1.Duration `json:"requeueAfter,omitempty"`
+}
+
+// DiscoveryEndpoint is an endpoint through which clients learn about the generated kinds.
+// +kubebuilder:validation:Enum=GroupVersion;Aggregated;OpenAPIV2;OpenAPIV3
+type DiscoveryEndpoint string
+
+const (
+	// DiscoveryGroupVersion is the discovery document of a group version, /apis/<group>/<version>.
+	DiscoveryGroupVersion DiscoveryEndpoint = "GroupVersion"
+	// DiscoveryAggregated is the aggregated discovery document /apis, served since Kubernetes 1.26.
+	DiscoveryAggregated DiscoveryEndpoint = "Aggregated"
+	// DiscoveryOpenAPIV2 is the OpenAPI v2 document /openapi/v2, which holds every kind of the cluster.
+	DiscoveryOpenAPIV2 DiscoveryEndpoint = "OpenAPIV2"
+	// DiscoveryOpenAPIV3 is the OpenAPI v3 document of a group version, /openapi/v3/apis/<group>/<version>.
+	DiscoveryOpenAPIV3 DiscoveryEndpoint = "OpenAPIV3"
+)
+
+// DiscoverySpec enables measuring how long the kinds of the generated CRDs take to
+// appear in and disappear from the discovery and OpenAPI endpoints after their CRD
+// is created or deleted. Kinds are looked up in the storage version.
+type DiscoverySpec struct {
+	// Endpoints are the endpoints that are polled. Polling OpenAPIV2 fetches the
+	// schemas of every kind of the cluster and is expensive on large clusters.
+	// +kubebuilder:default={GroupVersion,Aggregated,OpenAPIV3}
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Endpoints []DiscoveryEndpoint `json:"endpoints,omitempty"`
+
+	// Interval is the time between two polls of the endpoints.
+	// +kubebuilder:default="1s"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout is how long a kind may take to appear or disappear before it is
+	// counted as timed out and no longer awaited.
+	// +kubebuilder:default="5m"
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+// WatchMode is how the watchers of a run follow the generated kinds.
+// +kubebuilder:validation:Enum=Watch;Informer
+type WatchMode string
+
+const (
+	// WatchRaw opens raw watches that resume from the last resource version they saw
+	// when the API server closes them.
+	WatchRaw WatchMode = "Watch"
+	// WatchInformer runs a dynamic informer with its own cache per watcher, like
+	// every controller of a kind does.
+	WatchInformer WatchMode = "Informer"
+)
+
+// WatchSpec opens watchers on the kinds of the generated CRDs once they are established,
+// to load the API server with the watch fan-out of many controllers. The watchers measure
+// the delivery latency of the events of a probe custom resource written to every kind.
+type WatchSpec struct {
+	// PerKind is the number of watchers opened on every generated kind.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default=1
+	// +optional
+	PerKind int32 `json:"perKind,omitempty"`
+
+	// Mode is how the watchers follow the kinds.
+	// +kubebuilder:default=Watch
+	// +optional
+	Mode WatchMode `json:"mode,omitempty"`
+
+	// ProbeInterval is the time between two writes of the probe custom resource of
+	// every watched kind.
+	// +kubebuilder:default="10s"
+	// +optional
+	ProbeInterval metav1.Duration `json:"probeInterval,omitempty"`
+}
+
+// ListTarget is a collection the list load of a run reads.
+// +kubebuilder:validation:Enum=CustomResourceDefinitions;CustomResources
+type ListTarget string
+
+const (
+	// ListCRDs lists the customresourcedefinitions of the cluster.
+	ListCRDs ListTarget = "CustomResourceDefinitions"
+	// ListCustomResources lists the custom resources of the established generated
+	// kinds in every namespace, one kind after the other.
+	ListCustomResources ListTarget = "CustomResources"
+)
+
+// ListResourceVersion is the resourceVersion semantics of the list calls of a run.
+// +kubebuilder:validation:Enum=None;NotOlderThan;Exact
+type ListResourceVersion string
+
+const (
+	// ListResourceVersionNone sets no resourceVersion, reading the most recent data from etcd.
+	ListResourceVersionNone ListResourceVersion = "None"
+	// ListNotOlderThan reads data at least as recent as the previous list of the
+	// target, served from the watch cache.
+	ListNotOlderThan ListResourceVersion = "NotOlderThan"
+	// ListExact reads the data at exactly the resource version of the previous
+	// list of the target, from etcd.
+	ListExact ListResourceVersion = "Exact"
+)
+
+// ListSpec issues LIST calls against the generated kinds and the CRDs themselves while
+// the run goes on, to measure how read latency and response size grow with the number
+// of CRDs.
+type ListSpec struct {
+	// Targets are the collections listed, in turn.
+	// +kubebuilder:default={CustomResourceDefinitions,CustomResources}
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Targets []ListTarget `json:"targets,omitempty"`
+
+	// PageSize is the limit of every list request; the rest of the collection is
+	// read page by page with continue tokens. Zero reads every collection in one
+	// request. Defaults to 500.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PageSize *int32 `json:"pageSize,omitempty"`
+
+	// ResourceVersion is the resourceVersion semantics of the first request of a list.
+	// +kubebuilder:default=None
+	// +optional
+	ResourceVersion ListResourceVersion `json:"resourceVersion,omitempty"`
+
+	// LabelSelector restricts the listed objects.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Rate controls the concurrency and the number of lists per second. The pages
+	// of a list are requested one after the other.
+	// +optional
+	Rate *RateSpec `json:"rate,omitempty"`
+}
+
+// WriteMode is how the controller writes the generated CRDs.
+// +kubebuilder:validation:Enum=Create;Update;ServerSideApply
+type WriteMode string
+
+const (
+	// WriteCreate creates the missing CRDs and leaves the existing ones to the drift policy.
+	WriteCreate WriteMode = "Create"
+	// WriteUpdate creates the missing CRDs and updates the existing ones with their
+	// intended spec on every pass.
+	WriteUpdate WriteMode = "Update"
+	// WriteServerSideApply applies the intended CRDs with server-side apply on every pass,
+	// creating the missing ones.
+	WriteServerSideApply WriteMode = "ServerSideApply"
+)
+
+// WriteSpec sets how the controller writes the generated CRDs.
+type WriteSpec struct {
+	// Mode is how the CRDs are written. Update and ServerSideApply write every
+	// CRD of the run on every pass, which puts drifted CRDs back as a side effect.
+	// +kubebuilder:default=Create
+	// +optional
+	Mode WriteMode `json:"mode,omitempty"`
+
+	// FieldManager is the field manager of the writes of the controller.
+	// +kubebuilder:default=recontest-operator
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// Force makes the applies of the controller take over the fields owned by
+	// other managers rather than fail with a conflict. It only applies to the
+	// ServerSideApply mode.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// Competitor applies conflicting values to the CRDs of the run under a field
+	// manager of its own.
+	// +optional
+	Competitor *CompetitorSpec `json:"competitor,omitempty"`
+}
+
+// CompetitorSpec configures a competing field manager. In every round it applies the
+// versions of a share of the CRDs of a steady run with a changed schema description,
+// fields the controller owns, so the two managers conflict.
+type CompetitorSpec struct {
+	// FieldManager is the field manager of the competitor. It must differ from the
+	// field manager of the controller.
+	// +kubebuilder:default=recontest-competitor
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// Percent is the share of the CRDs of the run applied in every round.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	Percent int32 `json:"percent,omitempty"`
+
+	// Interval is the time between the start of two rounds.
+	// +kubebuilder:default="30s"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Force makes the applies of the competitor take over the fields owned by the
+	// controller rather than fail with a conflict.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// QPS is the number of applies per second within a round. Applies are not
+	// rate limited beyond the client defaults when it is unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+}
+
+// ReportFormat is a format the report of a run is written in.
+// +kubebuilder:validation:Enum=JSON;CSV;JUnit
+type ReportFormat string
+
+const (
+	// ReportJSON writes the whole report to report.json.
+	ReportJSON ReportFormat = "JSON"
+	// ReportCSV writes the timings of every CRD to crds.csv and the throughput
+	// over time to throughput.csv.
+	ReportCSV ReportFormat = "CSV"
+	// ReportJUnit writes the assertions of the run to junit.xml, one test case each.
+	ReportJUnit ReportFormat = "JUnit"
+)
+
+// ReportDestination is where the report of a run is written.
+// +kubebuilder:validation:Enum=ConfigMap;Directory
+type ReportDestination string
+
+const (
+	// ReportConfigMap writes the report to a ConfigMap in the namespace of the ReconTest.
+	ReportConfigMap ReportDestination = "ConfigMap"
+	// ReportDirectory writes the report to <namespace>/<name> below the report
+	// directory of the operator, set with --report-dir.
+	ReportDirectory ReportDestination = "Directory"
+)
+
+// ReportSpec enables the report of a run: the per-CRD timings, the API errors by
+// status reason, the throughput over time and the configuration of the run. It is
+// refreshed periodically and written a last time when the ReconTest is deleted.
+type ReportSpec struct {
+	// Formats are the formats the report is written in.
+	// +kubebuilder:default={JSON,CSV,JUnit}
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Formats []ReportFormat `json:"formats,omitempty"`
+
+	// Destinations are where the report is written.
+	// +kubebuilder:default={ConfigMap}
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Destinations []ReportDestination `json:"destinations,omitempty"`
+
+	// ConfigMapName is the name of the ConfigMap the report is written to.
+	// Defaults to <name>-report. The ConfigMap is kept when the ReconTest is
+	// deleted, so the report can be collected after the run. It has no owner
+	// reference and is never garbage-collected.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Interval is the time between two refreshes of the report.
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// BaselineRef points to the report of an earlier run the run is compared against.
+type BaselineRef struct {
+	// ConfigMapName is the name of the ConfigMap in the namespace of the ReconTest
+	// holding the JSON report of the baseline run, usually the report ConfigMap
+	// kept after that run was deleted.
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+
+	// TolerancePercent is how much worse than the baseline a metric may get, in
+	// percent of its baseline value, before it counts as a regression.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TolerancePercent int32 `json:"tolerancePercent,omitempty"`
+
+	// Confidence is the confidence level, in percent, at which a change of a
+	// metric must be statistically significant to count as a regression.
+	// +kubebuilder:default=95
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	Confidence int32 `json:"confidence,omitempty"`
+}
+
+// AssertionMetric is the measurement of a run an assertion is about.
+// +kubebuilder:validation:Enum=NamesAcceptedLatency;EstablishedLatency;InstanceCreateLatency;ErrorRate;AllEstablished
+type AssertionMetric string
+
+const (
+	// AssertNamesAcceptedLatency is the latency from the Create call of a CRD until
+	// its NamesAccepted condition is True.
+	AssertNamesAcceptedLatency AssertionMetric = "NamesAcceptedLatency"
+	// AssertEstablishedLatency is the latency from the Create call of a CRD until
+	// its Established condition is True.
+	AssertEstablishedLatency AssertionMetric = "EstablishedLatency"
+	// AssertInstanceCreateLatency is the duration of the Create calls of custom resources.
+	AssertInstanceCreateLatency AssertionMetric = "InstanceCreateLatency"
+	// AssertErrorRate is the percentage of the API requests of the run that failed.
+	AssertErrorRate AssertionMetric = "ErrorRate"
+	// AssertAllEstablished is the time from the first Create call of the run until
+	// every desired CRD was established.
+	AssertAllEstablished AssertionMetric = "AllEstablished"
+)
+
+// Assertion is an expectation on a run, such as "the p99 establishment latency
+// is at most 5s" or "at most 0.1% of the requests fail".
+type Assertion struct {
+	// Name identifies the assertion in status and reports. Defaults to the
+	// metric, followed by the percentile for latencies, e.g. EstablishedLatencyP99.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Metric is the measurement the assertion is about.
+	Metric AssertionMetric `json:"metric"`
+
+	// Percentile is the percentile of the latency samples compared against Max.
+	// Only used by the latency metrics.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=99
+	// +optional
+	Percentile int32 `json:"percentile,omitempty"`
+
+	// Max is the highest acceptable duration, required by the latency metrics and AllEstablished.
+	// +optional
+	Max *metav1.Duration `json:"max,omitempty"`
+
+	// MaxPercent is the highest acceptable percentage, such as "0.1", required by ErrorRate.
+	// +optional
+	MaxPercent *resource.Quantity `json:"maxPercent,omitempty"`
+}
+
+// ChurnSpec enables churn: a share of the CRDs of a steady run is deleted in
+// rounds and the controller recreates them.
+type ChurnSpec struct {
+	// Percent is the share of the CRDs of the run deleted in every round.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	Percent int32 `json:"percent,omitempty"`
+
+	// Interval is the time between the start of two rounds.
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// QPS is the number of deletes per second within a round. Deletes are not
+	// rate limited beyond the client defaults when it is unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+}
+
+// SchemaChange is a change schema churn applies to the schema of a generated CRD.
+// +kubebuilder:validation:Enum=AddOptionalProperty;LoosenMaximum;AddEnumValue;AddRequiredProperty;TightenMaximum;RemoveProperty;ChangePropertyType
+type SchemaChange string
+
+const (
+	// SchemaAddOptionalProperty adds an optional string property to the spec.
+	SchemaAddOptionalProperty SchemaChange = "AddOptionalProperty"
+	// SchemaLoosenMaximum raises the first maximum of the spec.
+	SchemaLoosenMaximum SchemaChange = "LoosenMaximum"
+	// SchemaAddEnumValue adds a value to the first enum of the spec.
+	SchemaAddEnumValue SchemaChange = "AddEnumValue"
+	// SchemaAddRequiredProperty adds a required string property to the spec.
+	// Incompatible: existing custom resources no longer validate.
+	SchemaAddRequiredProperty SchemaChange = "AddRequiredProperty"
+	// SchemaTightenMaximum halves the range of the first maximum of the spec.
+	// Incompatible: existing custom resources may no longer validate.
+	SchemaTightenMaximum SchemaChange = "TightenMaximum"
+	// SchemaRemoveProperty removes the first optional property of the spec.
+	// Incompatible: its stored values are pruned on the next read.
+	SchemaRemoveProperty SchemaChange = "RemoveProperty"
+	// SchemaChangePropertyType turns the first string property of the spec into an integer.
+	// Incompatible: stored values no longer match the schema.
+	SchemaChangePropertyType SchemaChange = "ChangePropertyType"
+)
+
+// SchemaChurnSpec enables schema churn: the schema of a share of the CRDs of a
+// steady run is updated in rounds, one revision at a time.
+type SchemaChurnSpec struct {
+	// Percent is the share of the CRDs of the run updated in every round.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// +optional
+	Percent int32 `json:"percent,omitempty"`
+
+	// Interval is the time between the start of two rounds.
+	// +kubebuilder:default="1m"
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// Changes are applied in turn, revision n of a CRD applying the n-th change
+	// of the list, wrapping around. Only the compatible changes are applied when
+	// it is unset. Incompatible changes are only applied when listed here.
+	// +optional
+	Changes []SchemaChange `json:"changes,omitempty"`
+
+	// QPS is the number of updates per second within a round. Updates are not
+	// rate limited beyond the client defaults when it is unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	QPS int32 `json:"qps,omitempty"`
+}
+
+// ConversionStrategy is how the API server converts custom resources between
+// the versions of the generated CRDs.
+// +kubebuilder:validation:Enum=None;Webhook
+type ConversionStrategy string
+
+const (
+	// ConversionNone only changes the apiVersion of custom resources, so every
+	// version shares the same schema.
+	ConversionNone ConversionStrategy = "None"
+	// ConversionWebhook converts custom resources through the conversion webhook
+	// built into the operator. Every version renames and moves the spec fields of
+	// the first version.
+	ConversionWebhook ConversionStrategy = "Webhook"
+)
+
+// ConversionWebhookSpec injects faults into the conversion webhook requests of a run.
+type ConversionWebhookSpec struct {
+	// Latency is an artificial delay added to every conversion request.
+	// +optional
+	Latency metav1.Duration `json:"latency,omitempty"`
+
+	// ErrorPercent is the share of conversion requests answered with an error.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ErrorPercent int32 `json:"errorPercent,omitempty"`
+}
+
+// VersionsSpec configures the served versions of the generated CRDs. Versions
+// are named v1alpha1, v1alpha2 and so on.
+type VersionsSpec struct {
+	// Count is the number of served versions.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +kubebuilder:default=1
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// Storage is the name of the storage version. Defaults to v1alpha1.
+	// +kubebuilder:validation:Pattern=`^v1alpha[1-9][0-9]*$`
+	// +optional
+	Storage string `json:"storage,omitempty"`
+
+	// Conversion is the conversion strategy of the generated CRDs.
+	// +kubebuilder:default=None
+	// +optional
+	Conversion ConversionStrategy `json:"conversion,omitempty"`
+
+	// Webhook injects faults into the conversion requests of a Webhook strategy.
+	// +optional
+	Webhook *ConversionWebhookSpec `json:"webhook,omitempty"`
+}
+
+// InstancesSpec configures the custom resources created for every generated CRD.
+// Their content is synthesized from the schema of the CRD.
+type InstancesSpec struct {
+	// PerCRD is the number of custom resources created for every generated CRD.
+	// +kubebuilder:validation:Minimum=1
+	PerCRD int32 `json:"perCRD"`
+
+	// Namespace is the namespace of the custom resources of namespaced CRDs.
+	// Defaults to the namespace of the ReconTest.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Version is the version the custom resources are written in. Defaults to
+	// the storage version; any other version makes every write go through conversion.
+	// +kubebuilder:validation:Pattern=`^v1alpha[1-9][0-9]*$`
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Rate controls the concurrency and rate of custom resource creation.
+	// +optional
+	Rate *RateSpec `json:"rate,omitempty"`
+}
+
+// PhaseType is what a phase of a scenario does.
+// +kubebuilder:validation:Enum=Create;Hold;Churn;UpdateSchema;CreateInstances;Delete;WaitForCondition
+type PhaseType string
+
+const (
+	// ScenarioCreate creates CRDs until the run has Count of them.
+	ScenarioCreate PhaseType = "Create"
+	// ScenarioHold keeps the run as it is for Duration.
+	ScenarioHold PhaseType = "Hold"
+	// ScenarioChurn churns the CRDs of the run for Duration.
+	ScenarioChurn PhaseType = "Churn"
+	// ScenarioUpdateSchema replaces the schema or versions of the CRDs of the run
+	// and updates every CRD to them.
+	ScenarioUpdateSchema PhaseType = "UpdateSchema"
+	// ScenarioCreateInstances fills the CRDs of the run with custom resources.
+	ScenarioCreateInstances PhaseType = "CreateInstances"
+	// ScenarioDelete deletes every CRD of the run.
+	ScenarioDelete PhaseType = "Delete"
+	// ScenarioWaitForCondition waits until a condition is True on every CRD of the run.
+	ScenarioWaitForCondition PhaseType = "WaitForCondition"
+)
+
+// PhaseTimeoutAction is what happens when a phase times out.
+// +kubebuilder:validation:Enum=Fail;Continue
+type PhaseTimeoutAction string
+
+const (
+	// PhaseTimeoutFail fails the scenario.
+	PhaseTimeoutFail PhaseTimeoutAction = "Fail"
+	// PhaseTimeoutContinue moves on to the next phase.
+	PhaseTimeoutContinue PhaseTimeoutAction = "Continue"
+)
+
+// Phase is a step of a scenario. Only the parameters of its type are used.
+type Phase struct {
+	// Name identifies the phase in status. Defaults to its type and position,
+	// e.g. Create-1.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Type is what the phase does.
+	Type PhaseType `json:"type"`
+
+	// Count is the number of CRDs a Create phase brings the run to. CRDs are
+	// only added, a lower count than the run has does not delete any.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// Duration is how long a Hold or Churn phase lasts.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Churn is the churn of a Churn phase.
+	// +optional
+	Churn *ChurnSpec `json:"churn,omitempty"`
+
+	// Schema replaces the schema of the CRDs in an UpdateSchema phase.
+	// +optional
+	Schema *SchemaSpec `json:"schema,omitempty"`
+
+	// Versions replaces the versions of the CRDs in an UpdateSchema phase.
+	// +optional
+	Versions *VersionsSpec `json:"versions,omitempty"`
+
+	// Instances are the custom resources a CreateInstances phase creates. They
+	// are also created for the CRDs added by later phases.
+	// +optional
+	Instances *InstancesSpec `json:"instances,omitempty"`
+
+	// Condition is the CRD condition a WaitForCondition phase waits for.
+	// +kubebuilder:validation:Enum=Established;NamesAccepted
+	// +kubebuilder:default=Established
+	// +optional
+	Condition string `json:"condition,omitempty"`
+
+	// Timeout bounds how long the phase may take to meet its exit criteria.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// OnTimeout is what happens when the phase times out.
+	// +kubebuilder:default=Fail
+	// +optional
+	OnTimeout PhaseTimeoutAction `json:"onTimeout,omitempty"`
+}
+
 // ReconTestSpec defines the desired state of ReconTest
 type ReconTestSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// Count is the number of CRDs generated for this run.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1000
+	// +optional
+	Count int32 `json:"count,omitempty"`
+
+	// Group is the API group of the generated CRDs.
+	// +kubebuilder:default=example.anirudh.io
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// NamePrefix is the plural name prefix of the generated CRDs. The index of
+	// each CRD is appended to it, e.g. complexrecontests1.
+	// +kubebuilder:validation:Pattern=`^[a-z]([-a-z0-9]*[a-z])?$`
+	// +kubebuilder:default=complexrecontests
+	// +optional
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// KindPrefix is the kind prefix of the generated CRDs. The index of each
+	// CRD is appended to it, e.g. ComplexRecontest1.
+	// +kubebuilder:validation:Pattern=`^[A-Z][a-zA-Z0-9]*[a-zA-Z]$`
+	// +kubebuilder:default=ComplexRecontest
+	// +optional
+	KindPrefix string `json:"kindPrefix,omitempty"`
+
+	// Scope is the scope of the generated CRDs.
+	// +kubebuilder:default=Namespaced
+	// +optional
+	Scope CRDScope `json:"scope,omitempty"`
+
+	// StartIndex is the index of the first generated CRD.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	StartIndex int32 `json:"startIndex,omitempty"`
+
+	// Schema describes the schema of the generated CRDs. The Complex preset
+	// is used when it is unset.
+	// +optional
+	Schema *SchemaSpec `json:"schema,omitempty"`
+
+	// Versions configures the served versions of the generated CRDs. A single
+	// v1alpha1 version is generated when it is unset.
+	// +optional
+	Versions *VersionsSpec `json:"versions,omitempty"`
+
+	// Rate controls the concurrency and rate of CRD creation.
+	// +optional
+	Rate *RateSpec `json:"rate,omitempty"`
+
+	// RetryPolicy decides how failed requests are handled, by error class. It
+	// applies to the CRD and custom resource creations and to churn deletions.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// Churn deletes part of the CRDs of the run periodically so they are recreated.
+	// +optional
+	Churn *ChurnSpec `json:"churn,omitempty"`
+
+	// SchemaChurn updates the schema of part of the CRDs of the run periodically.
+	// +optional
+	SchemaChurn *SchemaChurnSpec `json:"schemaChurn,omitempty"`
 
-	// Foo is an example field of ReconTest. Edit recontest_types.go to remove/update
-	Foo string `json:"foo,omitempty"`
+	// Instances creates custom resources of every generated CRD once it is established.
+	// +optional
+	Instances *InstancesSpec `json:"instances,omitempty"`
+
+	// Discovery measures how long the generated kinds take to be published by the
+	// discovery and OpenAPI endpoints.
+	// +optional
+	Discovery *DiscoverySpec `json:"discovery,omitempty"`
+
+	// Watches opens watchers on the generated kinds and measures the delivery
+	// latency of their events.
+	// +optional
+	Watches *WatchSpec `json:"watches,omitempty"`
+
+	// Lists issues LIST calls against the generated kinds and the CRDs and measures
+	// their latency and response size.
+	// +optional
+	Lists *ListSpec `json:"lists,omitempty"`
+
+	// Writes sets how the generated CRDs are written and can add a competing
+	// field manager. The CRDs are created with plain Create calls when it is unset.
+	// +optional
+	Writes *WriteSpec `json:"writes,omitempty"`
+
+	// Report writes the results of the run as JSON, CSV and JUnit XML.
+	// +optional
+	Report *ReportSpec `json:"report,omitempty"`
+
+	// Assertions are the expectations the run is checked against, continuously
+	// and once more when the ReconTest is deleted. Their verdict is the Passed
+	// condition.
+	// +optional
+	Assertions []Assertion `json:"assertions,omitempty"`
+
+	// BaselineRef compares the run against the report of an earlier run each time
+	// its report is written, and flags the metrics that regressed. It requires
+	// the report to be enabled.
+	// +optional
+	BaselineRef *BaselineRef `json:"baselineRef,omitempty"`
+
+	// Phases turn the run into a scenario whose phases run one after the other,
+	// each until its exit criteria are met. The run starts without CRDs, Count,
+	// Churn and Instances are replaced by the phases. A change of spec restarts
+	// the scenario.
+	// +optional
+	Phases []Phase `json:"phases,omitempty"`
+
+	// DriftPolicy decides what happens to generated CRDs whose live spec no
+	// longer matches the spec the run intends for them.
+	// +kubebuilder:default=Ignore
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
+	// CleanupPolicy decides what happens to the generated CRDs when the
+	// ReconTest is deleted.
+	// +kubebuilder:default=Delete
+	// +optional
+	CleanupPolicy CleanupPolicy `json:"cleanupPolicy,omitempty"`
+
+	// DryRun sends the CRD creations of the run, and the writes it would make to
+	// its existing CRDs, once per generation with dryRun=All and reports what the
+	// API server would accept and the requests the real run would make. Nothing
+	// is persisted. Phases are not supported. Turning it on for a run that
+	// already created CRDs stops its load, but its CRDs stay until the ReconTest
+	// is deleted.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// ReconTestPhase is the lifecycle phase of a ReconTest run.
+// +kubebuilder:validation:Enum=Pending;Creating;Steady;Deleting;Completed;Failed
+type ReconTestPhase string
+
+const (
+	// PhasePending means the run has been accepted but no CRD has been created yet.
+	PhasePending ReconTestPhase = "Pending"
+	// PhaseCreating means the controller is creating the CRDs of the run.
+	PhaseCreating ReconTestPhase = "Creating"
+	// PhaseSteady means every desired CRD of the run exists.
+	PhaseSteady ReconTestPhase = "Steady"
+	// PhaseDeleting means the CRDs of the run are being deleted.
+	PhaseDeleting ReconTestPhase = "Deleting"
+	// PhaseCompleted means the run has finished: its scenario ended, its dry run was sent, or
+	// every CRD and instance of a run without a continuous load is in place and measured.
+	PhaseCompleted ReconTestPhase = "Completed"
+	// PhaseFailed means the last pass over the CRDs of the run had failures, or the
+	// run was aborted.
+	PhaseFailed ReconTestPhase = "Failed"
+)
+
+// Condition types set on ReconTestStatus.
+const (
+	// ConditionReady is True when every desired CRD of the run exists.
+	ConditionReady = "Ready"
+	// ConditionDegraded is True when the last pass failed to create one or more CRDs.
+	ConditionDegraded = "Degraded"
+	// ConditionPassed is True when every assertion of the run passed, False when
+	// any failed and Unknown while some cannot be decided yet.
+	ConditionPassed = "Passed"
+	// ConditionRegressed is True when a metric of the run regressed against its
+	// baseline, False when none did and Unknown when the baseline cannot be read.
+	ConditionRegressed = "Regressed"
+)
+
+// AssertionState is the verdict of an assertion.
+// +kubebuilder:validation:Enum=Passed;Failed;Pending
+type AssertionState string
+
+const (
+	// AssertionPassed means the run meets the assertion.
+	AssertionPassed AssertionState = "Passed"
+	// AssertionFailed means the run violates the assertion.
+	AssertionFailed AssertionState = "Failed"
+	// AssertionPending means there is not enough data to decide yet.
+	AssertionPending AssertionState = "Pending"
+)
+
+// AssertionStatus is the verdict of an assertion of the run.
+type AssertionStatus struct {
+	// Name is the name of the assertion.
+	Name string `json:"name"`
+
+	// State is the verdict of the assertion.
+	State AssertionState `json:"state"`
+
+	// Value is the observed value of the metric, if any.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Message explains the verdict.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// CRDFailure records the last error returned for a generated CRD.
+type CRDFailure struct {
+	// Name is the name of the CRD.
+	Name string `json:"name"`
+
+	// Reason is the API status reason of the error, if any.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+}
+
+// LatencySummary summarises a set of latency samples.
+type LatencySummary struct {
+	// Samples is the number of samples the percentiles were computed from.
+	Samples int32 `json:"samples"`
+
+	// P50 is the median latency.
+	// +optional
+	P50 metav1.Duration `json:"p50,omitempty"`
+
+	// P90 is the 90th percentile latency.
+	// +optional
+	P90 metav1.Duration `json:"p90,omitempty"`
+
+	// P99 is the 99th percentile latency.
+	// +optional
+	P99 metav1.Duration `json:"p99,omitempty"`
+}
+
+// EstablishmentLatency summarises how long the CRDs created by a run took,
+// from their Create call, to have each condition turn True.
+type EstablishmentLatency struct {
+	// NamesAccepted is the latency until the NamesAccepted condition is True.
+	NamesAccepted LatencySummary `json:"namesAccepted"`
+
+	// Established is the latency until the Established condition is True.
+	Established LatencySummary `json:"established"`
+}
+
+// ChurnStatus reports the churn activity of a run.
+type ChurnStatus struct {
+	// Rounds is the number of churn rounds run.
+	Rounds int32 `json:"rounds"`
+
+	// Deleted is the number of CRDs deleted by churn.
+	Deleted int32 `json:"deleted"`
+
+	// LastRoundTime is the start time of the last round.
+	// +optional
+	LastRoundTime *metav1.Time `json:"lastRoundTime,omitempty"`
+
+	// DeleteToGone summarises the time from the Delete call of a churned CRD until it was gone.
+	// +optional
+	DeleteToGone *LatencySummary `json:"deleteToGone,omitempty"`
+
+	// RecreateToEstablished summarises the time from the Create call of a
+	// recreated CRD until it was established.
+	// +optional
+	RecreateToEstablished *LatencySummary `json:"recreateToEstablished,omitempty"`
+}
+
+// SchemaChurnStatus reports the schema churn activity of a run.
+type SchemaChurnStatus struct {
+	// Rounds is the number of schema churn rounds run.
+	Rounds int32 `json:"rounds"`
+
+	// Updated is the number of schema updates the API server accepted.
+	Updated int32 `json:"updated"`
+
+	// Rejected is the number of schema updates the API server rejected.
+	Rejected int32 `json:"rejected"`
+
+	// LastRoundTime is the start time of the last round.
+	// +optional
+	LastRoundTime *metav1.Time `json:"lastRoundTime,omitempty"`
+
+	// UpdateToEstablished summarises the time from the Update call of a CRD until
+	// the controller saw the updated generation with the Established condition True.
+	// The API server keeps Established True across updates, so this is the time for
+	// the Update call to return or for its watch event to reach the controller,
+	// whichever comes first, not a time to re-establish the CRD.
+	// +optional
+	UpdateToEstablished *LatencySummary `json:"updateToEstablished,omitempty"`
+
+	// UpdateToPublished summarises the time from the Update call of a CRD until
+	// the OpenAPI v3 document of its storage version showed the new revision.
+	// +optional
+	UpdateToPublished *LatencySummary `json:"updateToPublished,omitempty"`
+
+	// PublishTimeouts is the number of updates not published within the
+	// publication timeout.
+	PublishTimeouts int32 `json:"publishTimeouts"`
+}
+
+// InstanceStatus reports the custom resources created for the CRDs of a run.
+type InstanceStatus struct {
+	// Desired is the number of custom resources the run should create.
+	Desired int32 `json:"desired"`
+
+	// Created is the number of custom resources known to exist.
+	Created int32 `json:"created"`
+
+	// Failed is the number of custom resources the last pass failed to create.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// CreateLatency summarises the duration of the Create calls of the custom resources.
+	// +optional
+	CreateLatency *LatencySummary `json:"createLatency,omitempty"`
+}
+
+// EndpointDiscoveryStatus reports the publication latencies measured on one endpoint.
+type EndpointDiscoveryStatus struct {
+	// Endpoint is the polled endpoint.
+	Endpoint DiscoveryEndpoint `json:"endpoint"`
+
+	// Unsupported is set when the API server does not serve the endpoint.
+	// +optional
+	Unsupported bool `json:"unsupported,omitempty"`
+
+	// Appear summarises the time from the Create call of a CRD until the
+	// endpoint published its kind.
+	// +optional
+	Appear *LatencySummary `json:"appear,omitempty"`
+
+	// Disappear summarises the time from the Delete call of a CRD until the
+	// endpoint no longer published its kind.
+	// +optional
+	Disappear *LatencySummary `json:"disappear,omitempty"`
+
+	// Pending is the number of kinds still awaited on the endpoint.
+	// +optional
+	Pending int32 `json:"pending,omitempty"`
+
+	// TimedOut is the number of kinds that did not appear or disappear within the timeout.
+	// +optional
+	TimedOut int32 `json:"timedOut,omitempty"`
+}
+
+// DiscoveryStatus reports how long the generated kinds took to be published by
+// the discovery and OpenAPI endpoints.
+type DiscoveryStatus struct {
+	// Endpoints holds the measurements of every polled endpoint.
+	// +listType=map
+	// +listMapKey=endpoint
+	// +optional
+	Endpoints []EndpointDiscoveryStatus `json:"endpoints,omitempty"`
+}
+
+// WatchStatus reports the watchers opened on the kinds of a run.
+type WatchStatus struct {
+	// Watchers is the number of watchers currently open.
+	Watchers int32 `json:"watchers"`
+
+	// Events is the number of events delivered to the watchers.
+	// +optional
+	Events int64 `json:"events,omitempty"`
+
+	// DeliveryLatency summarises the time from the write of a probe custom resource
+	// until a watcher saw its event.
+	// +optional
+	DeliveryLatency *LatencySummary `json:"deliveryLatency,omitempty"`
+
+	// Restarts is the number of watches the API server closed and that were reopened.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// Expired is the number of watches that ended because their resource version
+	// was too old, forcing a relist.
+	// +optional
+	Expired int32 `json:"expired,omitempty"`
+
+	// Failed is the number of watches that could not be opened or ended with another error.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// ListLatencyBucket summarises the list requests made while the run had a number of CRDs.
+type ListLatencyBucket struct {
+	// CRDs is the number of CRDs of the run when the requests were made, rounded
+	// down to a tenth of the desired count.
+	CRDs int32 `json:"crds"`
+
+	// Latency summarises the latency of the requests.
+	Latency LatencySummary `json:"latency"`
+}
+
+// ListTargetStatus reports the list calls made against one target.
+type ListTargetStatus struct {
+	// Target is the listed collection.
+	Target ListTarget `json:"target"`
+
+	// Lists is the number of complete lists, every page read.
+	// +optional
+	Lists int64 `json:"lists,omitempty"`
+
+	// Requests is the number of list requests, one per page.
+	// +optional
+	Requests int64 `json:"requests,omitempty"`
+
+	// Errors is the number of list requests that failed.
+	// +optional
+	Errors int64 `json:"errors,omitempty"`
+
+	// Latency summarises the latency of the list requests.
+	// +optional
+	Latency *LatencySummary `json:"latency,omitempty"`
+
+	// ResponseBytesP50 is the median size of the responses, in bytes.
+	// +optional
+	ResponseBytesP50 int64 `json:"responseBytesP50,omitempty"`
+
+	// ResponseBytesMax is the size of the largest response, in bytes.
+	// +optional
+	ResponseBytesMax int64 `json:"responseBytesMax,omitempty"`
+
+	// ByCRDs summarises the latency of the list requests by the number of CRDs of
+	// the run at the time, showing how reads slow down as CRDs are added.
+	// +optional
+	ByCRDs []ListLatencyBucket `json:"byCRDs,omitempty"`
+}
+
+// CompetitorStatus reports the applies of the competing field manager of a run.
+type CompetitorStatus struct {
+	// Rounds is the number of rounds of the competitor.
+	Rounds int32 `json:"rounds"`
+
+	// LastRoundTime is the start time of the last round.
+	// +optional
+	LastRoundTime *metav1.Time `json:"lastRoundTime,omitempty"`
+
+	// Applies is the number of applies of the competitor.
+	Applies int64 `json:"applies"`
+
+	// Conflicts is the number of applies of the competitor that failed with a conflict.
+	Conflicts int64 `json:"conflicts"`
+
+	// Latency summarises the latency of the applies of the competitor.
+	// +optional
+	Latency *LatencySummary `json:"latency,omitempty"`
+}
+
+// WriteStatus reports the CRD writes of a run and the managed fields they leave.
+type WriteStatus struct {
+	// Mode is the mode the CRDs are written in.
+	Mode WriteMode `json:"mode"`
+
+	// Writes is the number of CRD writes of the controller, creates included.
+	Writes int64 `json:"writes"`
+
+	// Conflicts is the number of CRD writes of the controller that failed with a conflict.
+	Conflicts int64 `json:"conflicts"`
+
+	// Latency summarises the latency of the CRD writes of the controller.
+	// +optional
+	Latency *LatencySummary `json:"latency,omitempty"`
+
+	// ManagedFieldsEntriesMax is the largest number of managedFields entries of a CRD
+	// of the run, as of the last pass.
+	// +optional
+	ManagedFieldsEntriesMax int32 `json:"managedFieldsEntriesMax,omitempty"`
+
+	// ManagedFieldsBytesP50 is the median size of the managedFields of the CRDs of the
+	// run, as of the last pass.
+	// +optional
+	ManagedFieldsBytesP50 int64 `json:"managedFieldsBytesP50,omitempty"`
+
+	// ManagedFieldsBytesMax is the largest size of the managedFields of a CRD of the
+	// run, as of the last pass.
+	// +optional
+	ManagedFieldsBytesMax int64 `json:"managedFieldsBytesMax,omitempty"`
+
+	// Competitor reports the applies of the competing field manager.
+	// +optional
+	Competitor *CompetitorStatus `json:"competitor,omitempty"`
+}
+
+// DryRunStatus reports the outcome of the dry run of a run.
+type DryRunStatus struct {
+	// ObservedGeneration is the generation of the ReconTest the dry run was made for.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Accepted is the number of CRD writes the API server accepted.
+	Accepted int32 `json:"accepted"`
+
+	// Rejected is the number of CRD writes the API server rejected, by
+	// admission or validation. Their errors are in status.failedCRDs.
+	Rejected int32 `json:"rejected"`
+
+	// Existing is the number of CRDs of the run that already exist. They are
+	// not created again, but the writes the real run would make to them are
+	// sent and counted in CRDUpdates.
+	Existing int32 `json:"existing"`
+
+	// Latency summarises the latency of the dry-run requests.
+	// +optional
+	Latency *LatencySummary `json:"latency,omitempty"`
+
+	// CRDCreates is the number of CRD creations the real run would make.
+	CRDCreates int32 `json:"crdCreates"`
+
+	// CRDUpdates is the number of writes to existing CRDs the real run would
+	// make: the updates or applies of the Update and ServerSideApply write
+	// modes, or the repair of drifted CRDs under the Update or Recreate drift
+	// policy.
+	// +optional
+	CRDUpdates int32 `json:"crdUpdates,omitempty"`
+
+	// CRDBytes is the size of the CRDs the real run would send, in bytes.
+	CRDBytes int64 `json:"crdBytes"`
+
+	// InstanceCreates is the number of custom resource creations the real run
+	// would make once its CRDs are established. They cannot be sent in a dry run,
+	// as their kinds do not exist.
+	// +optional
+	InstanceCreates int64 `json:"instanceCreates,omitempty"`
+
+	// InstanceCreatesEstimated is true when InstanceCreates is computed from
+	// spec.instances rather than sent: none of the custom resources was seen
+	// by admission or validation.
+	// +optional
+	InstanceCreatesEstimated bool `json:"instanceCreatesEstimated,omitempty"`
+
+	// CompletionTime is when the dry run finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ListStatus reports the list calls of a run.
+type ListStatus struct {
+	// Targets holds the measurements of every listed target.
+	// +listType=map
+	// +listMapKey=target
+	// +optional
+	Targets []ListTargetStatus `json:"targets,omitempty"`
+}
+
+// ReportStatus reports the last report written for a run.
+type ReportStatus struct {
+	// LastWriteTime is the time the report was last written.
+	// +optional
+	LastWriteTime *metav1.Time `json:"lastWriteTime,omitempty"`
+
+	// ConfigMap is the name of the ConfigMap the report was written to.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// FailedChecks is the number of checks of the last report that failed.
+	// +optional
+	FailedChecks int32 `json:"failedChecks,omitempty"`
+}
+
+// MetricComparison compares a metric of the run with its baseline.
+type MetricComparison struct {
+	// Metric is the name of the metric, e.g. EstablishedLatencyP99.
+	Metric string `json:"metric"`
+
+	// Baseline is the value of the metric in the baseline run.
+	Baseline string `json:"baseline"`
+
+	// Current is the value of the metric in this run.
+	Current string `json:"current"`
+
+	// Delta is the change of the metric relative to the baseline.
+	Delta string `json:"delta"`
+
+	// PValue is the probability of a change at least this large between runs
+	// that perform the same, for metrics that are tested for significance.
+	// +optional
+	PValue string `json:"pValue,omitempty"`
+
+	// Regressed is set when the metric got worse by more than the tolerance
+	// and, where tested, the change is significant.
+	// +optional
+	Regressed bool `json:"regressed,omitempty"`
+}
+
+// BaselineStatus reports the comparison of the run with its baseline.
+type BaselineStatus struct {
+	// ConfigMap is the name of the ConfigMap the baseline report was read from.
+	ConfigMap string `json:"configMap"`
+
+	// Run is the namespace and name of the baseline run.
+	// +optional
+	Run string `json:"run,omitempty"`
+
+	// Regressions is the number of metrics that regressed.
+	// +optional
+	Regressions int32 `json:"regressions,omitempty"`
+
+	// Metrics compares every metric of the run with the baseline.
+	// +listType=map
+	// +listMapKey=metric
+	// +optional
+	Metrics []MetricComparison `json:"metrics,omitempty"`
+
+	// Error is set when the baseline could not be compared against.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorClassStatus counts the failed requests of a run with errors of one class.
+type ErrorClassStatus struct {
+	// Class is the error class.
+	Class ErrorClass `json:"class"`
+
+	// Errors is the number of requests that failed with an error of the class.
+	Errors int64 `json:"errors"`
+
+	// Retries is the number of those requests that were retried.
+	// +optional
+	Retries int64 `json:"retries,omitempty"`
+
+	// GivenUp is the number of those requests that were not retried.
+	// +optional
+	GivenUp int64 `json:"givenUp,omitempty"`
+}
+
+// AbortStatus records the error that aborted a run.
+type AbortStatus struct {
+	// Class is the class of the error.
+	Class ErrorClass `json:"class"`
+
+	// Operation is the operation whose request failed.
+	Operation string `json:"operation"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+
+	// Time is when the run was aborted.
+	Time metav1.Time `json:"time"`
+
+	// ObservedGeneration is the generation of the ReconTest that was aborted.
+	// The run resumes once its spec changes.
+	ObservedGeneration int64 `json:"observedGeneration"`
+}
+
+// ErrorStatus reports the failed requests of a run by error class.
+type ErrorStatus struct {
+	// Classes counts the failed requests of every error class seen.
+	// +listType=map
+	// +listMapKey=class
+	// +optional
+	Classes []ErrorClassStatus `json:"classes,omitempty"`
+
+	// Aborted is set when an error aborted the run.
+	// +optional
+	Aborted *AbortStatus `json:"aborted,omitempty"`
+}
+
+// PhaseState is the state of a phase of a scenario.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;TimedOut;Failed
+type PhaseState string
+
+const (
+	// PhaseStatePending means the phase has not started yet.
+	PhaseStatePending PhaseState = "Pending"
+	// PhaseStateRunning means the phase is running.
+	PhaseStateRunning PhaseState = "Running"
+	// PhaseStateSucceeded means the phase met its exit criteria.
+	PhaseStateSucceeded PhaseState = "Succeeded"
+	// PhaseStateTimedOut means the phase timed out and the scenario moved on.
+	PhaseStateTimedOut PhaseState = "TimedOut"
+	// PhaseStateFailed means the phase timed out and failed the scenario.
+	PhaseStateFailed PhaseState = "Failed"
+)
+
+// PhaseStatus reports a phase of a scenario.
+type PhaseStatus struct {
+	// Name is the name of the phase.
+	Name string `json:"name"`
+
+	// Type is the type of the phase.
+	Type PhaseType `json:"type"`
+
+	// State is the state of the phase.
+	State PhaseState `json:"state"`
+
+	// StartTime is when the phase started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the phase ended.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message explains the state of the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ScenarioStatus reports the progress of the scenario of a run.
+type ScenarioStatus struct {
+	// ObservedGeneration is the generation of the ReconTest the scenario runs.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// Current is the name of the running phase, empty once the scenario ended.
+	// +optional
+	Current string `json:"current,omitempty"`
+
+	// Phases reports every phase of the scenario, in order.
+	// +optional
+	Phases []PhaseStatus `json:"phases,omitempty"`
+
+	// CompletionTime is when the scenario ended.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 }
 
 // ReconTestStatus defines the observed state of ReconTest
 type ReconTestStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ObservedGeneration is the generation of the ReconTest last acted upon.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Phase is the lifecycle phase of the run.
+	// +optional
+	Phase ReconTestPhase `json:"phase,omitempty"`
+
+	// Desired is the number of CRDs the run should generate.
+	// +optional
+	Desired int32 `json:"desired,omitempty"`
+
+	// Created is the number of CRDs created by the last pass.
+	// +optional
+	Created int32 `json:"created,omitempty"`
+
+	// Existing is the number of CRDs that already existed during the last pass.
+	// +optional
+	Existing int32 `json:"existing,omitempty"`
+
+	// Drifted is the number of CRDs whose live spec did not match their
+	// intended spec during the last pass.
+	// +optional
+	Drifted int32 `json:"drifted,omitempty"`
+
+	// Failed is the number of CRDs the last pass failed to create or repair.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// FailedCRDs holds the last error of each CRD that failed during the last
+	// pass, capped to the first 100 CRDs.
+	// +optional
+	FailedCRDs []CRDFailure `json:"failedCRDs,omitempty"`
+
+	// EstablishmentLatency summarises the establishment latency of the CRDs
+	// created by this controller process for the run.
+	// +optional
+	EstablishmentLatency *EstablishmentLatency `json:"establishmentLatency,omitempty"`
+
+	// Scenario reports the progress of the scenario of the run.
+	// +optional
+	Scenario *ScenarioStatus `json:"scenario,omitempty"`
+
+	// Churn reports the churn activity of the run.
+	// +optional
+	Churn *ChurnStatus `json:"churn,omitempty"`
+
+	// SchemaChurn reports the schema churn activity of the run.
+	// +optional
+	SchemaChurn *SchemaChurnStatus `json:"schemaChurn,omitempty"`
+
+	// Instances reports the custom resources created for the CRDs of the run.
+	// +optional
+	Instances *InstanceStatus `json:"instances,omitempty"`
+
+	// Discovery reports how long the generated kinds took to be published by
+	// the discovery and OpenAPI endpoints.
+	// +optional
+	Discovery *DiscoveryStatus `json:"discovery,omitempty"`
+
+	// Watches reports the watchers opened on the generated kinds.
+	// +optional
+	Watches *WatchStatus `json:"watches,omitempty"`
+
+	// Lists reports the latency and response size of the list calls of the run.
+	// +optional
+	Lists *ListStatus `json:"lists,omitempty"`
+
+	// Writes reports the CRD writes of the run and the managed fields they leave.
+	// +optional
+	Writes *WriteStatus `json:"writes,omitempty"`
+
+	// DryRun reports the outcome of the dry run of the run.
+	// +optional
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+
+	// Errors reports the failed requests of the run by error class.
+	// +optional
+	Errors *ErrorStatus `json:"errors,omitempty"`
+
+	// Report reports the last report written for the run.
+	// +optional
+	Report *ReportStatus `json:"report,omitempty"`
+
+	// Assertions holds the verdict of every assertion of the run.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Assertions []AssertionStatus `json:"assertions,omitempty"`
+
+	// Baseline reports the comparison of the run with its baseline.
+	// +optional
+	Baseline *BaselineStatus `json:"baseline,omitempty"`
+
+	// Conditions are the standard conditions of the run, such as Ready, Degraded, Passed and Regressed.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Scenario",type=string,JSONPath=`.status.scenario.current`,priority=1
+//+kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desired`
+//+kubebuilder:printcolumn:name="Created",type=integer,JSONPath=`.status.created`
+//+kubebuilder:printcolumn:name="Existing",type=integer,JSONPath=`.status.existing`
+//+kubebuilder:printcolumn:name="Drifted",type=integer,JSONPath=`.status.drifted`,priority=1
+//+kubebuilder:printcolumn:name="Failed",type=integer,JSONPath=`.status.failed`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Passed",type=string,JSONPath=`.status.conditions[?(@.type=="Passed")].status`
+//+kubebuilder:printcolumn:name="Regressed",type=string,JSONPath=`.status.conditions[?(@.type=="Regressed")].status`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // ReconTest is the Schema for the recontests API
 type ReconTest struct {
@@ -22,16 +22,664 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AbortStatus) DeepCopyInto(out *AbortStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AbortStatus.
+func (in *AbortStatus) DeepCopy() *AbortStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AbortStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Assertion) DeepCopyInto(out *Assertion) {
+	*out = *in
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxPercent != nil {
+		in, out := &in.MaxPercent, &out.MaxPercent
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Assertion.
+func (in *Assertion) DeepCopy() *Assertion {
+	if in == nil {
+		return nil
+	}
+	out := new(Assertion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssertionStatus) DeepCopyInto(out *AssertionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssertionStatus.
+func (in *AssertionStatus) DeepCopy() *AssertionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AssertionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaselineRef) DeepCopyInto(out *BaselineRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineRef.
+func (in *BaselineRef) DeepCopy() *BaselineRef {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BaselineStatus) DeepCopyInto(out *BaselineStatus) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]MetricComparison, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BaselineStatus.
+func (in *BaselineStatus) DeepCopy() *BaselineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CELSpec) DeepCopyInto(out *CELSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CELSpec.
+func (in *CELSpec) DeepCopy() *CELSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CELSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CRDFailure) DeepCopyInto(out *CRDFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRDFailure.
+func (in *CRDFailure) DeepCopy() *CRDFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(CRDFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChurnSpec) DeepCopyInto(out *ChurnSpec) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChurnSpec.
+func (in *ChurnSpec) DeepCopy() *ChurnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ChurnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChurnStatus) DeepCopyInto(out *ChurnStatus) {
+	*out = *in
+	if in.LastRoundTime != nil {
+		in, out := &in.LastRoundTime, &out.LastRoundTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DeleteToGone != nil {
+		in, out := &in.DeleteToGone, &out.DeleteToGone
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.RecreateToEstablished != nil {
+		in, out := &in.RecreateToEstablished, &out.RecreateToEstablished
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChurnStatus.
+func (in *ChurnStatus) DeepCopy() *ChurnStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ChurnStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompetitorSpec) DeepCopyInto(out *CompetitorSpec) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompetitorSpec.
+func (in *CompetitorSpec) DeepCopy() *CompetitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CompetitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompetitorStatus) DeepCopyInto(out *CompetitorStatus) {
+	*out = *in
+	if in.LastRoundTime != nil {
+		in, out := &in.LastRoundTime, &out.LastRoundTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompetitorStatus.
+func (in *CompetitorStatus) DeepCopy() *CompetitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CompetitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConversionWebhookSpec) DeepCopyInto(out *ConversionWebhookSpec) {
+	*out = *in
+	out.Latency = in.Latency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConversionWebhookSpec.
+func (in *ConversionWebhookSpec) DeepCopy() *ConversionWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConversionWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoverySpec) DeepCopyInto(out *DiscoverySpec) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]DiscoveryEndpoint, len(*in))
+		copy(*out, *in)
+	}
+	out.Interval = in.Interval
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoverySpec.
+func (in *DiscoverySpec) DeepCopy() *DiscoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiscoveryStatus) DeepCopyInto(out *DiscoveryStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]EndpointDiscoveryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiscoveryStatus.
+func (in *DiscoveryStatus) DeepCopy() *DiscoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DiscoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EndpointDiscoveryStatus) DeepCopyInto(out *EndpointDiscoveryStatus) {
+	*out = *in
+	if in.Appear != nil {
+		in, out := &in.Appear, &out.Appear
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.Disappear != nil {
+		in, out := &in.Disappear, &out.Disappear
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EndpointDiscoveryStatus.
+func (in *EndpointDiscoveryStatus) DeepCopy() *EndpointDiscoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EndpointDiscoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorClassStatus) DeepCopyInto(out *ErrorClassStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorClassStatus.
+func (in *ErrorClassStatus) DeepCopy() *ErrorClassStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorClassStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorStatus) DeepCopyInto(out *ErrorStatus) {
+	*out = *in
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]ErrorClassStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aborted != nil {
+		in, out := &in.Aborted, &out.Aborted
+		*out = new(AbortStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorStatus.
+func (in *ErrorStatus) DeepCopy() *ErrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EstablishmentLatency) DeepCopyInto(out *EstablishmentLatency) {
+	*out = *in
+	out.NamesAccepted = in.NamesAccepted
+	out.Established = in.Established
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EstablishmentLatency.
+func (in *EstablishmentLatency) DeepCopy() *EstablishmentLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(EstablishmentLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceStatus) DeepCopyInto(out *InstanceStatus) {
+	*out = *in
+	if in.CreateLatency != nil {
+		in, out := &in.CreateLatency, &out.CreateLatency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceStatus.
+func (in *InstanceStatus) DeepCopy() *InstanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstancesSpec) DeepCopyInto(out *InstancesSpec) {
+	*out = *in
+	if in.Rate != nil {
+		in, out := &in.Rate, &out.Rate
+		*out = new(RateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstancesSpec.
+func (in *InstancesSpec) DeepCopy() *InstancesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstancesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LatencySummary) DeepCopyInto(out *LatencySummary) {
+	*out = *in
+	out.P50 = in.P50
+	out.P90 = in.P90
+	out.P99 = in.P99
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LatencySummary.
+func (in *LatencySummary) DeepCopy() *LatencySummary {
+	if in == nil {
+		return nil
+	}
+	out := new(LatencySummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListLatencyBucket) DeepCopyInto(out *ListLatencyBucket) {
+	*out = *in
+	out.Latency = in.Latency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListLatencyBucket.
+func (in *ListLatencyBucket) DeepCopy() *ListLatencyBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(ListLatencyBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListSpec) DeepCopyInto(out *ListSpec) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ListTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.PageSize != nil {
+		in, out := &in.PageSize, &out.PageSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rate != nil {
+		in, out := &in.Rate, &out.Rate
+		*out = new(RateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListSpec.
+func (in *ListSpec) DeepCopy() *ListSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ListSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListStatus) DeepCopyInto(out *ListStatus) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ListTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListStatus.
+func (in *ListStatus) DeepCopy() *ListStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ListStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ListTargetStatus) DeepCopyInto(out *ListTargetStatus) {
+	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.ByCRDs != nil {
+		in, out := &in.ByCRDs, &out.ByCRDs
+		*out = make([]ListLatencyBucket, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ListTargetStatus.
+func (in *ListTargetStatus) DeepCopy() *ListTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ListTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricComparison) DeepCopyInto(out *MetricComparison) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricComparison.
+func (in *MetricComparison) DeepCopy() *MetricComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Phase) DeepCopyInto(out *Phase) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Churn != nil {
+		in, out := &in.Churn, &out.Churn
+		*out = new(ChurnSpec)
+		**out = **in
+	}
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(SchemaSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = new(VersionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(InstancesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Phase.
+func (in *Phase) DeepCopy() *Phase {
+	if in == nil {
+		return nil
+	}
+	out := new(Phase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseStatus) DeepCopyInto(out *PhaseStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseStatus.
+func (in *PhaseStatus) DeepCopy() *PhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RampSpec) DeepCopyInto(out *RampSpec) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RampSpec.
+func (in *RampSpec) DeepCopy() *RampSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RampSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateSpec) DeepCopyInto(out *RateSpec) {
+	*out = *in
+	if in.RampUp != nil {
+		in, out := &in.RampUp, &out.RampUp
+		*out = new(RampSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateSpec.
+func (in *RateSpec) DeepCopy() *RateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconTest) DeepCopyInto(out *ReconTest) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconTest.
@@ -87,6 +735,85 @@ func (in *ReconTestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconTestSpec) DeepCopyInto(out *ReconTestSpec) {
 	*out = *in
+	if in.Schema != nil {
+		in, out := &in.Schema, &out.Schema
+		*out = new(SchemaSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = new(VersionsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rate != nil {
+		in, out := &in.Rate, &out.Rate
+		*out = new(RateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Churn != nil {
+		in, out := &in.Churn, &out.Churn
+		*out = new(ChurnSpec)
+		**out = **in
+	}
+	if in.SchemaChurn != nil {
+		in, out := &in.SchemaChurn, &out.SchemaChurn
+		*out = new(SchemaChurnSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(InstancesSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Discovery != nil {
+		in, out := &in.Discovery, &out.Discovery
+		*out = new(DiscoverySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Watches != nil {
+		in, out := &in.Watches, &out.Watches
+		*out = new(WatchSpec)
+		**out = **in
+	}
+	if in.Lists != nil {
+		in, out := &in.Lists, &out.Lists
+		*out = new(ListSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Writes != nil {
+		in, out := &in.Writes, &out.Writes
+		*out = new(WriteSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(ReportSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]Assertion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BaselineRef != nil {
+		in, out := &in.BaselineRef, &out.BaselineRef
+		*out = new(BaselineRef)
+		**out = **in
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]Phase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconTestSpec.
@@ -102,6 +829,88 @@ func (in *ReconTestSpec) DeepCopy() *ReconTestSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReconTestStatus) DeepCopyInto(out *ReconTestStatus) {
 	*out = *in
+	if in.FailedCRDs != nil {
+		in, out := &in.FailedCRDs, &out.FailedCRDs
+		*out = make([]CRDFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.EstablishmentLatency != nil {
+		in, out := &in.EstablishmentLatency, &out.EstablishmentLatency
+		*out = new(EstablishmentLatency)
+		**out = **in
+	}
+	if in.Scenario != nil {
+		in, out := &in.Scenario, &out.Scenario
+		*out = new(ScenarioStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Churn != nil {
+		in, out := &in.Churn, &out.Churn
+		*out = new(ChurnStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SchemaChurn != nil {
+		in, out := &in.SchemaChurn, &out.SchemaChurn
+		*out = new(SchemaChurnStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(InstanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Discovery != nil {
+		in, out := &in.Discovery, &out.Discovery
+		*out = new(DiscoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Watches != nil {
+		in, out := &in.Watches, &out.Watches
+		*out = new(WatchStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lists != nil {
+		in, out := &in.Lists, &out.Lists
+		*out = new(ListStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Writes != nil {
+		in, out := &in.Writes, &out.Writes
+		*out = new(WriteStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = new(ErrorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(ReportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Assertions != nil {
+		in, out := &in.Assertions, &out.Assertions
+		*out = make([]AssertionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Baseline != nil {
+		in, out := &in.Baseline, &out.Baseline
+		*out = new(BaselineStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconTestStatus.
@@ -113,3 +922,283 @@ func (in *ReconTestStatus) DeepCopy() *ReconTestStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportSpec) DeepCopyInto(out *ReportSpec) {
+	*out = *in
+	if in.Formats != nil {
+		in, out := &in.Formats, &out.Formats
+		*out = make([]ReportFormat, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]ReportDestination, len(*in))
+		copy(*out, *in)
+	}
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportSpec.
+func (in *ReportSpec) DeepCopy() *ReportSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReportStatus) DeepCopyInto(out *ReportStatus) {
+	*out = *in
+	if in.LastWriteTime != nil {
+		in, out := &in.LastWriteTime, &out.LastWriteTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReportStatus.
+func (in *ReportStatus) DeepCopy() *ReportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]RetryRule, len(*in))
+		copy(*out, *in)
+	}
+	out.RequeueAfter = in.RequeueAfter
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryRule) DeepCopyInto(out *RetryRule) {
+	*out = *in
+	out.InitialBackoff = in.InitialBackoff
+	out.MaxBackoff = in.MaxBackoff
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryRule.
+func (in *RetryRule) DeepCopy() *RetryRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScenarioStatus) DeepCopyInto(out *ScenarioStatus) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]PhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScenarioStatus.
+func (in *ScenarioStatus) DeepCopy() *ScenarioStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScenarioStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaChurnSpec) DeepCopyInto(out *SchemaChurnSpec) {
+	*out = *in
+	out.Interval = in.Interval
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]SchemaChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaChurnSpec.
+func (in *SchemaChurnSpec) DeepCopy() *SchemaChurnSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaChurnSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaChurnStatus) DeepCopyInto(out *SchemaChurnStatus) {
+	*out = *in
+	if in.LastRoundTime != nil {
+		in, out := &in.LastRoundTime, &out.LastRoundTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UpdateToEstablished != nil {
+		in, out := &in.UpdateToEstablished, &out.UpdateToEstablished
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.UpdateToPublished != nil {
+		in, out := &in.UpdateToPublished, &out.UpdateToPublished
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaChurnStatus.
+func (in *SchemaChurnStatus) DeepCopy() *SchemaChurnStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaChurnStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaSpec) DeepCopyInto(out *SchemaSpec) {
+	*out = *in
+	if in.CEL != nil {
+		in, out := &in.CEL, &out.CEL
+		*out = new(CELSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaSpec.
+func (in *SchemaSpec) DeepCopy() *SchemaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VersionsSpec) DeepCopyInto(out *VersionsSpec) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(ConversionWebhookSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VersionsSpec.
+func (in *VersionsSpec) DeepCopy() *VersionsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VersionsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WatchSpec) DeepCopyInto(out *WatchSpec) {
+	*out = *in
+	out.ProbeInterval = in.ProbeInterval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WatchSpec.
+func (in *WatchSpec) DeepCopy() *WatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WatchStatus) DeepCopyInto(out *WatchStatus) {
+	*out = *in
+	if in.DeliveryLatency != nil {
+		in, out := &in.DeliveryLatency, &out.DeliveryLatency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WatchStatus.
+func (in *WatchStatus) DeepCopy() *WatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteSpec) DeepCopyInto(out *WriteSpec) {
+	*out = *in
+	if in.Competitor != nil {
+		in, out := &in.Competitor, &out.Competitor
+		*out = new(CompetitorSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteSpec.
+func (in *WriteSpec) DeepCopy() *WriteSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WriteStatus) DeepCopyInto(out *WriteStatus) {
+	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(LatencySummary)
+		**out = **in
+	}
+	if in.Competitor != nil {
+		in, out := &in.Competitor, &out.Competitor
+		*out = new(CompetitorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WriteStatus.
+func (in *WriteStatus) DeepCopy() *WriteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WriteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
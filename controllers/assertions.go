@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/slo"
+)
+
+// evaluateAssertions returns the verdict of every assertion of a run on what the trackers
+// measured so far. final marks the end of the run, when pending assertions fail.
+func (r *ReconTestReconciler) evaluateAssertions(reconTest *examplev1alpha1.ReconTest,
+	spec examplev1alpha1.ReconTestSpec, final bool) []examplev1alpha1.AssertionStatus {
+	obs := slo.Observations{
+		InstanceCreate: r.instances.samples(reconTest.UID),
+		Desired:        int(spec.Count),
+		Now:            time.Now(),
+		Final:          final,
+	}
+	obs.NamesAccepted, obs.Established = r.establishment.samples(reconTest.UID)
+	obs.Requests, obs.Errors = r.requests.count(reconTest.UID)
+
+	// Churned CRDs are established again, only the first establishment of each counts
+	established := map[string]time.Time{}
+	for _, timing := range r.establishment.timings(reconTest.UID) {
+		if obs.FirstCreate.IsZero() || timing.Created.Before(obs.FirstCreate) {
+			obs.FirstCreate = timing.Created
+		}
+		if timing.EstablishedSeconds == nil {
+			continue
+		}
+		at := timing.Created.Add(time.Duration(*timing.EstablishedSeconds * float64(time.Second)))
+		if first, ok := established[timing.Name]; !ok || at.Before(first) {
+			established[timing.Name] = at
+		}
+	}
+	for _, at := range established {
+		obs.EstablishedAt = append(obs.EstablishedAt, at)
+	}
+
+	results := slo.Evaluate(spec.Assertions, obs)
+	owner := reconTest.Namespace + "/" + reconTest.Name
+	for _, result := range results {
+		verdict := -1.0
+		switch result.State {
+		case examplev1alpha1.AssertionPassed:
+			verdict = 1
+		case examplev1alpha1.AssertionFailed:
+			verdict = 0
+		}
+		assertionPassed.WithLabelValues(owner, result.Name).Set(verdict)
+	}
+	return results
+}
+
+// applyVerdict records the verdict of the assertions of a run in status and sets its Passed
+// condition, or removes both when the run has no assertions
+func applyVerdict(status *examplev1alpha1.ReconTestStatus, results []examplev1alpha1.AssertionStatus,
+	generation int64) {
+	status.Assertions = results
+	if len(results) == 0 {
+		meta.RemoveStatusCondition(&status.Conditions, examplev1alpha1.ConditionPassed)
+		return
+	}
+
+	conditionStatus, reason, message := slo.Verdict(results)
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               examplev1alpha1.ConditionPassed,
+		Status:             conditionStatus,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
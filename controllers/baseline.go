@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/compare"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+)
+
+// compareBaseline compares the report of a run with the report of its baseline run. A baseline
+// that cannot be read is recorded in the returned status rather than failing the report.
+func (r *ReconTestReconciler) compareBaseline(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	ref *examplev1alpha1.BaselineRef, runReport *report.Report) *examplev1alpha1.BaselineStatus {
+	owner := reconTest.Namespace + "/" + reconTest.Name
+	status := &examplev1alpha1.BaselineStatus{ConfigMap: ref.ConfigMapName}
+	baseline, err := r.readBaseline(ctx, reconTest.Namespace, ref.ConfigMapName)
+	if err != nil {
+		status.Error = err.Error()
+		baselineRegressions.WithLabelValues(owner).Set(-1)
+		return status
+	}
+
+	result := compare.Compare(baseline, runReport, compare.Options{
+		TolerancePercent: float64(ref.TolerancePercent),
+		Alpha:            1 - float64(ref.Confidence)/100,
+	})
+	status.Run = result.Baseline.Name
+	status.Regressions = int32(result.Regressions)
+	for _, metric := range result.Metrics {
+		baselineValue, current, delta, pValue := metric.Values()
+		status.Metrics = append(status.Metrics, examplev1alpha1.MetricComparison{
+			Metric:    metric.Name,
+			Baseline:  baselineValue,
+			Current:   current,
+			Delta:     delta,
+			PValue:    pValue,
+			Regressed: metric.Regressed,
+		})
+	}
+	baselineRegressions.WithLabelValues(owner).Set(float64(result.Regressions))
+	return status
+}
+
+// readBaseline reads the JSON report held by a ConfigMap, written plain or gzipped by the
+// report of the baseline run
+func (r *ReconTestReconciler) readBaseline(ctx context.Context, namespace, name string) (*report.Report, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := r.apiReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, configMap); err != nil {
+		return nil, fmt.Errorf("reading baseline: %w", err)
+	}
+	if raw, ok := configMap.Data[report.JSONFile]; ok {
+		return report.Read(strings.NewReader(raw))
+	}
+	if raw, ok := configMap.BinaryData[report.JSONFile+".gz"]; ok {
+		return report.Read(bytes.NewReader(raw))
+	}
+	return nil, fmt.Errorf("ConfigMap %s holds no %s, the baseline run must write its report as JSON",
+		name, report.JSONFile)
+}
+
+// applyBaseline records the comparison of a run with its baseline in status and sets its
+// Regressed condition, or removes both when the run has no baseline
+func applyBaseline(status *examplev1alpha1.ReconTestStatus, baseline *examplev1alpha1.BaselineStatus,
+	generation int64) {
+	status.Baseline = baseline
+	if baseline == nil {
+		meta.RemoveStatusCondition(&status.Conditions, examplev1alpha1.ConditionRegressed)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               examplev1alpha1.ConditionRegressed,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoRegressions",
+		Message:            fmt.Sprintf("No metric regressed against %s", baseline.Run),
+	}
+	switch {
+	case baseline.Error != "":
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "BaselineUnavailable"
+		condition.Message = baseline.Error
+	case baseline.Regressions > 0:
+		var regressed []string
+		for _, metric := range baseline.Metrics {
+			if metric.Regressed {
+				regressed = append(regressed, metric.Metric)
+			}
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Regressed"
+		condition.Message = fmt.Sprintf("%s regressed against %s", strings.Join(regressed, ", "), baseline.Run)
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
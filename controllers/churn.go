@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+)
+
+// churnRecreateRequeue is how soon a run with churned CRDs awaiting recreation is reconciled again
+const churnRecreateRequeue = time.Second * 5
+
+// pendingDelete is a CRD deleted by churn that is not gone yet
+type pendingDelete struct {
+	run     types.UID
+	owner   string
+	started time.Time
+}
+
+// churnTracker follows the CRDs deleted by churn until they are gone and recreated
+type churnTracker struct {
+	mu       sync.Mutex
+	deleting map[string]*pendingDelete
+	// awaiting holds the run of every churned CRD that is gone but not recreated yet
+	awaiting     map[string]types.UID
+	deleteToGone map[types.UID][]time.Duration
+}
+
+func newChurnTracker() *churnTracker {
+	return &churnTracker{
+		deleting:     map[string]*pendingDelete{},
+		awaiting:     map[string]types.UID{},
+		deleteToGone: map[types.UID][]time.Duration{},
+	}
+}
+
+// deleted records the time the Delete call for a churned CRD of a run was issued
+func (t *churnTracker) deleted(reconTest *examplev1alpha1.ReconTest, crdName string, started time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.deleting[crdName] = &pendingDelete{
+		run:     reconTest.UID,
+		owner:   reconTest.Namespace + "/" + reconTest.Name,
+		started: started,
+	}
+}
+
+// gone records that a CRD disappeared, completing its delete if it was churned
+func (t *churnTracker) gone(crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.deleting[crdName]
+	if !ok {
+		return
+	}
+	elapsed := time.Since(p.started)
+	t.deleteToGone[p.run] = append(t.deleteToGone[p.run], elapsed)
+	crdDeleteToGoneSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+
+	delete(t.deleting, crdName)
+	t.awaiting[crdName] = p.run
+}
+
+// forget drops a churned CRD whose Delete call failed or found it already gone
+func (t *churnTracker) forget(crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.deleting, crdName)
+}
+
+// recreated reports whether crdName was churned and is now being recreated
+func (t *churnTracker) recreated(crdName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.awaiting[crdName]; !ok {
+		return false
+	}
+	delete(t.awaiting, crdName)
+	return true
+}
+
+// pending returns the number of churned CRDs of a run that are not gone or not recreated yet
+func (t *churnTracker) pending(run types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, p := range t.deleting {
+		if p.run == run {
+			count++
+		}
+	}
+	for _, awaitingRun := range t.awaiting {
+		if awaitingRun == run {
+			count++
+		}
+	}
+	return count
+}
+
+// summary returns the delete-to-gone latency percentiles of a run, or nil when it has no samples
+func (t *churnTracker) summary(run types.UID) *examplev1alpha1.LatencySummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := t.deleteToGone[run]
+	if len(samples) == 0 {
+		return nil
+	}
+	summary := latencySummary(samples)
+	return &summary
+}
+
+// clear drops everything kept for a run
+func (t *churnTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.deleteToGone, run)
+	for name, p := range t.deleting {
+		if p.run == run {
+			delete(t.deleting, name)
+		}
+	}
+	for name, awaitingRun := range t.awaiting {
+		if awaitingRun == run {
+			delete(t.awaiting, name)
+		}
+	}
+}
+
+// eventHandler feeds CRD informer delete events into the tracker
+func (t *churnTracker) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.gone(crd.Name)
+			}
+		},
+	}
+}
+
+// churnDue returns how long until the next churn round of a run is due, zero when it is due now
+func churnDue(status examplev1alpha1.ReconTestStatus, churn *examplev1alpha1.ChurnSpec, now time.Time) time.Duration {
+	if status.Churn == nil || status.Churn.LastRoundTime == nil {
+		return 0
+	}
+	next := status.Churn.LastRoundTime.Add(churn.Interval.Duration)
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// churn deletes Percent of the CRDs of a run, chosen at random, at the churn rate. Failed
+// deletions are retried, given up or abort the run as policy decides.
+func (r *ReconTestReconciler) churn(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec, policy *retry.Policy) error {
+	churn := spec.Churn
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return err
+	}
+
+	candidates := make([]*v1.CustomResourceDefinition, 0, len(crds))
+	for i := range crds {
+		if crds[i].DeletionTimestamp == nil {
+			candidates = append(candidates, &crds[i])
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	victims := candidates[:(len(candidates)*int(churn.Percent)+99)/100]
+
+	roundStart := metav1.Now()
+	logger.Info(fmt.Sprintf("Churning %d of %d CRDs", len(victims), len(candidates)))
+
+	deleted := int32(0)
+	deleteStarts := make([]time.Time, len(victims))
+
+	aborted := r.runBatch(ctx, reconTest, policy, batch{
+		operation: opDeleteCRD,
+		cfg:       load.Config{Concurrency: 1, QPS: float64(churn.QPS)},
+		n:         len(victims),
+		request: func(ctx context.Context, i int) error {
+			deleteStarts[i] = time.Now()
+			r.churnTracker.deleted(reconTest, victims[i].Name, deleteStarts[i])
+			return r.Delete(ctx, victims[i])
+		},
+		result: func(i int, err error) error {
+			if err != nil {
+				r.churnTracker.forget(victims[i].Name)
+				if !apierrors.IsNotFound(err) {
+					logger.Error(err, fmt.Sprintf("Failed to churn CRD: %s", victims[i].Name))
+					return err
+				}
+			} else {
+				r.discovery.deleted(reconTest, spec, victims[i], deleteStarts[i])
+			}
+			deleted++
+			return nil
+		},
+		skipped: func(i int) {
+			r.churnTracker.forget(victims[i].Name)
+		},
+	})
+
+	return r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		if status.Churn == nil {
+			status.Churn = &examplev1alpha1.ChurnStatus{}
+		}
+		status.Churn.Rounds++
+		status.Churn.Deleted += deleted
+		status.Churn.LastRoundTime = &roundStart
+		if aborted != nil {
+			markAborted(status, aborted)
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func TestChurnDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	churn := &examplev1alpha1.ChurnSpec{Interval: metav1.Duration{Duration: time.Minute}}
+	lastRound := func(ago time.Duration) examplev1alpha1.ReconTestStatus {
+		last := metav1.NewTime(now.Add(-ago))
+		return examplev1alpha1.ReconTestStatus{Churn: &examplev1alpha1.ChurnStatus{LastRoundTime: &last}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		status examplev1alpha1.ReconTestStatus
+		want   time.Duration
+	}{
+		{"never churned", examplev1alpha1.ReconTestStatus{}, 0},
+		{"no round yet", examplev1alpha1.ReconTestStatus{Churn: &examplev1alpha1.ChurnStatus{}}, 0},
+		{"within the interval", lastRound(20 * time.Second), 40 * time.Second},
+		{"interval passed", lastRound(2 * time.Minute), 0},
+	} {
+		if got := churnDue(tc.status, churn, now); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
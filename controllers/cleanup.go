@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// listRunCRDs returns the generated CRDs labelled as belonging to the run of reconTest
+func (r *ReconTestReconciler) listRunCRDs(ctx context.Context,
+	reconTest *examplev1alpha1.ReconTest) ([]v1.CustomResourceDefinition, error) {
+	crds := &v1.CustomResourceDefinitionList{}
+	if err := r.List(ctx, crds, client.MatchingLabels{examplev1alpha1.RunLabel: string(reconTest.UID)}); err != nil {
+		return nil, err
+	}
+	return crds.Items, nil
+}
+
+// finalize applies the CleanupPolicy of a deleted ReconTest and releases its finalizer once done
+func (r *ReconTestReconciler) finalize(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(reconTest, examplev1alpha1.CleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	spec := crdgen.WithDefaults(reconTest.Spec)
+	switch spec.CleanupPolicy {
+	case examplev1alpha1.CleanupRetain:
+		logger.Info("Retaining CRDs of deleted run")
+	case examplev1alpha1.CleanupOrphan:
+		if err := r.orphanRunCRDs(ctx, logger, reconTest); err != nil {
+			return ctrl.Result{}, err
+		}
+	default:
+		remaining, err := r.deleteRunCRDs(ctx, logger, reconTest, spec)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if remaining > 0 {
+			// Keep the finalizer until every CRD of the run is gone
+			if err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+				status.Phase = examplev1alpha1.PhaseDeleting
+				status.Existing = int32(remaining)
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		}
+	}
+
+	// The run is over, settle the assertions that are still pending
+	if len(spec.Assertions) > 0 {
+		if err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			applyVerdict(status, r.evaluateAssertions(reconTest, spec, true), reconTest.Generation)
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Archive the results of the run before forgetting them
+	if spec.Report != nil {
+		if err := r.writeReport(ctx, reconTest, spec, true); err != nil {
+			// A report that cannot be written must not hold the ReconTest forever
+			logger.Error(err, "Failed to write the final report")
+		}
+	}
+
+	r.establishment.clear(reconTest.UID)
+	r.churnTracker.clear(reconTest.UID)
+	r.schemaChurn.clear(reconTest.UID)
+	r.instances.clear(reconTest.UID)
+	r.discovery.clear(reconTest.UID)
+	r.watches.clear(reconTest.UID)
+	r.lists.clear(reconTest.UID)
+	r.writes.clear(reconTest.UID)
+	r.requests.clear(reconTest.UID)
+	deleteRunMetrics(reconTest.Namespace + "/" + reconTest.Name)
+	controllerutil.RemoveFinalizer(reconTest, examplev1alpha1.CleanupFinalizer)
+	return ctrl.Result{}, r.Update(ctx, reconTest)
+}
+
+// deleteRunCRDs issues a delete for every CRD of the run and returns how many still exist
+func (r *ReconTestReconciler) deleteRunCRDs(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec) (int, error) {
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range crds {
+		crd := &crds[i]
+		if crd.DeletionTimestamp != nil {
+			continue // Already being deleted
+		}
+
+		logger.Info(fmt.Sprintf("Deleting CRD %s", crd.Name))
+		deleteStart := time.Now()
+		err := r.Delete(ctx, crd)
+		r.requests.observe(reconTest, opDeleteCRD, err)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return 0, err
+		}
+		r.discovery.deleted(reconTest, spec, crd, deleteStart)
+	}
+
+	return len(crds), nil
+}
+
+// orphanRunCRDs removes the run label and owner annotation from every CRD of the run
+func (r *ReconTestReconciler) orphanRunCRDs(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest) error {
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return err
+	}
+
+	for i := range crds {
+		crd := &crds[i]
+		base := crd.DeepCopy()
+		delete(crd.Labels, examplev1alpha1.RunLabel)
+		delete(crd.Annotations, examplev1alpha1.OwnerAnnotation)
+
+		logger.Info(fmt.Sprintf("Orphaning CRD %s", crd.Name))
+		if err := r.Patch(ctx, crd, client.MergeFrom(base)); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
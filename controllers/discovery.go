@@ -0,0 +1,402 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/discovery"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/series"
+)
+
+// discoveryTick is how often the poller checks for runs whose next poll is due
+const discoveryTick = time.Millisecond * 250
+
+// discoveryRequeue is how soon a run with kinds still awaited on an endpoint is reconciled again
+const discoveryRequeue = time.Second * 5
+
+// discoveryMetricsRetention is how long the discovery series of a deleted run are kept once its
+// last kind is gone, so the final samples are scraped
+const discoveryMetricsRetention = time.Minute * 2
+
+// Transitions of a kind on an endpoint, used as metric label values
+const (
+	transitionAppear    = "appear"
+	transitionDisappear = "disappear"
+)
+
+// awaitedKind is a kind expected to appear on or disappear from the polled endpoints
+type awaitedKind struct {
+	appear bool
+	since  time.Time
+	// endpoints holds the endpoints that have not shown the transition yet
+	endpoints map[examplev1alpha1.DiscoveryEndpoint]bool
+}
+
+// transitionSamples holds the latency samples and timeouts of one endpoint
+type transitionSamples struct {
+	appear    []time.Duration
+	disappear []time.Duration
+	timedOut  int32
+}
+
+// discoveryRun holds the polling settings, awaited kinds and samples of one run
+type discoveryRun struct {
+	owner    string
+	settings examplev1alpha1.DiscoverySpec
+	nextPoll time.Time
+	awaited  map[schema.GroupVersionKind]*awaitedKind
+	samples  map[examplev1alpha1.DiscoveryEndpoint]*transitionSamples
+	// unsupported holds the endpoints the API server does not serve
+	unsupported map[examplev1alpha1.DiscoveryEndpoint]bool
+	// cleared is set once the run is deleted; its awaited kinds are still measured for the metrics
+	cleared bool
+}
+
+// discoveryTracker measures how long the kinds of created and deleted CRDs take to
+// appear in and disappear from the discovery and OpenAPI endpoints. It runs as a
+// manager runnable that polls the endpoints of every run with awaited kinds.
+type discoveryTracker struct {
+	client *discovery.Client
+	log    logr.Logger
+
+	mu   sync.Mutex
+	runs map[types.UID]*discoveryRun
+	// retired holds the time the series of each deleted run, by owner, are deleted
+	retired map[string]time.Time
+}
+
+func newDiscoveryTracker(client *discovery.Client, log logr.Logger) *discoveryTracker {
+	return &discoveryTracker{
+		client:  client,
+		log:     log,
+		runs:    map[types.UID]*discoveryRun{},
+		retired: map[string]time.Time{},
+	}
+}
+
+// created awaits the appearance of the kind of the CRD with the given index, created at the given time
+func (t *discoveryTracker) created(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	index int, at time.Time) {
+	gvk := schema.GroupVersionKind{
+		Group:   spec.Group,
+		Version: crdgen.StorageVersion(spec),
+		Kind:    crdgen.Names(spec, index).Kind,
+	}
+	t.await(reconTest, spec.Discovery, gvk, true, at)
+}
+
+// deleted awaits the disappearance of the kind of crd, deleted at the given time
+func (t *discoveryTracker) deleted(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	crd *v1.CustomResourceDefinition, at time.Time) {
+	t.await(reconTest, spec.Discovery, storageKind(crd), false, at)
+}
+
+// await records a transition of a kind to measure, unless the run does not measure discovery
+func (t *discoveryTracker) await(reconTest *examplev1alpha1.ReconTest, settings *examplev1alpha1.DiscoverySpec,
+	gvk schema.GroupVersionKind, appear bool, at time.Time) {
+	if settings == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run := t.runs[reconTest.UID]
+	if run == nil {
+		run = &discoveryRun{
+			owner:       reconTest.Namespace + "/" + reconTest.Name,
+			awaited:     map[schema.GroupVersionKind]*awaitedKind{},
+			samples:     map[examplev1alpha1.DiscoveryEndpoint]*transitionSamples{},
+			unsupported: map[examplev1alpha1.DiscoveryEndpoint]bool{},
+		}
+		t.runs[reconTest.UID] = run
+		// A new run with the name of a deleted one keeps its series
+		delete(t.retired, run.owner)
+	}
+	run.settings = *settings
+
+	endpoints := make(map[examplev1alpha1.DiscoveryEndpoint]bool, len(settings.Endpoints))
+	for _, endpoint := range settings.Endpoints {
+		if !run.unsupported[endpoint] {
+			endpoints[endpoint] = true
+		}
+	}
+	run.awaited[gvk] = &awaitedKind{appear: appear, since: at, endpoints: endpoints}
+}
+
+// pending returns the number of kinds of a run still awaited on some endpoint
+func (t *discoveryTracker) pending(run types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r := t.runs[run]; r != nil {
+		return len(r.awaited)
+	}
+	return 0
+}
+
+// summary returns the publication latencies of a run, or nil when it measured none
+func (t *discoveryTracker) summary(run types.UID) *examplev1alpha1.DiscoveryStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.runs[run]
+	if r == nil {
+		return nil
+	}
+
+	status := &examplev1alpha1.DiscoveryStatus{}
+	for _, endpoint := range r.settings.Endpoints {
+		endpointStatus := examplev1alpha1.EndpointDiscoveryStatus{
+			Endpoint:    endpoint,
+			Unsupported: r.unsupported[endpoint],
+		}
+		if samples := r.samples[endpoint]; samples != nil {
+			if len(samples.appear) > 0 {
+				appear := latencySummary(samples.appear)
+				endpointStatus.Appear = &appear
+			}
+			if len(samples.disappear) > 0 {
+				disappear := latencySummary(samples.disappear)
+				endpointStatus.Disappear = &disappear
+			}
+			endpointStatus.TimedOut = samples.timedOut
+		}
+		for _, awaited := range r.awaited {
+			if awaited.endpoints[endpoint] {
+				endpointStatus.Pending++
+			}
+		}
+		status.Endpoints = append(status.Endpoints, endpointStatus)
+	}
+	return status
+}
+
+// clear drops the samples of a run. The kinds awaited to disappear after its deletion
+// are still measured for the metrics.
+func (t *discoveryTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.runs[run]
+	if r == nil {
+		return
+	}
+	r.cleared = true
+	r.samples = map[examplev1alpha1.DiscoveryEndpoint]*transitionSamples{}
+	for gvk, awaited := range r.awaited {
+		if awaited.appear {
+			delete(r.awaited, gvk)
+		}
+	}
+	if len(r.awaited) == 0 {
+		delete(t.runs, run)
+	}
+}
+
+// Start polls the endpoints of the runs with awaited kinds until ctx is done
+func (t *discoveryTracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(discoveryTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, run := range t.dueRuns(time.Now()) {
+				t.poll(ctx, run)
+			}
+			t.deleteRetired(time.Now())
+		}
+	}
+}
+
+// NeedLeaderElection runs the poller on the leader only, the replica that creates and deletes the CRDs
+func (t *discoveryTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// dueRuns returns the runs with awaited kinds whose next poll is due, and schedules their following poll
+func (t *discoveryTracker) dueRuns(now time.Time) []*discoveryRun {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var due []*discoveryRun
+	for _, run := range t.runs {
+		if len(run.awaited) > 0 && !now.Before(run.nextPoll) {
+			run.nextPoll = now.Add(run.settings.Interval.Duration)
+			due = append(due, run)
+		}
+	}
+	return due
+}
+
+// poll fetches the endpoints of a run and records the awaited kinds that made their transition
+func (t *discoveryTracker) poll(ctx context.Context, run *discoveryRun) {
+	t.mu.Lock()
+	var endpoints []examplev1alpha1.DiscoveryEndpoint
+	for _, endpoint := range run.settings.Endpoints {
+		if !run.unsupported[endpoint] {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	gvs := awaitedGroupVersions(run)
+	t.mu.Unlock()
+
+	for _, endpoint := range endpoints {
+		start := time.Now()
+		kinds, err := t.client.Kinds(ctx, endpoint, gvs)
+		polled := time.Now()
+		discoveryFetchSeconds.WithLabelValues(string(endpoint)).Observe(polled.Sub(start).Seconds())
+
+		t.mu.Lock()
+		switch {
+		case errors.Is(err, discovery.ErrUnsupported):
+			t.markUnsupported(run, endpoint)
+		case err != nil:
+			t.log.Error(err, "Failed to poll discovery endpoint", "recontest", run.owner, "endpoint", endpoint)
+		default:
+			t.observe(run, endpoint, kinds, polled)
+		}
+		t.mu.Unlock()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(run, time.Now())
+}
+
+// observe records the kinds of a run that made their transition on endpoint by the time it was polled
+func (t *discoveryTracker) observe(run *discoveryRun, endpoint examplev1alpha1.DiscoveryEndpoint,
+	kinds discovery.Kinds, polled time.Time) {
+	for gvk, awaited := range run.awaited {
+		if !awaited.endpoints[endpoint] || kinds.Has(gvk) != awaited.appear {
+			continue
+		}
+
+		elapsed := polled.Sub(awaited.since)
+		transition := transitionDisappear
+		if awaited.appear {
+			transition = transitionAppear
+		}
+		discoverySeconds.WithLabelValues(run.owner, string(endpoint), transition).Observe(elapsed.Seconds())
+		if !run.cleared {
+			samples := run.endpointSamples(endpoint)
+			if awaited.appear {
+				samples.appear = append(samples.appear, elapsed)
+			} else {
+				samples.disappear = append(samples.disappear, elapsed)
+			}
+		}
+
+		delete(awaited.endpoints, endpoint)
+		if len(awaited.endpoints) == 0 {
+			delete(run.awaited, gvk)
+		}
+	}
+}
+
+// markUnsupported stops awaiting kinds on an endpoint the API server does not serve
+func (t *discoveryTracker) markUnsupported(run *discoveryRun, endpoint examplev1alpha1.DiscoveryEndpoint) {
+	if !run.unsupported[endpoint] {
+		t.log.Info("Discovery endpoint not served, no longer polling it", "recontest", run.owner, "endpoint", endpoint)
+	}
+	run.unsupported[endpoint] = true
+	for gvk, awaited := range run.awaited {
+		delete(awaited.endpoints, endpoint)
+		if len(awaited.endpoints) == 0 {
+			delete(run.awaited, gvk)
+		}
+	}
+}
+
+// expire gives up on the kinds of a run awaited for longer than its timeout
+func (t *discoveryTracker) expire(run *discoveryRun, now time.Time) {
+	for gvk, awaited := range run.awaited {
+		if now.Sub(awaited.since) < run.settings.Timeout.Duration {
+			continue
+		}
+
+		transition := transitionDisappear
+		if awaited.appear {
+			transition = transitionAppear
+		}
+		for endpoint := range awaited.endpoints {
+			discoveryTimeoutsTotal.WithLabelValues(run.owner, string(endpoint), transition).Inc()
+			if !run.cleared {
+				run.endpointSamples(endpoint).timedOut++
+			}
+		}
+		delete(run.awaited, gvk)
+	}
+
+	// A deleted run is forgotten once its last kind is gone, and its series soon after
+	if run.cleared && len(run.awaited) == 0 {
+		for uid, r := range t.runs {
+			if r == run {
+				delete(t.runs, uid)
+				t.retired[run.owner] = now.Add(discoveryMetricsRetention)
+			}
+		}
+	}
+}
+
+// deleteRetired deletes the discovery series of the deleted runs whose retention is over
+func (t *discoveryTracker) deleteRetired(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for owner, at := range t.retired {
+		if now.Before(at) {
+			continue
+		}
+		series.Delete("recontest", owner, discoverySeconds, discoveryTimeoutsTotal)
+		delete(t.retired, owner)
+	}
+}
+
+// endpointSamples returns the samples of endpoint, creating them on first use
+func (r *discoveryRun) endpointSamples(endpoint examplev1alpha1.DiscoveryEndpoint) *transitionSamples {
+	samples := r.samples[endpoint]
+	if samples == nil {
+		samples = &transitionSamples{}
+		r.samples[endpoint] = samples
+	}
+	return samples
+}
+
+// awaitedGroupVersions returns the group versions of the awaited kinds of a run, in order
+func awaitedGroupVersions(run *discoveryRun) []schema.GroupVersion {
+	seen := map[schema.GroupVersion]bool{}
+	var gvs []schema.GroupVersion
+	for gvk := range run.awaited {
+		if gv := gvk.GroupVersion(); !seen[gv] {
+			seen[gv] = true
+			gvs = append(gvs, gv)
+		}
+	}
+	sort.Slice(gvs, func(i, j int) bool { return gvs[i].String() < gvs[j].String() })
+	return gvs
+}
+
+// storageKind returns the kind of crd in its storage version
+func storageKind(crd *v1.CustomResourceDefinition) schema.GroupVersionKind {
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+	for _, version := range crd.Spec.Versions {
+		if version.Storage {
+			gvk.Version = version.Name
+		}
+	}
+	return gvk
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/discovery"
+)
+
+func TestDiscoveryTrackerSummary(t *testing.T) {
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{
+		Count: 2,
+		Discovery: &examplev1alpha1.DiscoverySpec{
+			Endpoints: []examplev1alpha1.DiscoveryEndpoint{
+				examplev1alpha1.DiscoveryGroupVersion,
+				examplev1alpha1.DiscoveryOpenAPIV3,
+			},
+			Timeout: metav1.Duration{Duration: time.Minute},
+		},
+	})
+	tracker := newDiscoveryTracker(nil, logr.Discard())
+	run := testRun()
+	created := time.Now()
+	tracker.created(run, spec, 1, created)
+	tracker.created(run, spec, 2, created)
+	if pending := tracker.pending(run.UID); pending != 2 {
+		t.Fatalf("expected 2 awaited kinds, got %d", pending)
+	}
+
+	// The first kind shows up on both endpoints, the second only on GroupVersion
+	gv := schema.GroupVersion{Group: spec.Group, Version: crdgen.StorageVersion(spec)}
+	first, second := crdgen.Names(spec, 1).Kind, crdgen.Names(spec, 2).Kind
+	d := tracker.runs[run.UID]
+	tracker.observe(d, examplev1alpha1.DiscoveryGroupVersion,
+		discovery.Kinds{gv: sets.NewString(first, second)}, created.Add(time.Second))
+	tracker.observe(d, examplev1alpha1.DiscoveryOpenAPIV3,
+		discovery.Kinds{gv: sets.NewString(first)}, created.Add(2*time.Second))
+	if pending := tracker.pending(run.UID); pending != 1 {
+		t.Errorf("expected 1 awaited kind, got %d", pending)
+	}
+
+	// The second kind times out on OpenAPIV3
+	tracker.expire(d, created.Add(2*time.Minute))
+
+	summary := tracker.summary(run.UID)
+	if summary == nil || len(summary.Endpoints) != 2 {
+		t.Fatalf("expected a summary of 2 endpoints, got %+v", summary)
+	}
+	groupVersion, openAPI := summary.Endpoints[0], summary.Endpoints[1]
+	if groupVersion.Appear == nil || groupVersion.Appear.Samples != 2 || groupVersion.TimedOut != 0 {
+		t.Errorf("expected 2 appearances on GroupVersion, got %+v", groupVersion)
+	}
+	if openAPI.Appear == nil || openAPI.Appear.Samples != 1 || openAPI.TimedOut != 1 {
+		t.Errorf("expected 1 appearance and 1 timeout on OpenAPIV3, got %+v", openAPI)
+	}
+	if openAPI.Appear.P50.Duration != 2*time.Second {
+		t.Errorf("expected the latency to be measured until the poll, got %s", openAPI.Appear.P50.Duration)
+	}
+	if tracker.pending(run.UID) != 0 {
+		t.Error("expected no awaited kinds after the timeout")
+	}
+}
+
+func TestDiscoveryTrackerIgnoresRunsWithoutDiscovery(t *testing.T) {
+	tracker := newDiscoveryTracker(nil, logr.Discard())
+	run := testRun()
+	tracker.created(run, crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{}), 1, time.Now())
+	if tracker.pending(run.UID) != 0 || tracker.summary(run.UID) != nil {
+		t.Error("expected nothing to be awaited for a run that does not measure discovery")
+	}
+}
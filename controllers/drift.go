@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// drifted reports whether the live CRD no longer matches the intended one. A CRD drifts
+// when its spec was edited since it was written, or when the run now intends a different spec.
+func drifted(live, intended *v1.CustomResourceDefinition) bool {
+	wantHash := intended.Annotations[examplev1alpha1.SpecHashAnnotation]
+	return live.Annotations[examplev1alpha1.SpecHashAnnotation] != wantHash || crdgen.SpecHash(live.Spec) != wantHash
+}
+
+// repairDrift compares a live CRD of the run against its intended spec and applies the
+// DriftPolicy of the run to it when it drifted
+func (r *ReconTestReconciler) repairDrift(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, policy examplev1alpha1.DriftPolicy,
+	live, intended *v1.CustomResourceDefinition, pass *crdPass) {
+	if live.DeletionTimestamp != nil || !drifted(live, intended) {
+		return
+	}
+
+	owner := reconTest.Namespace + "/" + reconTest.Name
+	pass.drifted++
+	crdDriftTotal.WithLabelValues(owner, string(policy)).Inc()
+	logger.Info(fmt.Sprintf("CRD drifted from its intended spec: %s", live.Name), "driftPolicy", policy)
+
+	switch policy {
+	case examplev1alpha1.DriftUpdate:
+		repaired := live.DeepCopy()
+		repaired.Spec = intended.Spec
+		if repaired.Annotations == nil {
+			repaired.Annotations = map[string]string{}
+		}
+		repaired.Annotations[examplev1alpha1.SpecHashAnnotation] = intended.Annotations[examplev1alpha1.SpecHashAnnotation]
+		err := r.Update(ctx, repaired)
+		r.requests.observe(reconTest, opUpdateCRD, err)
+		if err != nil {
+			logger.Error(err, fmt.Sprintf("Failed to update drifted CRD: %s", live.Name))
+			pass.recordFailure(live.Name, err)
+		}
+	case examplev1alpha1.DriftRecreate:
+		// The delete event brings the run back here to create the CRD again
+		err := r.Delete(ctx, live)
+		r.requests.observe(reconTest, opDeleteCRD, err)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Failed to delete drifted CRD: %s", live.Name))
+			pass.recordFailure(live.Name, err)
+		}
+	}
+}
+
+// webhookCABundle returns the CA bundle crd reaches its conversion webhook with, and whether it uses one
+func webhookCABundle(crd *v1.CustomResourceDefinition) ([]byte, bool) {
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Strategy != v1.WebhookConverter || conversion.Webhook == nil ||
+		conversion.Webhook.ClientConfig == nil {
+		return nil, false
+	}
+	return conversion.Webhook.ClientConfig.CABundle, true
+}
+
+// rotateCABundle patches the CA bundle of the intended CRD into the live one when they both use the
+// conversion webhook and the bundle changed, whatever the DriftPolicy of the run, since the API server
+// cannot convert the custom resources of the CRD without it. It returns the live CRD as it is after it.
+func (r *ReconTestReconciler) rotateCABundle(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, live, intended *v1.CustomResourceDefinition,
+	pass *crdPass) *v1.CustomResourceDefinition {
+	liveBundle, liveOK := webhookCABundle(live)
+	intendedBundle, intendedOK := webhookCABundle(intended)
+	if live.DeletionTimestamp != nil || !liveOK || !intendedOK || bytes.Equal(liveBundle, intendedBundle) {
+		return live
+	}
+
+	rotated := live.DeepCopy()
+	rotated.Spec.Conversion.Webhook.ClientConfig.CABundle = intendedBundle
+	if !drifted(live, live) {
+		// The spec was as written, so it stays as written with the new bundle
+		rotated.Annotations[examplev1alpha1.SpecHashAnnotation] = crdgen.SpecHash(rotated.Spec)
+	}
+	logger.Info(fmt.Sprintf("Rotating the conversion webhook CA bundle of CRD %s", live.Name))
+	err := r.Patch(ctx, rotated, client.MergeFrom(live))
+	r.requests.observe(reconTest, opUpdateCRD, err)
+	if err != nil {
+		logger.Error(err, fmt.Sprintf("Failed to rotate the CA bundle of CRD: %s", live.Name))
+		pass.recordFailure(live.Name, err)
+		return live
+	}
+	return rotated
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// webhookSpec returns a spec whose CRDs convert their versions through the conversion webhook
+func webhookSpec() examplev1alpha1.ReconTestSpec {
+	return crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{
+		Count:    1,
+		Versions: &examplev1alpha1.VersionsSpec{Count: 2, Conversion: examplev1alpha1.ConversionWebhook},
+	})
+}
+
+func TestConversionWebhookReadsCABundleOnEveryPass(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ca.crt")
+	r := &ReconTestReconciler{
+		ConversionWebhook:      &crdgen.WebhookConfig{URL: "https://operator:9443", CABundle: []byte("startup")},
+		ConversionCABundleFile: file,
+	}
+	for _, bundle := range []string{"first", "rotated"} {
+		if err := os.WriteFile(file, []byte(bundle), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		webhook, err := r.conversionWebhook(webhookSpec())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(webhook.CABundle) != bundle {
+			t.Errorf("expected CA bundle %q, got %q", bundle, webhook.CABundle)
+		}
+	}
+	if string(r.ConversionWebhook.CABundle) != "startup" {
+		t.Errorf("expected the configured webhook to be left as it is, got %q", r.ConversionWebhook.CABundle)
+	}
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.conversionWebhook(webhookSpec()); err == nil {
+		t.Error("expected an error for a missing CA bundle")
+	}
+	if _, err := r.conversionWebhook(crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{Count: 1})); err != nil {
+		t.Errorf("expected runs without webhook conversion not to read the CA bundle, got %v", err)
+	}
+}
+
+func TestRotateCABundle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	run := testRun()
+	spec := webhookSpec()
+	schema, err := crdgen.Schema(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhook := func(bundle string) *crdgen.WebhookConfig {
+		return &crdgen.WebhookConfig{URL: "https://operator", CABundle: []byte(bundle)}
+	}
+	old := crdgen.CRD(run, spec, 0, schema, webhook("old"))
+	intended := crdgen.CRD(run, spec, 0, schema, webhook("new"))
+	if !drifted(old, intended) {
+		t.Fatal("expected a changed CA bundle to count as drift")
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(old.DeepCopy()).Build()
+	r := &ReconTestReconciler{Client: c, requests: newRequestTracker()}
+	live := &v1.CustomResourceDefinition{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: old.Name}, live); err != nil {
+		t.Fatal(err)
+	}
+	pass := &crdPass{desired: 1}
+	rotated := r.rotateCABundle(context.Background(), logr.Discard(), run, live, intended, pass)
+
+	if len(pass.failures) > 0 {
+		t.Fatalf("unexpected failures %+v", pass.failures)
+	}
+	if bundle, _ := webhookCABundle(rotated); string(bundle) != "new" {
+		t.Errorf("expected the rotated CRD to carry the new bundle, got %q", bundle)
+	}
+	if drifted(rotated, intended) {
+		t.Error("expected a CRD that only missed the new bundle not to drift once it is rotated")
+	}
+	stored := &v1.CustomResourceDefinition{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: old.Name}, stored); err != nil {
+		t.Fatal(err)
+	}
+	if bundle, _ := webhookCABundle(stored); string(bundle) != "new" {
+		t.Errorf("expected the new bundle to be patched into the live CRD, got %q", bundle)
+	}
+	if again := r.rotateCABundle(context.Background(), logr.Discard(), run, stored, intended, pass); again != stored {
+		t.Error("expected a CRD with the current bundle to be left as it is")
+	}
+}
@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+)
+
+// dryRun sends the creation of every missing CRD of spec, and the write the real run would make to
+// every existing one, with dryRun=All, at the concurrency and rate set in spec and as spec.writes
+// would write them, and reports what the API server accepted and the requests the real run would
+// make. It runs once per generation; nothing is persisted, so the run has nothing to clean up
+// beyond the CRDs a real pass created before the run was switched to a dry run.
+func (r *ReconTestReconciler) dryRun(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	schema *v1.JSONSchemaProps, policy *retry.Policy) (ctrl.Result, error) {
+	if status := reconTest.Status.DryRun; status != nil && status.ObservedGeneration == reconTest.Generation {
+		// Already done for this generation
+		return ctrl.Result{}, nil
+	}
+
+	// A run switched to a dry run stops loading the cluster, its CRDs stay until it is deleted
+	r.watches.clear(reconTest.UID)
+	r.lists.clear(reconTest.UID)
+
+	webhook, err := r.conversionWebhook(spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	runCRDs, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	present := make(map[string]*v1.CustomResourceDefinition, len(runCRDs))
+	for i := range runCRDs {
+		present[runCRDs[i].Name] = &runCRDs[i]
+	}
+
+	dryRun := &examplev1alpha1.DryRunStatus{ObservedGeneration: reconTest.Generation}
+	pass := &crdPass{desired: spec.Count}
+	crds := make([]*v1.CustomResourceDefinition, 0, spec.Count)
+	var updates []rewrite
+	for _, index := range crdgen.Indices(spec) {
+		crd := crdgen.CRD(reconTest, spec, index, schema, webhook)
+		// Marshalling only fails on invalid raw JSON, which the generator does not produce
+		raw, _ := json.Marshal(crd)
+		dryRun.CRDBytes += int64(len(raw))
+		live, ok := present[crd.Name]
+		if !ok {
+			crds = append(crds, crd)
+			continue
+		}
+
+		dryRun.Existing++
+		if spec.SchemaChurn != nil {
+			// The schema churn updates of the live CRD are part of its intended spec
+			if err := crdgen.Revise(crd, spec.SchemaChurn.Changes, crdgen.SchemaRevision(live)); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if existingWrite(spec, live, crd) != "" {
+			updates = append(updates, rewrite{live: live, intended: crd})
+		}
+	}
+	dryRun.CRDCreates = int32(len(crds))
+	dryRun.CRDUpdates = int32(len(updates))
+	if spec.Instances != nil {
+		dryRun.InstanceCreates = int64(spec.Count) * int64(spec.Instances.PerCRD)
+		dryRun.InstanceCreatesEstimated = true
+	}
+	logger.Info(fmt.Sprintf("Dry run of %d CRD creations and %d CRD updates", len(crds), len(updates)))
+
+	// Both batches record what the API server made of every request
+	result := func(name string, err error) error {
+		switch {
+		case err == nil:
+			dryRun.Accepted++
+		case apierrors.IsAlreadyExists(err):
+			// Created since the CRDs of the run were listed
+			dryRun.Existing++
+		case apierrors.IsNotFound(err):
+			// Deleted since the CRDs of the run were listed, the real run would create it again
+		default:
+			logger.Info(fmt.Sprintf("Dry run rejected CRD: %s", name), "error", err.Error())
+			dryRun.Rejected++
+			pass.recordFailure(name, err)
+			return err
+		}
+		return nil
+	}
+
+	elapsed := make([]time.Duration, len(crds)+len(updates))
+	pass.abort(r.runBatch(ctx, reconTest, policy, batch{
+		operation: writeOperation(spec),
+		cfg:       loadConfig(spec.Rate),
+		n:         len(crds),
+		request: func(ctx context.Context, i int) error {
+			start := time.Now()
+			err := r.dryRunCRD(ctx, spec, crds[i])
+			elapsed[i] = time.Since(start)
+			return err
+		},
+		result: func(i int, err error) error {
+			return result(crds[i].Name, err)
+		},
+	}))
+	if pass.aborted == nil && len(updates) > 0 {
+		attempted := make([]bool, len(updates))
+		pass.abort(r.runBatch(ctx, reconTest, policy, batch{
+			operation: existingWrite(spec, updates[0].live, updates[0].intended),
+			cfg:       loadConfig(spec.Rate),
+			n:         len(updates),
+			request: func(ctx context.Context, i int) error {
+				if attempted[i] {
+					// A retried write starts again from the CRD as it is now
+					live := &v1.CustomResourceDefinition{}
+					if err := r.Get(ctx, client.ObjectKeyFromObject(updates[i].live), live); err != nil {
+						return err
+					}
+					updates[i].live = live
+				}
+				attempted[i] = true
+				start := time.Now()
+				err := r.dryRunExisting(ctx, spec, updates[i])
+				elapsed[len(crds)+i] = time.Since(start)
+				return err
+			},
+			result: func(i int, err error) error {
+				return result(updates[i].live.Name, err)
+			},
+		}))
+	}
+
+	var latencies []time.Duration
+	for _, latency := range elapsed {
+		if latency > 0 {
+			latencies = append(latencies, latency)
+		}
+	}
+	if len(latencies) > 0 {
+		latency := latencySummary(latencies)
+		dryRun.Latency = &latency
+	}
+	now := metav1.Now()
+	dryRun.CompletionTime = &now
+
+	return ctrl.Result{}, r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		status.ObservedGeneration = reconTest.Generation
+		status.Desired = spec.Count
+		status.Failed = int32(len(pass.failures))
+		status.FailedCRDs = pass.failures
+		if len(status.FailedCRDs) > maxReportedFailures {
+			status.FailedCRDs = status.FailedCRDs[:maxReportedFailures]
+		}
+		status.DryRun = dryRun
+		status.Phase = examplev1alpha1.PhaseCompleted
+
+		degraded := metav1.Condition{
+			Type:               examplev1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: reconTest.Generation,
+			Reason:             "DryRunAccepted",
+			Message:            fmt.Sprintf("The dry run accepted %d CRD writes", dryRun.Accepted),
+		}
+		if dryRun.Rejected > 0 {
+			status.Phase = examplev1alpha1.PhaseFailed
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = "DryRunRejected"
+			degraded.Message = fmt.Sprintf("The dry run rejected %d CRD writes, last error: %v",
+				dryRun.Rejected, pass.lastErr)
+		}
+		meta.SetStatusCondition(&status.Conditions, degraded)
+		if classes := r.requests.classSummary(reconTest.UID); len(classes) > 0 || pass.aborted != nil {
+			status.Errors = &examplev1alpha1.ErrorStatus{Classes: classes, Aborted: pass.aborted}
+		}
+		if pass.aborted != nil {
+			markAborted(status, pass.aborted)
+		}
+	})
+}
+
+// dryRunCRD sends the creation of crd with dryRun=All, applying it in the ServerSideApply mode
+func (r *ReconTestReconciler) dryRunCRD(ctx context.Context, spec examplev1alpha1.ReconTestSpec,
+	crd *v1.CustomResourceDefinition) error {
+	if spec.Writes == nil {
+		return r.Create(ctx, crd, client.DryRunAll)
+	}
+	if spec.Writes.Mode == examplev1alpha1.WriteServerSideApply {
+		return r.apply(ctx, crd, spec.Writes.FieldManager, spec.Writes.Force, client.DryRunAll)
+	}
+	return r.Create(ctx, crd, client.FieldOwner(spec.Writes.FieldManager), client.DryRunAll)
+}
+
+// existingWrite returns the operation the real run makes on the existing CRD live, whose intended
+// spec is intended, or an empty string when it leaves it as it is
+func existingWrite(spec examplev1alpha1.ReconTestSpec, live, intended *v1.CustomResourceDefinition) string {
+	if live.DeletionTimestamp != nil {
+		return ""
+	}
+	switch writeMode(spec) {
+	case examplev1alpha1.WriteUpdate:
+		return opUpdateCRD
+	case examplev1alpha1.WriteServerSideApply:
+		return opApplyCRD
+	}
+	if !drifted(live, intended) {
+		return ""
+	}
+	switch spec.DriftPolicy {
+	case examplev1alpha1.DriftUpdate:
+		return opUpdateCRD
+	case examplev1alpha1.DriftRecreate:
+		return opDeleteCRD
+	}
+	return ""
+}
+
+// dryRunExisting sends the write the real run makes on an existing CRD with dryRun=All
+func (r *ReconTestReconciler) dryRunExisting(ctx context.Context, spec examplev1alpha1.ReconTestSpec,
+	update rewrite) error {
+	switch existingWrite(spec, update.live, update.intended) {
+	case opApplyCRD:
+		return r.apply(ctx, update.intended.DeepCopy(), spec.Writes.FieldManager, spec.Writes.Force,
+			client.DryRunAll)
+	case opDeleteCRD:
+		return r.Delete(ctx, update.live.DeepCopy(), client.DryRunAll)
+	}
+	opts := []client.UpdateOption{client.DryRunAll}
+	if writeMode(spec) == examplev1alpha1.WriteUpdate {
+		opts = append(opts, client.FieldOwner(spec.Writes.FieldManager))
+	}
+	return r.Update(ctx, rewritten(update.live, update.intended), opts...)
+}
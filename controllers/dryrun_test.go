@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+func TestExistingWrite(t *testing.T) {
+	run := testRun()
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{Count: 1})
+	schema, err := crdgen.Schema(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intended := crdgen.CRD(run, spec, 0, schema, nil)
+	edited := intended.DeepCopy()
+	edited.Spec.Names.ShortNames = []string{"edited"}
+	deleting := intended.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{}
+
+	withWrites := func(mode examplev1alpha1.WriteMode,
+		drift examplev1alpha1.DriftPolicy) examplev1alpha1.ReconTestSpec {
+		spec := spec
+		spec.DriftPolicy = drift
+		if mode != "" {
+			spec.Writes = &examplev1alpha1.WriteSpec{Mode: mode, FieldManager: "run"}
+		}
+		return spec
+	}
+	for _, tc := range []struct {
+		name string
+		spec examplev1alpha1.ReconTestSpec
+		live *v1.CustomResourceDefinition
+		want string
+	}{
+		{"as intended", withWrites("", examplev1alpha1.DriftUpdate), intended, ""},
+		{"drift ignored", withWrites("", examplev1alpha1.DriftIgnore), edited, ""},
+		{"drift updated", withWrites("", examplev1alpha1.DriftUpdate), edited, opUpdateCRD},
+		{"drift recreated", withWrites("", examplev1alpha1.DriftRecreate), edited, opDeleteCRD},
+		{"update mode", withWrites(examplev1alpha1.WriteUpdate, examplev1alpha1.DriftIgnore), intended, opUpdateCRD},
+		{"apply mode", withWrites(examplev1alpha1.WriteServerSideApply, examplev1alpha1.DriftIgnore), intended,
+			opApplyCRD},
+		{"being deleted", withWrites(examplev1alpha1.WriteUpdate, examplev1alpha1.DriftIgnore), deleting, ""},
+	} {
+		if got := existingWrite(tc.spec, tc.live, intended); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestDryRunExistingPersistsNothing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	run := testRun()
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{Count: 1, DriftPolicy: examplev1alpha1.DriftRecreate})
+	schema, err := crdgen.Schema(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intended := crdgen.CRD(run, spec, 0, schema, nil)
+	edited := intended.DeepCopy()
+	edited.Spec.Names.ShortNames = []string{"edited"}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(edited).Build()
+	r := &ReconTestReconciler{Client: c}
+
+	for _, policy := range []examplev1alpha1.DriftPolicy{examplev1alpha1.DriftUpdate, examplev1alpha1.DriftRecreate} {
+		spec.DriftPolicy = policy
+		live := &v1.CustomResourceDefinition{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(edited), live); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.dryRunExisting(context.Background(), spec, rewrite{live: live, intended: intended}); err != nil {
+			t.Fatalf("%s: %v", policy, err)
+		}
+		stored := &v1.CustomResourceDefinition{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(edited), stored); err != nil {
+			t.Fatalf("%s: expected the CRD to be kept: %v", policy, err)
+		}
+		if !drifted(stored, intended) {
+			t.Errorf("%s: expected the drifted CRD to be left as it is", policy)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+// pendingCRD is a created CRD that is not established yet
+type pendingCRD struct {
+	run           types.UID
+	owner         string
+	created       time.Time
+	namesAccepted bool
+
+	// recreated is set for CRDs recreated after being deleted by churn
+	recreated bool
+	timing    *crdTiming
+}
+
+// crdTiming holds the timings of one Create call of a CRD, zero until measured
+type crdTiming struct {
+	name          string
+	created       time.Time
+	createCall    time.Duration
+	namesAccepted time.Duration
+	established   time.Duration
+	recreated     bool
+}
+
+// runLatencies holds the establishment latency samples of one run
+type runLatencies struct {
+	namesAccepted []time.Duration
+	established   []time.Duration
+	reestablished []time.Duration
+	// crds holds the timings of every Create call of the run
+	crds []*crdTiming
+}
+
+// establishmentTracker measures how long each created CRD takes to have its
+// NamesAccepted and Established conditions turn True. It is fed by the CRD
+// informer, so the measurement does not block reconciliation.
+type establishmentTracker struct {
+	mu      sync.Mutex
+	pending map[string]*pendingCRD
+	runs    map[types.UID]*runLatencies
+}
+
+func newEstablishmentTracker() *establishmentTracker {
+	return &establishmentTracker{
+		pending: map[string]*pendingCRD{},
+		runs:    map[types.UID]*runLatencies{},
+	}
+}
+
+// started records the time the Create call for a CRD of a run was issued and how long it took
+func (t *establishmentTracker) started(reconTest *examplev1alpha1.ReconTest, crdName string, created time.Time,
+	createCall time.Duration, recreated bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timing := &crdTiming{name: crdName, created: created, createCall: createCall, recreated: recreated}
+	latencies := t.latencies(reconTest.UID)
+	latencies.crds = append(latencies.crds, timing)
+	t.pending[crdName] = &pendingCRD{
+		run:       reconTest.UID,
+		owner:     reconTest.Namespace + "/" + reconTest.Name,
+		created:   created,
+		recreated: recreated,
+		timing:    timing,
+	}
+}
+
+// latencies returns the samples of a run, creating them on first use. The caller holds mu.
+func (t *establishmentTracker) latencies(run types.UID) *runLatencies {
+	latencies := t.runs[run]
+	if latencies == nil {
+		latencies = &runLatencies{}
+		t.runs[run] = latencies
+	}
+	return latencies
+}
+
+// observe records any conditions of crd that turned True since it was created
+func (t *establishmentTracker) observe(crd *v1.CustomResourceDefinition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.pending[crd.Name]
+	if !ok {
+		return
+	}
+	latencies := t.latencies(p.run)
+
+	now := time.Now()
+	if !p.namesAccepted && apihelpers.IsCRDConditionTrue(crd, v1.NamesAccepted) {
+		p.namesAccepted = true
+		elapsed := now.Sub(p.created)
+		p.timing.namesAccepted = elapsed
+		latencies.namesAccepted = append(latencies.namesAccepted, elapsed)
+		crdNamesAcceptedSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+	}
+	if apihelpers.IsCRDConditionTrue(crd, v1.Established) {
+		elapsed := now.Sub(p.created)
+		p.timing.established = elapsed
+		latencies.established = append(latencies.established, elapsed)
+		crdEstablishedSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+		if p.recreated {
+			latencies.reestablished = append(latencies.reestablished, elapsed)
+			crdRecreateToEstablishedSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+		}
+		delete(t.pending, crd.Name)
+	}
+}
+
+// forget drops a CRD that was deleted before it was established
+func (t *establishmentTracker) forget(crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.pending, crdName)
+}
+
+// pendingCount returns the number of created CRDs of a run that are not established yet
+func (t *establishmentTracker) pendingCount(run types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, crd := range t.pending {
+		if crd.run == run {
+			count++
+		}
+	}
+	return count
+}
+
+// summary returns the establishment latency percentiles of a run, or nil when it has no samples
+func (t *establishmentTracker) summary(run types.UID) *examplev1alpha1.EstablishmentLatency {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latencies := t.runs[run]
+	if latencies == nil {
+		return nil
+	}
+	return &examplev1alpha1.EstablishmentLatency{
+		NamesAccepted: latencySummary(latencies.namesAccepted),
+		Established:   latencySummary(latencies.established),
+	}
+}
+
+// recreateSummary returns the establishment latency percentiles of the CRDs of a run
+// recreated after churn, or nil when it has no samples
+func (t *establishmentTracker) recreateSummary(run types.UID) *examplev1alpha1.LatencySummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latencies := t.runs[run]
+	if latencies == nil || len(latencies.reestablished) == 0 {
+		return nil
+	}
+	summary := latencySummary(latencies.reestablished)
+	return &summary
+}
+
+// samples returns a copy of the NamesAccepted and Established latency samples of a run
+func (t *establishmentTracker) samples(run types.UID) (namesAccepted, established []time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latencies := t.runs[run]
+	if latencies == nil {
+		return nil, nil
+	}
+	return append([]time.Duration(nil), latencies.namesAccepted...),
+		append([]time.Duration(nil), latencies.established...)
+}
+
+// timings returns the timings of every Create call of a run, in the order they were issued
+func (t *establishmentTracker) timings(run types.UID) []report.CRD {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	latencies := t.runs[run]
+	if latencies == nil {
+		return nil
+	}
+	timings := make([]report.CRD, 0, len(latencies.crds))
+	for _, timing := range latencies.crds {
+		timings = append(timings, report.CRD{
+			Name:                 timing.name,
+			Created:              timing.created,
+			CreateSeconds:        timing.createCall.Seconds(),
+			NamesAcceptedSeconds: optionalSeconds(timing.namesAccepted),
+			EstablishedSeconds:   optionalSeconds(timing.established),
+			Recreated:            timing.recreated,
+		})
+	}
+	return timings
+}
+
+// optionalSeconds converts a measured duration into seconds, or nil when it was not measured
+func optionalSeconds(d time.Duration) *float64 {
+	if d == 0 {
+		return nil
+	}
+	seconds := d.Seconds()
+	return &seconds
+}
+
+// clear drops every sample kept for a run
+func (t *establishmentTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.runs, run)
+	for name, p := range t.pending {
+		if p.run == run {
+			delete(t.pending, name)
+		}
+	}
+}
+
+// trackEstablishment starts measuring the establishment of a CRD whose Create call returned. The
+// informer may have delivered the events of its conditions before, when nothing awaited them, so
+// the cached CRD is checked once it is pending: the cache is updated before its events are handled.
+func (r *ReconTestReconciler) trackEstablishment(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	crdName string, created time.Time, createCall time.Duration, recreated bool) {
+	r.establishment.started(reconTest, crdName, created, createCall, recreated)
+
+	cached := &v1.CustomResourceDefinition{}
+	if err := r.Get(ctx, client.ObjectKey{Name: crdName}, cached); err == nil {
+		r.establishment.observe(cached)
+	}
+}
+
+// eventHandler feeds CRD informer events into the tracker
+func (t *establishmentTracker) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.observe(crd)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.observe(crd)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.forget(crd.Name)
+			}
+		},
+	}
+}
+
+// latencySummary converts samples into the status representation of their percentiles
+func latencySummary(samples []time.Duration) examplev1alpha1.LatencySummary {
+	return statusSummary(stats.Summarize(samples))
+}
+
+// reservoirSummary converts the samples kept by a reservoir into the status representation of their percentiles
+func reservoirSummary(samples *stats.Reservoir[time.Duration]) examplev1alpha1.LatencySummary {
+	return statusSummary(stats.SummarizeReservoir(samples))
+}
+
+// statusSummary converts summary into its status representation
+func statusSummary(summary stats.Summary) examplev1alpha1.LatencySummary {
+	return examplev1alpha1.LatencySummary{
+		Samples: int32(summary.Count),
+		P50:     metav1.Duration{Duration: summary.P50},
+		P90:     metav1.Duration{Duration: summary.P90},
+		P99:     metav1.Duration{Duration: summary.P99},
+	}
+}
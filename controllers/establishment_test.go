@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// crdWithConditions returns a CRD with the given conditions set to True
+func crdWithConditions(name string,
+	conditions ...v1.CustomResourceDefinitionConditionType) *v1.CustomResourceDefinition {
+	crd := &v1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	for _, condition := range conditions {
+		crd.Status.Conditions = append(crd.Status.Conditions, v1.CustomResourceDefinitionCondition{
+			Type:   condition,
+			Status: v1.ConditionTrue,
+		})
+	}
+	return crd
+}
+
+func TestEstablishmentTrackerSummary(t *testing.T) {
+	tracker := newEstablishmentTracker()
+	run := testRun()
+	if tracker.summary(run.UID) != nil {
+		t.Error("expected no summary before any CRD was created")
+	}
+
+	created := time.Now().Add(-time.Second)
+	tracker.started(run, "a.example.io", created, time.Millisecond, false)
+	tracker.started(run, "b.example.io", created, time.Millisecond, true)
+	if pending := tracker.pendingCount(run.UID); pending != 2 {
+		t.Errorf("expected 2 pending CRDs, got %d", pending)
+	}
+
+	tracker.observe(crdWithConditions("a.example.io", v1.NamesAccepted))
+	tracker.observe(crdWithConditions("a.example.io", v1.NamesAccepted, v1.Established))
+	tracker.observe(crdWithConditions("b.example.io", v1.NamesAccepted, v1.Established))
+	tracker.observe(crdWithConditions("unknown.example.io", v1.Established))
+
+	summary := tracker.summary(run.UID)
+	if summary == nil || summary.NamesAccepted.Samples != 2 || summary.Established.Samples != 2 {
+		t.Fatalf("expected 2 samples of each condition, got %+v", summary)
+	}
+	if summary.Established.P50.Duration < time.Second {
+		t.Errorf("expected the latency to be measured from creation, got %s", summary.Established.P50.Duration)
+	}
+	if recreated := tracker.recreateSummary(run.UID); recreated == nil || recreated.Samples != 1 {
+		t.Errorf("expected 1 recreated sample, got %+v", recreated)
+	}
+	if pending := tracker.pendingCount(run.UID); pending != 0 {
+		t.Errorf("expected no pending CRDs, got %d", pending)
+	}
+
+	timings := tracker.timings(run.UID)
+	if len(timings) != 2 || timings[0].EstablishedSeconds == nil || !timings[1].Recreated {
+		t.Errorf("unexpected timings %+v", timings)
+	}
+
+	tracker.clear(run.UID)
+	if tracker.summary(run.UID) != nil {
+		t.Error("expected the samples of a cleared run to be dropped")
+	}
+}
+
+func TestEstablishmentTrackerForget(t *testing.T) {
+	tracker := newEstablishmentTracker()
+	run := testRun()
+	tracker.started(run, "a.example.io", time.Now(), time.Millisecond, false)
+	tracker.forget("a.example.io")
+	tracker.observe(crdWithConditions("a.example.io", v1.Established))
+
+	if pending := tracker.pendingCount(run.UID); pending != 0 {
+		t.Errorf("expected a forgotten CRD not to be pending, got %d", pending)
+	}
+	if summary := tracker.summary(run.UID); summary == nil || summary.Established.Samples != 0 {
+		t.Errorf("expected no sample for a forgotten CRD, got %+v", summary)
+	}
+}
+
+func TestTrackEstablishmentAfterTheEvents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	crd := crdWithConditions("a.example.io", v1.NamesAccepted, v1.Established)
+	r := &ReconTestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(crd).Build(),
+		establishment: newEstablishmentTracker(),
+	}
+	run := testRun()
+
+	// The informer delivers the conditions before the result of the Create call is handled
+	r.establishment.observe(crd)
+	r.trackEstablishment(context.Background(), run, crd.Name, time.Now().Add(-time.Second), time.Millisecond, false)
+
+	if pending := r.establishment.pendingCount(run.UID); pending != 0 {
+		t.Errorf("expected the CRD not to be pending, got %d pending", pending)
+	}
+	summary := r.establishment.summary(run.UID)
+	if summary == nil || summary.NamesAccepted.Samples != 1 || summary.Established.Samples != 1 {
+		t.Errorf("expected a sample of each condition, got %+v", summary)
+	}
+}
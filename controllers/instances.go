@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/synth"
+)
+
+// instancesRequeue is how soon a run with CRDs still waiting for their custom resources is reconciled again
+const instancesRequeue = time.Second * 5
+
+// instanceTracker remembers the CRDs whose custom resources all exist and the
+// latency of the Create calls that made them
+type instanceTracker struct {
+	mu sync.Mutex
+	// populated holds the run of every CRD whose custom resources all exist
+	populated map[string]types.UID
+	latencies map[types.UID][]time.Duration
+}
+
+func newInstanceTracker() *instanceTracker {
+	return &instanceTracker{
+		populated: map[string]types.UID{},
+		latencies: map[types.UID][]time.Duration{},
+	}
+}
+
+// isPopulated reports whether every custom resource of crdName exists
+func (t *instanceTracker) isPopulated(crdName string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, ok := t.populated[crdName]
+	return ok
+}
+
+// populate records that every custom resource of a CRD of the run exists
+func (t *instanceTracker) populate(run types.UID, crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.populated[crdName] = run
+}
+
+// created records the latency of a successful Create call for a custom resource of a run
+func (t *instanceTracker) created(reconTest *examplev1alpha1.ReconTest, elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.latencies[reconTest.UID] = append(t.latencies[reconTest.UID], elapsed)
+	instanceCreateSeconds.WithLabelValues(reconTest.Namespace + "/" + reconTest.Name).Observe(elapsed.Seconds())
+}
+
+// forget drops a CRD that is gone together with its custom resources
+func (t *instanceTracker) forget(crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.populated, crdName)
+}
+
+// count returns the number of CRDs of a run whose custom resources all exist
+func (t *instanceTracker) count(run types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, populatedRun := range t.populated {
+		if populatedRun == run {
+			count++
+		}
+	}
+	return count
+}
+
+// summary returns the create latency percentiles of a run, or nil when it has no samples
+func (t *instanceTracker) summary(run types.UID) *examplev1alpha1.LatencySummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := t.latencies[run]
+	if len(samples) == 0 {
+		return nil
+	}
+	summary := latencySummary(samples)
+	return &summary
+}
+
+// samples returns a copy of the create latency samples of a run
+func (t *instanceTracker) samples(run types.UID) []time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]time.Duration(nil), t.latencies[run]...)
+}
+
+// clear drops everything kept for a run
+func (t *instanceTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.latencies, run)
+	for name, populatedRun := range t.populated {
+		if populatedRun == run {
+			delete(t.populated, name)
+		}
+	}
+}
+
+// eventHandler feeds CRD informer delete events into the tracker
+func (t *instanceTracker) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.forget(crd.Name)
+			}
+		},
+	}
+}
+
+// instanceJob is one custom resource to create
+type instanceJob struct {
+	crd   *v1.CustomResourceDefinition
+	index int
+}
+
+// createInstances creates the custom resources of every established CRD of the run that does not
+// have them all yet, at the concurrency and rate set in spec. Failed creations are retried, given
+// up or abort the run in pass as policy decides. It returns the status of the instances of the
+// run and whether some CRDs are still waiting for theirs.
+func (r *ReconTestReconciler) createInstances(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec, policy *retry.Policy,
+	pass *crdPass) (*examplev1alpha1.InstanceStatus, bool, error) {
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return nil, false, err
+	}
+
+	perCRD := int(spec.Instances.PerCRD)
+	namespace := ""
+	if spec.Scope == examplev1alpha1.NamespacedScope {
+		namespace = instancesNamespace(reconTest, spec)
+	}
+
+	waiting := false
+	jobs := make([]instanceJob, 0)
+	for i := range crds {
+		crd := &crds[i]
+		if crd.DeletionTimestamp != nil || r.instances.isPopulated(crd.Name) {
+			continue
+		}
+		// Custom resources cannot be served before their CRD is established
+		if !apihelpers.IsCRDConditionTrue(crd, v1.Established) {
+			waiting = true
+			continue
+		}
+		for j := 0; j < perCRD; j++ {
+			jobs = append(jobs, instanceJob{crd: crd, index: j})
+		}
+	}
+
+	failed := map[string]int{}
+	elapsed := make([]time.Duration, len(jobs))
+
+	pass.abort(r.runBatch(ctx, reconTest, policy, batch{
+		operation: opCreateInstance,
+		cfg:       loadConfig(spec.Instances.Rate),
+		n:         len(jobs),
+		request: func(ctx context.Context, i int) error {
+			obj, err := instanceFor(reconTest, jobs[i].crd, crdgen.InstancesVersion(spec), namespace, jobs[i].index)
+			if err != nil {
+				return err
+			}
+
+			// The dynamic client needs no discovery of the new kind, unlike the REST mapper of r
+			resource := r.dynamic.Resource(instanceResource(jobs[i].crd, crdgen.InstancesVersion(spec)))
+			start := time.Now()
+			_, err = resource.Namespace(namespace).Create(ctx, obj, metav1.CreateOptions{})
+			elapsed[i] = time.Since(start)
+			return err
+		},
+		result: func(i int, err error) error {
+			crdName := jobs[i].crd.Name
+			if _, ok := failed[crdName]; !ok {
+				failed[crdName] = 0
+			}
+			switch {
+			case err == nil:
+				r.instances.created(reconTest, elapsed[i])
+			case apierrors.IsAlreadyExists(err):
+			default:
+				logger.Error(err, fmt.Sprintf("Failed to create custom resource %d of CRD: %s", jobs[i].index, crdName))
+				failed[crdName]++
+				return err
+			}
+			return nil
+		},
+		skipped: func(i int) {
+			failed[jobs[i].crd.Name]++
+		},
+	}))
+
+	failures := 0
+	for crdName, count := range failed {
+		if count == 0 {
+			r.instances.populate(reconTest.UID, crdName)
+			continue
+		}
+		failures += count
+		waiting = true
+	}
+
+	return &examplev1alpha1.InstanceStatus{
+		Desired:       spec.Count * spec.Instances.PerCRD,
+		Created:       int32(r.instances.count(reconTest.UID)) * spec.Instances.PerCRD,
+		Failed:        int32(failures),
+		CreateLatency: r.instances.summary(reconTest.UID),
+	}, waiting, nil
+}
+
+// instancesNamespace returns the namespace of the custom resources a run creates for namespaced CRDs
+func instancesNamespace(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec) string {
+	if spec.Instances != nil && spec.Instances.Namespace != "" {
+		return spec.Instances.Namespace
+	}
+	return reconTest.Namespace
+}
+
+// instanceResource returns the resource of the custom resources of crd in version
+func instanceResource(crd *v1.CustomResourceDefinition, version string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: crd.Spec.Group, Version: version, Resource: crd.Spec.Names.Plural}
+}
+
+// instanceFor returns the custom resource with the given index of crd in versionName, whose
+// content is synthesized from the schema of that version
+func instanceFor(reconTest *examplev1alpha1.ReconTest, crd *v1.CustomResourceDefinition,
+	versionName, namespace string, index int) (*unstructured.Unstructured, error) {
+	var version *v1.CustomResourceDefinitionVersion
+	for i := range crd.Spec.Versions {
+		if crd.Spec.Versions[i].Name == versionName {
+			version = &crd.Spec.Versions[i]
+		}
+	}
+	if version == nil || version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+		return nil, fmt.Errorf("CRD %s has no schema for version %s", crd.Name, versionName)
+	}
+
+	name := fmt.Sprintf("%s-%d", crd.Spec.Names.Singular, index)
+
+	// Seed from the name so every custom resource keeps its content across passes
+	seed := fnv.New64a()
+	_, _ = seed.Write([]byte(crd.Name + "/" + name))
+	obj, err := synth.Object(version.Schema.OpenAPIV3Schema, int64(seed.Sum64()))
+	if err != nil {
+		return nil, fmt.Errorf("synthesizing custom resource for CRD %s: %w", crd.Name, err)
+	}
+	obj.SetAPIVersion(crd.Spec.Group + "/" + version.Name)
+	obj.SetKind(crd.Spec.Names.Kind)
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetLabels(map[string]string{examplev1alpha1.RunLabel: string(reconTest.UID)})
+	return obj, nil
+}
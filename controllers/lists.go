@@ -0,0 +1,326 @@
+package controllers
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/lists"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+// listTick is how often the list load of new or changed runs is started
+const listTick = time.Second
+
+// listRequeue is how soon a run with a list load is reconciled again to report it
+const listRequeue = time.Second * 15
+
+// listIdle is how long a list worker waits when the run has no established kind to list yet
+const listIdle = time.Second
+
+// listSamples holds the list calls made against one target
+type listSamples struct {
+	lists     int64
+	requests  int64
+	errors    int64
+	latencies stats.Reservoir[time.Duration]
+	bytes     stats.Reservoir[int64]
+	// byCRDs holds the latencies by the number of CRDs of the run, rounded down to the bucket width
+	byCRDs map[int32]*stats.Reservoir[time.Duration]
+}
+
+// listRun holds the list settings, listed kinds and samples of one run
+type listRun struct {
+	uid        types.UID
+	owner      string
+	generation int64
+	settings   examplev1alpha1.ListSpec
+	selector   string
+	// bucketWidth is a tenth of the desired number of CRDs of the run
+	bucketWidth int32
+	// paths are the collections of the established kinds of the run
+	paths []string
+	// resourceVersions holds the resource version of the last list of every collection
+	resourceVersions map[string]string
+	samples          map[examplev1alpha1.ListTarget]*listSamples
+	// stop stops the list load of the run, it is nil until the load is started
+	stop context.CancelFunc
+}
+
+// listTracker runs the list load of every run and measures the latency and size of its list
+// requests against the number of CRDs the run had at the time. It runs as a manager runnable
+// that starts the list load of every run and restarts it when the run changes.
+type listTracker struct {
+	client *lists.Client
+	log    logr.Logger
+
+	mu   sync.Mutex
+	runs map[types.UID]*listRun
+	// crds holds the number of CRDs of every run, followed through the CRD informer
+	crds map[types.UID]int32
+}
+
+func newListTracker(client *lists.Client, log logr.Logger) *listTracker {
+	return &listTracker{
+		client: client,
+		log:    log,
+		runs:   map[types.UID]*listRun{},
+		crds:   map[types.UID]int32{},
+	}
+}
+
+// sync sets the list settings of a run and the kinds it lists to those of its established crds.
+// The list load of the run is restarted when its spec changed.
+func (t *listTracker) sync(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	crds []v1.CustomResourceDefinition) error {
+	selector := ""
+	if spec.Lists.LabelSelector != nil {
+		labelSelector, err := metav1.LabelSelectorAsSelector(spec.Lists.LabelSelector)
+		if err != nil {
+			return err
+		}
+		selector = labelSelector.String()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run := t.runs[reconTest.UID]
+	if run == nil {
+		run = &listRun{
+			uid:              reconTest.UID,
+			owner:            reconTest.Namespace + "/" + reconTest.Name,
+			resourceVersions: map[string]string{},
+			samples:          map[examplev1alpha1.ListTarget]*listSamples{},
+		}
+		t.runs[reconTest.UID] = run
+	}
+	if run.generation != reconTest.Generation && run.stop != nil {
+		run.stop()
+		run.stop = nil
+	}
+	run.generation = reconTest.Generation
+	run.settings = *spec.Lists.DeepCopy()
+	run.selector = selector
+	run.bucketWidth = spec.Count / 10
+	if run.bucketWidth < 1 {
+		run.bucketWidth = 1
+	}
+
+	version := crdgen.InstancesVersion(spec)
+	run.paths = run.paths[:0]
+	for i := range crds {
+		crd := &crds[i]
+		if crd.DeletionTimestamp == nil && apihelpers.IsCRDConditionTrue(crd, v1.Established) {
+			run.paths = append(run.paths, "/apis/"+crd.Spec.Group+"/"+version+"/"+crd.Spec.Names.Plural)
+		}
+	}
+	sort.Strings(run.paths)
+	return nil
+}
+
+// summary returns the list calls of a run by target, or nil when it has no list load
+func (t *listTracker) summary(run types.UID) *examplev1alpha1.ListStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.runs[run]
+	if r == nil {
+		return nil
+	}
+	status := &examplev1alpha1.ListStatus{}
+	for _, target := range r.settings.Targets {
+		targetStatus := examplev1alpha1.ListTargetStatus{Target: target}
+		if samples := r.samples[target]; samples != nil {
+			targetStatus.Lists = samples.lists
+			targetStatus.Requests = samples.requests
+			targetStatus.Errors = samples.errors
+			if samples.latencies.Count() > 0 {
+				latency := reservoirSummary(&samples.latencies)
+				targetStatus.Latency = &latency
+			}
+			if samples.bytes.Count() > 0 {
+				sizes := samples.bytes.Sorted()
+				targetStatus.ResponseBytesP50 = sizes[(len(sizes)+1)/2-1]
+				targetStatus.ResponseBytesMax = samples.bytes.Max()
+			}
+			for crds, latencies := range samples.byCRDs {
+				targetStatus.ByCRDs = append(targetStatus.ByCRDs, examplev1alpha1.ListLatencyBucket{
+					CRDs:    crds,
+					Latency: reservoirSummary(latencies),
+				})
+			}
+			sort.Slice(targetStatus.ByCRDs, func(i, j int) bool {
+				return targetStatus.ByCRDs[i].CRDs < targetStatus.ByCRDs[j].CRDs
+			})
+		}
+		status.Targets = append(status.Targets, targetStatus)
+	}
+	return status
+}
+
+// clear stops the list load of a run and drops its samples
+func (t *listTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r := t.runs[run]; r != nil && r.stop != nil {
+		r.stop()
+	}
+	delete(t.runs, run)
+}
+
+// eventHandler feeds CRD informer events into the CRD counts of the runs
+func (t *listTracker) eventHandler() toolscache.ResourceEventHandler {
+	count := func(obj interface{}, delta int32) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		crd, ok := obj.(*v1.CustomResourceDefinition)
+		if !ok {
+			return
+		}
+		run, ok := crd.Labels[examplev1alpha1.RunLabel]
+		if !ok {
+			return
+		}
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		t.crds[types.UID(run)] += delta
+		if t.crds[types.UID(run)] <= 0 {
+			delete(t.crds, types.UID(run))
+		}
+	}
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { count(obj, 1) },
+		DeleteFunc: func(obj interface{}) { count(obj, -1) },
+	}
+}
+
+// Start runs the list load of every run until ctx is done
+func (t *listTracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(listTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.startLoads(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection runs the list load on the leader only, the replica that creates the CRDs
+func (t *listTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// startLoads starts the list load of the runs that have none running
+func (t *listTracker) startLoads(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, run := range t.runs {
+		if run.stop != nil {
+			continue
+		}
+		loadCtx, stop := context.WithCancel(ctx)
+		run.stop = stop
+		engine := load.NewEngine(loadConfig(run.settings.Rate))
+		go engine.Run(loadCtx, math.MaxInt, func(ctx context.Context, i int) error {
+			return t.list(ctx, run, i)
+		}, func(int, error) {})
+	}
+}
+
+// list makes the i-th list of a run, going through its targets and the kinds of the run in turn
+func (t *listTracker) list(ctx context.Context, run *listRun, i int) error {
+	t.mu.Lock()
+	targets := run.settings.Targets
+	target := targets[i%len(targets)]
+	path := lists.CRDsPath
+	if target == examplev1alpha1.ListCustomResources {
+		if len(run.paths) == 0 {
+			t.mu.Unlock()
+			select {
+			case <-ctx.Done():
+			case <-time.After(listIdle):
+			}
+			return nil
+		}
+		path = run.paths[(i/len(targets))%len(run.paths)]
+	}
+	previous := run.resourceVersions[path]
+	opts := lists.Options{
+		Limit:           int64(*run.settings.PageSize),
+		ResourceVersion: run.settings.ResourceVersion,
+		LabelSelector:   run.selector,
+	}
+	samples := run.targetSamples(target)
+	t.mu.Unlock()
+
+	resourceVersion, err := t.client.List(ctx, path, previous, opts, func(page lists.Page) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		bucket := t.crds[run.uid] / run.bucketWidth * run.bucketWidth
+		samples.requests++
+		samples.latencies.Add(page.Latency)
+		samples.bytes.Add(int64(page.Bytes))
+		if samples.byCRDs[bucket] == nil {
+			samples.byCRDs[bucket] = &stats.Reservoir[time.Duration]{}
+		}
+		samples.byCRDs[bucket].Add(page.Latency)
+		listSeconds.WithLabelValues(run.owner, string(target)).Observe(page.Latency.Seconds())
+		listResponseBytes.WithLabelValues(run.owner, string(target)).Observe(float64(page.Bytes))
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		t.log.V(1).Info("List failed", "recontest", run.owner, "path", path, "error", err.Error())
+		samples.requests++
+		samples.errors++
+		listErrorsTotal.WithLabelValues(run.owner, string(target)).Inc()
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+			// The resource version was compacted, start again from the most recent one
+			delete(run.resourceVersions, path)
+		}
+		return err
+	}
+	samples.lists++
+	run.resourceVersions[path] = resourceVersion
+	return nil
+}
+
+// targetSamples returns the samples of target, creating them on first use. The caller holds mu.
+func (r *listRun) targetSamples(target examplev1alpha1.ListTarget) *listSamples {
+	samples := r.samples[target]
+	if samples == nil {
+		samples = &listSamples{byCRDs: map[int32]*stats.Reservoir[time.Duration]{}}
+		r.samples[target] = samples
+	}
+	return samples
+}
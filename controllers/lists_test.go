@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+func TestListTrackerSummaryIsBounded(t *testing.T) {
+	tracker := newListTracker(nil, logr.Discard())
+	run := &listRun{
+		settings: examplev1alpha1.ListSpec{Targets: []examplev1alpha1.ListTarget{examplev1alpha1.ListCRDs}},
+		samples:  map[examplev1alpha1.ListTarget]*listSamples{},
+	}
+	tracker.runs[types.UID("run-uid")] = run
+
+	const pages = 4 * stats.ReservoirSize
+	samples := run.targetSamples(examplev1alpha1.ListCRDs)
+	for i := 1; i <= pages; i++ {
+		samples.requests++
+		samples.latencies.Add(time.Duration(i) * time.Millisecond)
+		samples.bytes.Add(int64(i))
+		if samples.byCRDs[0] == nil {
+			samples.byCRDs[0] = &stats.Reservoir[time.Duration]{}
+		}
+		samples.byCRDs[0].Add(time.Duration(i) * time.Millisecond)
+	}
+	if kept := len(samples.latencies.Sorted()); kept != stats.ReservoirSize {
+		t.Errorf("expected %d latencies kept, got %d", stats.ReservoirSize, kept)
+	}
+
+	status := tracker.summary("run-uid")
+	if status == nil || len(status.Targets) != 1 {
+		t.Fatalf("expected the status of one target, got %+v", status)
+	}
+	target := status.Targets[0]
+	if target.Latency == nil || target.Latency.Samples != pages {
+		t.Errorf("expected a latency of %d samples, got %+v", pages, target.Latency)
+	}
+	if target.ResponseBytesMax != pages {
+		t.Errorf("expected the largest response of %d bytes, got %d", pages, target.ResponseBytesMax)
+	}
+	if len(target.ByCRDs) != 1 || target.ByCRDs[0].Latency.Samples != pages {
+		t.Errorf("expected one bucket of %d samples, got %+v", pages, target.ByCRDs)
+	}
+}
@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/conversion"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/series"
+)
+
+// latencyBuckets covers latencies from 50ms to roughly 100s
+var latencyBuckets = prometheus.ExponentialBuckets(0.05, 2, 12)
+
+var (
+	crdNamesAcceptedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_crd_names_accepted_seconds",
+		Help:    "Time from the Create call of a generated CRD until its NamesAccepted condition is True.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	crdEstablishedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_crd_established_seconds",
+		Help:    "Time from the Create call of a generated CRD until its Established condition is True.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	crdDeleteToGoneSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_crd_delete_to_gone_seconds",
+		Help:    "Time from the Delete call of a CRD deleted by churn until it is gone.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	crdRecreateToEstablishedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_crd_recreate_to_established_seconds",
+		Help:    "Time from the Create call of a CRD recreated after churn until its Established condition is True.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	instanceCreateSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_instance_create_seconds",
+		Help:    "Duration of the Create call of a custom resource of a generated CRD.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	crdDriftTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_crd_drift_total",
+		Help: "Number of times a generated CRD was found to differ from its intended spec, by drift policy applied.",
+	}, []string{"recontest", "policy"})
+
+	crdDrifted = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "recontest_crd_drifted",
+		Help: "Number of generated CRDs that differed from their intended spec during the last pass.",
+	}, []string{"recontest"})
+
+	requestErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_request_errors_total",
+		Help: "Number of API requests of a run that failed, by operation and error class.",
+	}, []string{"recontest", "operation", "class"})
+
+	requestRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_request_retries_total",
+		Help: "Number of failed API requests of a run that were retried, by operation and error class.",
+	}, []string{"recontest", "operation", "class"})
+
+	assertionPassed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "recontest_assertion_passed",
+		Help: "Verdict of an assertion of a run: 1 when it passed, 0 when it failed, -1 while it is pending.",
+	}, []string{"recontest", "assertion"})
+
+	baselineRegressions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "recontest_baseline_regressions",
+		Help: "Number of metrics of a run that regressed against its baseline, -1 when the baseline cannot be read.",
+	}, []string{"recontest"})
+
+	discoverySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "recontest_discovery_seconds",
+		Help: "Time from the Create or Delete call of a generated CRD until a discovery or OpenAPI endpoint " +
+			"published or stopped publishing its kind, by endpoint and transition.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest", "endpoint", "transition"})
+
+	discoveryTimeoutsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_discovery_timeouts_total",
+		Help: "Number of kinds that did not appear on or disappear from an endpoint within the discovery timeout.",
+	}, []string{"recontest", "endpoint", "transition"})
+
+	watchDeliverySeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "recontest_watch_delivery_seconds",
+		Help: "Time from the write of the watch probe of a generated kind until a watcher of the kind " +
+			"saw its event.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	watchesEndedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_watches_ended_total",
+		Help: "Number of watches on the generated kinds that ended and were reopened, by reason.",
+	}, []string{"recontest", "reason"})
+
+	watchersOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "recontest_watchers",
+		Help: "Number of watchers open on the generated kinds of a run.",
+	}, []string{"recontest"})
+
+	listSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_list_seconds",
+		Help:    "Latency of the list requests of a run, one per page, by target.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"recontest", "target"})
+
+	listResponseBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_list_response_bytes",
+		Help:    "Size of the responses to the list requests of a run, by target.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+	}, []string{"recontest", "target"})
+
+	listErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_list_errors_total",
+		Help: "Number of list requests of a run that failed, by target.",
+	}, []string{"recontest", "target"})
+
+	schemaUpdatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_schema_updates_total",
+		Help: "Number of schema churn updates of generated CRDs the API server accepted, by change.",
+	}, []string{"recontest", "change"})
+
+	schemaUpdateToEstablishedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "recontest_schema_update_to_established_seconds",
+		Help: "Time from the Update call of a schema churn update until its generation was seen with " +
+			"Established True.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	schemaUpdateToPublishedSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "recontest_schema_update_to_published_seconds",
+		Help: "Time from the Update call of a schema churn update until OpenAPI v3 published the new " +
+			"revision of the schema.",
+		Buckets: latencyBuckets,
+	}, []string{"recontest"})
+
+	schemaPublishTimeoutsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_schema_publish_timeouts_total",
+		Help: "Number of schema churn updates OpenAPI v3 did not publish within the publication timeout.",
+	}, []string{"recontest"})
+
+	crdWriteSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_crd_write_seconds",
+		Help:    "Latency of the CRD writes of a run, by field manager.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"recontest", "manager"})
+
+	crdWriteConflictsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_crd_write_conflicts_total",
+		Help: "Number of CRD writes of a run that failed with a conflict, by field manager.",
+	}, []string{"recontest", "manager"})
+
+	crdManagedFieldsBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "recontest_crd_managed_fields_bytes",
+		Help: "Largest size of the managedFields of a CRD of a run, as of its last pass.",
+	}, []string{"recontest"})
+
+	discoveryFetchSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_discovery_fetch_seconds",
+		Help:    "Time taken to fetch and decode a discovery or OpenAPI document, by endpoint.",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"endpoint"})
+)
+
+func init() {
+	// Register with the controller-runtime registry served on --metrics-bind-address
+	metrics.Registry.MustRegister(
+		crdNamesAcceptedSeconds,
+		crdEstablishedSeconds,
+		crdDeleteToGoneSeconds,
+		crdRecreateToEstablishedSeconds,
+		instanceCreateSeconds,
+		crdDriftTotal,
+		crdDrifted,
+		requestErrorsTotal,
+		requestRetriesTotal,
+		assertionPassed,
+		baselineRegressions,
+		discoverySeconds,
+		discoveryTimeoutsTotal,
+		watchDeliverySeconds,
+		watchesEndedTotal,
+		watchersOpen,
+		listSeconds,
+		listResponseBytes,
+		listErrorsTotal,
+		discoveryFetchSeconds,
+		schemaUpdatesTotal,
+		schemaUpdateToEstablishedSeconds,
+		schemaUpdateToPublishedSeconds,
+		schemaPublishTimeoutsTotal,
+		crdWriteSeconds,
+		crdWriteConflictsTotal,
+		crdManagedFieldsBytes,
+	)
+}
+
+// runVecs holds every metric labelled with the <namespace>/<name> of a run in its recontest label
+var runVecs = []series.Vec{
+	crdNamesAcceptedSeconds,
+	crdEstablishedSeconds,
+	crdDeleteToGoneSeconds,
+	crdRecreateToEstablishedSeconds,
+	instanceCreateSeconds,
+	crdDriftTotal,
+	crdDrifted,
+	requestErrorsTotal,
+	requestRetriesTotal,
+	assertionPassed,
+	baselineRegressions,
+	discoverySeconds,
+	discoveryTimeoutsTotal,
+	watchDeliverySeconds,
+	watchesEndedTotal,
+	watchersOpen,
+	listSeconds,
+	listResponseBytes,
+	listErrorsTotal,
+	schemaUpdatesTotal,
+	schemaUpdateToEstablishedSeconds,
+	schemaUpdateToPublishedSeconds,
+	schemaPublishTimeoutsTotal,
+	crdWriteSeconds,
+	crdWriteConflictsTotal,
+	crdManagedFieldsBytes,
+}
+
+// deleteRunMetrics deletes the series of a deleted run, given as <namespace>/<name>, so they
+// are not exported for as long as the operator runs
+func deleteRunMetrics(owner string) {
+	series.Delete("recontest", owner, runVecs...)
+	conversion.DeleteRunMetrics(owner)
+}
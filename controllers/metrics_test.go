@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDeleteRunMetrics(t *testing.T) {
+	crdDrifted.WithLabelValues("default/deleted").Set(3)
+	crdDrifted.WithLabelValues("default/kept").Set(1)
+	assertionPassed.WithLabelValues("default/deleted", "p99").Set(1)
+	requestErrorsTotal.WithLabelValues("default/deleted", opCreateCRD, "Conflict").Inc()
+	listSeconds.WithLabelValues("default/deleted", "all").Observe(0.1)
+	vecs := map[string]func() int{
+		"drifted":        func() int { return testutil.CollectAndCount(crdDrifted) },
+		"assertion":      func() int { return testutil.CollectAndCount(assertionPassed) },
+		"request errors": func() int { return testutil.CollectAndCount(requestErrorsTotal) },
+		"list seconds":   func() int { return testutil.CollectAndCount(listSeconds) },
+	}
+	before := map[string]int{}
+	for name, count := range vecs {
+		before[name] = count()
+	}
+
+	deleteRunMetrics("default/deleted")
+
+	// Each vector held one series of the deleted run
+	for name, count := range vecs {
+		if got := count(); got != before[name]-1 {
+			t.Errorf("expected the %s series of the deleted run, and only it, to be gone: %d left of %d",
+				name, got, before[name])
+		}
+	}
+}
@@ -2,317 +2,697 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/go-logr/logr"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientdiscovery "k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/discovery"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/lists"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/scenario"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/slo"
 )
 
 // ReconTestReconciler reconciles a ReconTest object
 type ReconTestReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// ConversionWebhook is where the API server reaches the conversion webhook of the
+	// operator. Runs that ask for webhook conversion are rejected when it is nil.
+	ConversionWebhook *crdgen.WebhookConfig
+	// ConversionCABundleFile is the PEM file the CA bundle of ConversionWebhook is read from
+	// on every pass, so a rotated serving certificate reaches the existing CRDs. The bundle
+	// of ConversionWebhook is used as it is when it is empty.
+	ConversionCABundleFile string
+	// ReportDir is the directory reports are written to. Runs that ask for their report
+	// in a directory are rejected when it is empty.
+	ReportDir string
+
+	// establishment measures the establishment latency of created CRDs
+	establishment *establishmentTracker
+	// churnTracker follows the CRDs deleted by churn
+	churnTracker *churnTracker
+	// schemaChurn follows the CRDs updated by schema churn
+	schemaChurn *schemaChurnTracker
+	// instances remembers the CRDs whose custom resources were created
+	instances *instanceTracker
+	// dynamic creates the custom resources of the generated kinds, whose REST mappings the
+	// client of the reconciler would have to discover first
+	dynamic dynamic.Interface
+	// discovery measures how long the generated kinds take to be published to clients
+	discovery *discoveryTracker
+	// watches keeps the watchers of every run open on its kinds
+	watches *watchTracker
+	// lists runs the list load of every run
+	lists *listTracker
+	// writes measures the CRD writes of every run by field manager
+	writes *writeTracker
+	// requests counts the API requests of every run and their errors
+	requests *requestTracker
+	// serverVersion reads the version of the cluster for the reports
+	serverVersion clientdiscovery.ServerVersionInterface
+	// apiReader reads baseline reports without caching every ConfigMap of the cluster
+	apiReader client.Reader
 }
 
-//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=create;get;list;update;watch;delete
+//+kubebuilder:rbac:groups=example.anirudh.io,resources=recontests,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=example.anirudh.io,resources=recontests/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=example.anirudh.io,resources=recontests/finalizers,verbs=update
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=create;get;list;update;patch;watch;delete
+// The instances of the generated kinds, in the default group of the runs. Runs in other groups need
+// the opt-in generated-kinds-role in config/rbac.
+//+kubebuilder:rbac:groups=example.anirudh.io,resources=*,verbs=create;list;watch;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;patch
+//+kubebuilder:rbac:urls=/apis;/apis/*;/openapi/v2;/openapi/v3;/openapi/v3/*,verbs=get
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *ReconTestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
-	// Generate and create CRDs
-	return r.createAllCRDs(ctx, logger)
-}
+	reconTest := &examplev1alpha1.ReconTest{}
+	if err := r.Get(ctx, req.NamespacedName, reconTest); err != nil {
+		// The ReconTest may have been deleted since the request was queued
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// Apply the cleanup policy of a deleted run before letting it go
+	if !reconTest.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, logger, reconTest)
+	}
+
+	// Make sure the run can be cleaned up before creating anything for it
+	if !controllerutil.ContainsFinalizer(reconTest, examplev1alpha1.CleanupFinalizer) {
+		controllerutil.AddFinalizer(reconTest, examplev1alpha1.CleanupFinalizer)
+		if err := r.Update(ctx, reconTest); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-// createAllCRDs generates and creates all CRDs
-func (r *ReconTestReconciler) createAllCRDs(ctx context.Context, logger logr.Logger) (ctrl.Result, error) {
-	// Number of CRDs to generate
-	numCRDs := 1000
+	spec := crdgen.WithDefaults(reconTest.Spec)
 
-	// Track if any creation errors occurred
-	var lastErr error
+	// Record a newly seen run as pending before doing any work for it
+	if reconTest.Status.Phase == "" {
+		err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			status.ObservedGeneration = reconTest.Generation
+			status.Phase = examplev1alpha1.PhasePending
+			status.Desired = spec.Count
+		})
+		return ctrl.Result{Requeue: true}, err
+	}
 
-	for i := 1; i <= numCRDs; i++ {
-		// Construct CRD name
-		crdName := fmt.Sprintf("complexrecontests%d.example.anirudh.io", i)
+	schema, err := r.intendedSchema(spec)
+	if err != nil {
+		// Retrying cannot help until the spec changes
+		logger.Error(err, "Invalid spec")
+		return ctrl.Result{}, r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			markInvalidSpec(status, reconTest.Generation, err)
+		})
+	}
 
-		logger.Info(fmt.Sprintf("Creating CRD %s", crdName))
+	// An aborted run waits for its spec to change
+	if isAborted(reconTest) {
+		return ctrl.Result{}, nil
+	}
+	policy := retry.NewPolicy(spec.RetryPolicy)
+	if spec.DryRun {
+		return r.dryRun(ctx, logger, reconTest, spec, schema, policy)
+	}
 
-		// Create CRD object
-		crd := r.generateComplexCRD(i, crdName)
+	// A scenario runs its current phase with the spec the phases so far add up to
+	var stage *scenarioStage
+	if len(spec.Phases) > 0 {
+		if stage, err = r.currentStage(ctx, reconTest, spec); err != nil || stage == nil {
+			// The scenario ended, the run stays as it is until its spec changes
+			return ctrl.Result{}, err
+		}
+		spec = stage.spec
+		if schema, err = crdgen.Schema(spec); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
-		// Attempt to create CRD
-		if err := r.Create(ctx, crd); err != nil {
-			// Check if the error is due to the CRD already existing
-			if apierrors.IsAlreadyExists(err) {
-				// Log that the CRD already exists
-				logger.Info(fmt.Sprintf("CRD already exists: %s", crdName))
-				continue // Move to the next CRD
+	// Show that a pass is in progress unless the run is already steady or done at this generation
+	settled := reconTest.Status.Phase == examplev1alpha1.PhaseSteady ||
+		reconTest.Status.Phase == examplev1alpha1.PhaseCompleted
+	if !settled || reconTest.Status.ObservedGeneration != reconTest.Generation {
+		if err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			status.ObservedGeneration = reconTest.Generation
+			status.Phase = examplev1alpha1.PhaseCreating
+			status.Desired = spec.Count
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Generate and create the CRDs described by the ReconTest, unless the scenario has none now
+	var pass *crdPass
+	remaining := 0
+	switch {
+	case stage != nil && stage.phase.Type == examplev1alpha1.ScenarioDelete:
+		pass = &crdPass{}
+		if remaining, err = r.deleteRunCRDs(ctx, logger, reconTest, spec); err != nil {
+			return ctrl.Result{}, err
+		}
+	case stage != nil && !stage.hasCRDs:
+		pass = &crdPass{}
+	default:
+		if pass, err = r.createAllCRDs(ctx, logger, reconTest, spec, schema, policy); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Fill the established CRDs of the run with custom resources
+	var instances *examplev1alpha1.InstanceStatus
+	instancesWaiting := false
+	if spec.Instances != nil && pass.aborted == nil && (stage == nil || stage.hasCRDs) {
+		instances, instancesWaiting, err = r.createInstances(ctx, logger, reconTest, spec, policy, pass)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Follow the established kinds of the run with its watchers and list load
+	if spec.Watches != nil || spec.Lists != nil {
+		var crds []v1.CustomResourceDefinition
+		if stage == nil || stage.hasCRDs {
+			if crds, err = r.listRunCRDs(ctx, reconTest); err != nil {
+				return ctrl.Result{}, err
 			}
+		}
+		if spec.Watches != nil {
+			r.watches.sync(reconTest, spec, crds)
+		}
+		if spec.Lists != nil {
+			if err := r.lists.sync(reconTest, spec, crds); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	if spec.Watches == nil {
+		r.watches.clear(reconTest.UID)
+	}
+	if spec.Lists == nil {
+		r.lists.clear(reconTest.UID)
+	}
+	if spec.Writes == nil {
+		r.writes.clear(reconTest.UID)
+	}
 
-			// Log other errors
-			logger.Error(err, fmt.Sprintf("Failed to create complex CRD: %s", crdName))
-			lastErr = err
+	// A run without a scenario or a continuous load is done once its CRDs and instances are in place
+	// and every measurement of them has been taken
+	pass.done = stage == nil && !continuousLoad(spec) && !instancesWaiting &&
+		r.establishment.pendingCount(reconTest.UID) == 0 && r.discovery.pending(reconTest.UID) == 0
+
+	if err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		pass.applyTo(status, reconTest.Generation)
+		if latency := r.establishment.summary(reconTest.UID); latency != nil {
+			status.EstablishmentLatency = latency
+		}
+		if status.Churn != nil {
+			status.Churn.DeleteToGone = r.churnTracker.summary(reconTest.UID)
+			status.Churn.RecreateToEstablished = r.establishment.recreateSummary(reconTest.UID)
+		}
+		if status.SchemaChurn != nil {
+			status.SchemaChurn.UpdateToEstablished, status.SchemaChurn.UpdateToPublished,
+				status.SchemaChurn.PublishTimeouts = r.schemaChurn.summary(reconTest.UID)
+		}
+		status.Instances = instances
+		if published := r.discovery.summary(reconTest.UID); published != nil {
+			status.Discovery = published
+		}
+		status.Watches = r.watches.summary(reconTest.UID)
+		status.Lists = r.lists.summary(reconTest.UID)
+		if spec.Writes != nil {
+			if status.Writes == nil {
+				status.Writes = &examplev1alpha1.WriteStatus{}
+			}
+			r.writes.applyTo(reconTest.UID, spec.Writes, status.Writes)
 		} else {
-			logger.Info(fmt.Sprintf("Successfully created complex CRD: %s", crdName))
+			status.Writes = nil
+		}
+		if classes := r.requests.classSummary(reconTest.UID); len(classes) > 0 || pass.aborted != nil {
+			status.Errors = &examplev1alpha1.ErrorStatus{Classes: classes, Aborted: pass.aborted}
+		}
+		applyVerdict(status, r.evaluateAssertions(reconTest, spec, false), reconTest.Generation)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Move the scenario on once the current phase met its exit criteria
+	var stageAfter time.Duration
+	if stage != nil && pass.aborted == nil {
+		progress := scenario.Progress{
+			Desired:   pass.desired,
+			Present:   pass.created + pass.existing,
+			Drifted:   pass.drifted,
+			Remaining: remaining,
+		}
+		if instances != nil {
+			progress.InstancesDesired, progress.InstancesCreated = instances.Desired, instances.Created
+		}
+		if stageAfter, err = r.advanceScenario(ctx, reconTest, stage, progress); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Refresh the report of the run once it is due, failing passes included
+	var reportAfter time.Duration
+	if spec.Report != nil {
+		if reportAfter = reportDue(reconTest.Status, spec.Report, time.Now()); reportAfter == 0 {
+			if err := r.writeReport(ctx, reconTest, spec, false); err != nil {
+				return ctrl.Result{}, err
+			}
+			reportAfter = spec.Report.Interval.Duration
 		}
 	}
 
-	// If there were any errors, requeue with a delay
-	if lastErr != nil {
-		return ctrl.Result{
-			Requeue:      true,
-			RequeueAfter: time.Second * 30, // Requeue after 30 seconds to retry failed CRD creations
-		}, lastErr
+	// Retrying cannot help an aborted run until its spec changes
+	if pass.aborted != nil {
+		logger.Info(fmt.Sprintf("Run aborted by a %s error of %s", pass.aborted.Class, pass.aborted.Operation))
+		return ctrl.Result{}, nil
+	}
+
+	// If requests were given up, try them again on the next pass. Their errors are in status.
+	if pass.lastErr != nil {
+		requeueAfter := policy.RequeueAfter
+		if stage != nil && stageAfter < requeueAfter {
+			requeueAfter = stageAfter
+		}
+		return ctrl.Result{Requeue: true, RequeueAfter: requeueAfter}, nil
 	}
 
 	// Continuously requeue to keep trying to create CRDs
+	requeueAfter := time.Minute * 1 // Requeue every minute
+
+	// Churn a steady run once its next round is due
+	if spec.Churn != nil && reconTest.Status.Phase == examplev1alpha1.PhaseSteady {
+		due := churnDue(reconTest.Status, spec.Churn, time.Now())
+		if due == 0 {
+			if err := r.churn(ctx, logger, reconTest, spec, policy); err != nil {
+				return ctrl.Result{}, err
+			}
+			due = spec.Churn.Interval.Duration
+		}
+		if due < requeueAfter {
+			requeueAfter = due
+		}
+	}
+
+	// Compete for the fields of the CRDs of a steady run once the next round of the competitor is due
+	if spec.Writes != nil && spec.Writes.Competitor != nil && reconTest.Status.Phase == examplev1alpha1.PhaseSteady {
+		due := competitorDue(reconTest.Status, spec.Writes.Competitor, time.Now())
+		if due == 0 {
+			if err := r.compete(ctx, logger, reconTest, spec); err != nil {
+				return ctrl.Result{}, err
+			}
+			due = spec.Writes.Competitor.Interval.Duration
+		}
+		if due < requeueAfter {
+			requeueAfter = due
+		}
+	}
+
+	// Update the schemas of a steady run once its next schema churn round is due
+	if spec.SchemaChurn != nil && reconTest.Status.Phase == examplev1alpha1.PhaseSteady {
+		due := schemaChurnDue(reconTest.Status, spec.SchemaChurn, time.Now())
+		if due == 0 {
+			if err := r.churnSchemas(ctx, logger, reconTest, spec, policy); err != nil {
+				return ctrl.Result{}, err
+			}
+			due = spec.SchemaChurn.Interval.Duration
+		}
+		if due < requeueAfter {
+			requeueAfter = due
+		}
+	}
+
+	// Come back soon to report the schema updates still awaited
+	if r.schemaChurn.pendingCount(reconTest.UID) > 0 && schemaChurnRequeue < requeueAfter {
+		requeueAfter = schemaChurnRequeue
+	}
+
+	// Come back soon to recreate churned CRDs once they are gone
+	if r.churnTracker.pending(reconTest.UID) > 0 && churnRecreateRequeue < requeueAfter {
+		requeueAfter = churnRecreateRequeue
+	}
+
+	// Come back soon to fill the CRDs that were not established yet
+	if instancesWaiting && instancesRequeue < requeueAfter {
+		requeueAfter = instancesRequeue
+	}
+
+	// Come back soon to report the kinds still awaited on the discovery endpoints
+	if r.discovery.pending(reconTest.UID) > 0 && discoveryRequeue < requeueAfter {
+		requeueAfter = discoveryRequeue
+	}
+
+	// Come back soon to report the watchers
+	if spec.Watches != nil && watchRequeue < requeueAfter {
+		requeueAfter = watchRequeue
+	}
+
+	// Come back soon to report the list load
+	if spec.Lists != nil && listRequeue < requeueAfter {
+		requeueAfter = listRequeue
+	}
+
+	// Come back to refresh the report
+	if spec.Report != nil && reportAfter < requeueAfter {
+		requeueAfter = reportAfter
+	}
+
+	// Come back to check the current phase of the scenario, at once when it just ended
+	if stage != nil && stageAfter < requeueAfter {
+		requeueAfter = stageAfter
+	}
+
 	return ctrl.Result{
 		Requeue:      true,
-		RequeueAfter: time.Minute * 1, // Requeue every 5 minutes
+		RequeueAfter: requeueAfter,
 	}, nil
 }
 
-// generateComplexCRD creates a highly nested CustomResourceDefinition
-func (r *ReconTestReconciler) generateComplexCRD(index int, crdName string) *v1.CustomResourceDefinition {
-	plural := fmt.Sprintf("complexrecontests%d", index) // spec.names.plural
-	group := "example.anirudh.io"                       // spec.group
-
-	return &v1.CustomResourceDefinition{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: fmt.Sprintf("%s.%s", plural, group), // Ensure name is in the correct format
-			Labels: map[string]string{
-				"generated-by": "complex-recontest-controller",
-				"complexity":   "high",
-				"index":        fmt.Sprintf("%d", index),
-				"timestamp":    fmt.Sprintf("%d", time.Now().Unix()),
-			},
+// intendedSchema validates the settings of spec that its schema cannot check and returns
+// the OpenAPI v3 schema of the CRDs it generates
+func (r *ReconTestReconciler) intendedSchema(spec examplev1alpha1.ReconTestSpec) (*v1.JSONSchemaProps, error) {
+	if err := crdgen.Validate(spec); err != nil {
+		return nil, err
+	}
+	if crdgen.UsesWebhook(spec) && r.ConversionWebhook == nil {
+		return nil, errors.New("webhook conversion is not enabled on the operator, " +
+			"start it with --conversion-webhook-service or --conversion-webhook-url")
+	}
+	if spec.Report != nil {
+		if err := r.validateReport(spec.Report); err != nil {
+			return nil, err
+		}
+	}
+	if err := slo.Validate(spec.Assertions); err != nil {
+		return nil, err
+	}
+	if spec.BaselineRef != nil && spec.Report == nil {
+		return nil, errors.New("baselineRef compares the report of the run, enable it with spec.report")
+	}
+	if err := scenario.Validate(spec); err != nil {
+		return nil, err
+	}
+	if spec.DryRun && len(spec.Phases) > 0 {
+		return nil, errors.New("dryRun does not support phases")
+	}
+	if spec.Writes != nil && spec.Writes.Competitor != nil &&
+		spec.Writes.Competitor.FieldManager == spec.Writes.FieldManager {
+		return nil, errors.New("writes.competitor.fieldManager must differ from writes.fieldManager")
+	}
+	if spec.Lists != nil && spec.Lists.LabelSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.Lists.LabelSelector); err != nil {
+			return nil, fmt.Errorf("invalid lists.labelSelector: %w", err)
+		}
+	}
+	return crdgen.Schema(spec)
+}
+
+// conversionWebhook returns where the CRDs of spec reach the conversion webhook, with the CA
+// bundle currently in ConversionCABundleFile
+func (r *ReconTestReconciler) conversionWebhook(spec examplev1alpha1.ReconTestSpec) (*crdgen.WebhookConfig, error) {
+	if !crdgen.UsesWebhook(spec) || r.ConversionWebhook == nil || r.ConversionCABundleFile == "" {
+		return r.ConversionWebhook, nil
+	}
+	caBundle, err := os.ReadFile(r.ConversionCABundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading the CA bundle of the conversion webhook: %w", err)
+	}
+	webhook := *r.ConversionWebhook
+	webhook.CABundle = caBundle
+	return &webhook, nil
+}
+
+// continuousLoad reports whether spec keeps loading the cluster after all CRDs of the run exist
+func continuousLoad(spec examplev1alpha1.ReconTestSpec) bool {
+	if spec.Writes != nil && (spec.Writes.Competitor != nil || writeMode(spec) != examplev1alpha1.WriteCreate) {
+		// Every pass rewrites the existing CRDs, or the competitor applies them
+		return true
+	}
+	return spec.Churn != nil || spec.SchemaChurn != nil || spec.Watches != nil || spec.Lists != nil
+}
+
+// createAllCRDs generates and creates the CRDs described by spec that are missing from the cache,
+// each with a copy of schema, at the concurrency and rate set in spec. Failed creations are
+// retried, given up or abort the run as policy decides.
+func (r *ReconTestReconciler) createAllCRDs(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	schema *v1.JSONSchemaProps, policy *retry.Policy) (*crdPass, error) {
+	pass := &crdPass{desired: spec.Count}
+	webhook, err := r.conversionWebhook(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	runCRDs, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[string]*v1.CustomResourceDefinition, len(runCRDs))
+	for i := range runCRDs {
+		present[runCRDs[i].Name] = &runCRDs[i]
+	}
+
+	// Only the missing CRDs need a Create call, the others are checked for drift and, unless
+	// they are written in the Create mode, written again
+	mode := writeMode(spec)
+	driftPolicy := spec.DriftPolicy
+	if mode != examplev1alpha1.WriteCreate {
+		// Writing the intended spec again puts drifted CRDs back
+		driftPolicy = examplev1alpha1.DriftIgnore
+	}
+	indices := make([]int, 0, spec.Count)
+	var rewrites []rewrite
+	for _, index := range crdgen.Indices(spec) {
+		if live, ok := present[crdgen.Name(spec, index)]; ok {
+			pass.existing++
+			intended := crdgen.CRD(reconTest, spec, index, schema, webhook)
+			if spec.SchemaChurn != nil {
+				// The schema churn updates of the live CRD are part of its intended spec
+				if err := crdgen.Revise(intended, spec.SchemaChurn.Changes, crdgen.SchemaRevision(live)); err != nil {
+					return nil, err
+				}
+			}
+			live = r.rotateCABundle(ctx, logger, reconTest, live, intended, pass)
+			r.repairDrift(ctx, logger, reconTest, driftPolicy, live, intended, pass)
+			if mode != examplev1alpha1.WriteCreate && live.DeletionTimestamp == nil {
+				rewrites = append(rewrites, rewrite{live: live, intended: intended})
+			}
+			continue
+		}
+		indices = append(indices, index)
+	}
+	if spec.Writes != nil {
+		r.writes.observeManagedFields(reconTest, runCRDs)
+	}
+	crdDrifted.WithLabelValues(reconTest.Namespace + "/" + reconTest.Name).Set(float64(pass.drifted))
+	createStarts := make([]time.Time, len(indices))
+	createCalls := make([]time.Duration, len(indices))
+
+	pass.abort(r.runBatch(ctx, reconTest, policy, batch{
+		operation: writeOperation(spec),
+		cfg:       loadConfig(spec.Rate),
+		n:         len(indices),
+		request: func(ctx context.Context, i int) error {
+			// Create CRD object
+			crd := crdgen.CRD(reconTest, spec, indices[i], schema, webhook)
+			if mode != examplev1alpha1.WriteServerSideApply {
+				// Applies leave it out, or every pass would change it
+				crd.Labels["timestamp"] = fmt.Sprintf("%d", time.Now().Unix())
+			}
+
+			logger.Info(fmt.Sprintf("Creating CRD %s", crd.Name))
+
+			// Attempt to create CRD
+			createStarts[i] = time.Now()
+			err := r.createCRD(ctx, reconTest, spec, crd)
+			createCalls[i] = time.Since(createStarts[i])
+			return err
 		},
-		Spec: v1.CustomResourceDefinitionSpec{
-			Group: group,
-			Names: v1.CustomResourceDefinitionNames{
-				Kind:     fmt.Sprintf("ComplexRecontest%d", index),
-				ListKind: fmt.Sprintf("ComplexRecontest%dList", index),
-				Plural:   plural,
-				Singular: fmt.Sprintf("complexrecontest%d", index),
-			},
-			Scope: v1.NamespaceScoped,
-			Versions: []v1.CustomResourceDefinitionVersion{
-				{
-					Name:    "v1alpha1",
-					Served:  true,
-					Storage: true,
-					Schema: &v1.CustomResourceValidation{
-						OpenAPIV3Schema: &v1.JSONSchemaProps{
-							Type: "object",
-							Properties: map[string]v1.JSONSchemaProps{
-								"spec": {
-									Type: "object",
-									Properties: map[string]v1.JSONSchemaProps{
-										"organization": {
-											Type: "object",
-											Properties: map[string]v1.JSONSchemaProps{
-												"name": {
-													Type: "string",
-												},
-												"foundedYear": {
-													Type:    "integer",
-													Minimum: float64Ptr(1800.0),
-													Maximum: float64Ptr(2100.0),
-												},
-												"address": {
-													Type: "object",
-													Properties: map[string]v1.JSONSchemaProps{
-														"street": {
-															Type: "string",
-														},
-														"city": {
-															Type: "string",
-														},
-														"state": {
-															Type: "string",
-														},
-														"postalCode": {
-															Type:    "string",
-															Pattern: `^\d{5}(-\d{4})?$`, // US Zip code pattern
-														},
-														"geoLocation": {
-															Type: "object",
-															Properties: map[string]v1.JSONSchemaProps{
-																"latitude": {
-																	Type:    "number",
-																	Minimum: float64Ptr(-90.0),
-																	Maximum: float64Ptr(90.0),
-																},
-																"longitude": {
-																	Type:    "number",
-																	Minimum: float64Ptr(-180.0),
-																	Maximum: float64Ptr(180.0),
-																},
-															},
-															Required: []string{"latitude", "longitude"},
-														},
-													},
-													Required: []string{"street", "city", "state"},
-												},
-												"departments": {
-													Type: "array",
-													Items: &v1.JSONSchemaPropsOrArray{
-														Schema: &v1.JSONSchemaProps{
-															Type: "object",
-															Properties: map[string]v1.JSONSchemaProps{
-																"name": {
-																	Type: "string",
-																},
-																"headCount": {
-																	Type:    "integer",
-																	Minimum: float64Ptr(0.0),
-																},
-																"budget": {
-																	Type: "object",
-																	Properties: map[string]v1.JSONSchemaProps{
-																		"annual": {
-																			Type:    "number",
-																			Minimum: float64Ptr(0.0),
-																		},
-																		"currency": {
-																			Type: "string",
-																			Enum: []v1.JSON{
-																				{Raw: []byte(`"USD"`)},
-																				{Raw: []byte(`"EUR"`)},
-																				{Raw: []byte(`"GBP"`)},
-																				{Raw: []byte(`"JPY"`)},
-																			},
-																		},
-																	},
-																	Required: []string{"annual", "currency"},
-																},
-															},
-															Required: []string{"name", "headCount"},
-														},
-													},
-												},
-											},
-											Required: []string{"name", "foundedYear"},
-										},
-										"projectMetadata": {
-											Type: "object",
-											Properties: map[string]v1.JSONSchemaProps{
-												"projectId": {
-													Type: "string",
-												},
-												"status": {
-													Type: "string",
-													Enum: []v1.JSON{
-														{Raw: []byte(`"planning"`)},
-														{Raw: []byte(`"in-progress"`)},
-														{Raw: []byte(`"completed"`)},
-														{Raw: []byte(`"on-hold"`)},
-													},
-												},
-												"resources": {
-													Type: "array",
-													Items: &v1.JSONSchemaPropsOrArray{
-														Schema: &v1.JSONSchemaProps{
-															Type: "object",
-															Properties: map[string]v1.JSONSchemaProps{
-																"type": {
-																	Type: "string",
-																},
-																"quantity": {
-																	Type:    "integer",
-																	Minimum: float64Ptr(0.0),
-																},
-																"details": {
-																	Type: "object",
-																	AdditionalProperties: &v1.JSONSchemaPropsOrBool{
-																		Allows: true,
-																	},
-																},
-															},
-															Required: []string{"type", "quantity"},
-														},
-													},
-												},
-												"timeline": {
-													Type: "object",
-													Properties: map[string]v1.JSONSchemaProps{
-														"startDate": {
-															Type:   "string",
-															Format: "date",
-														},
-														"endDate": {
-															Type:   "string",
-															Format: "date",
-														},
-														"milestones": {
-															Type: "array",
-															Items: &v1.JSONSchemaPropsOrArray{
-																Schema: &v1.JSONSchemaProps{
-																	Type: "object",
-																	Properties: map[string]v1.JSONSchemaProps{
-																		"name": {
-																			Type: "string",
-																		},
-																		"completionDate": {
-																			Type:   "string",
-																			Format: "date",
-																		},
-																		"dependencies": {
-																			Type: "array",
-																			Items: &v1.JSONSchemaPropsOrArray{
-																				Schema: &v1.JSONSchemaProps{
-																					Type: "string",
-																				},
-																			},
-																		},
-																	},
-																	Required: []string{"name", "completionDate"},
-																},
-															},
-														},
-													},
-													Required: []string{"startDate", "endDate"},
-												},
-											},
-											Required: []string{"projectId", "status"},
-										},
-									},
-									Required: []string{"organization", "projectMetadata"},
-								},
-							},
-							Required: []string{"spec"},
-						},
-					},
-				},
-			},
+		result: func(i int, err error) error {
+			crdName := crdgen.Name(spec, indices[i])
+			if err != nil {
+				// Check if the error is due to the CRD already existing
+				if apierrors.IsAlreadyExists(err) {
+					// Log that the CRD already exists
+					logger.Info(fmt.Sprintf("CRD already exists: %s", crdName))
+					pass.existing++
+					return nil
+				}
+
+				// Log other errors
+				logger.Error(err, fmt.Sprintf("Failed to create complex CRD: %s", crdName))
+				pass.recordFailure(crdName, err)
+				return err
+			}
+
+			logger.Info(fmt.Sprintf("Successfully created complex CRD: %s", crdName))
+			r.trackEstablishment(ctx, reconTest, crdName, createStarts[i], createCalls[i],
+				r.churnTracker.recreated(crdName))
+			r.discovery.created(reconTest, spec, indices[i], createStarts[i])
+			pass.created++
+			return nil
 		},
+	}))
+
+	if len(rewrites) > 0 && pass.aborted == nil {
+		r.rewriteCRDs(ctx, logger, reconTest, spec, rewrites, policy, pass)
 	}
+	return pass, nil
 }
 
-// Helper function to create float64 pointers
-func float64Ptr(f float64) *float64 {
-	return &f
+// loadConfig translates the rate settings of a run into the configuration of its load engine
+func loadConfig(rateSpec *examplev1alpha1.RateSpec) load.Config {
+	if rateSpec == nil {
+		return load.Config{Concurrency: 1}
+	}
+
+	cfg := load.Config{
+		Concurrency: int(rateSpec.Concurrency),
+		QPS:         float64(rateSpec.QPS),
+		Burst:       int(rateSpec.Burst),
+	}
+	if rateSpec.RampUp != nil {
+		cfg.Ramp = load.RampProfile(rateSpec.RampUp.Profile)
+		cfg.RampDuration = rateSpec.RampUp.Duration.Duration
+		cfg.RampSteps = int(rateSpec.RampUp.Steps)
+	}
+	return cfg
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *ReconTestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.establishment = newEstablishmentTracker()
+	r.churnTracker = newChurnTracker()
+	r.instances = newInstanceTracker()
+	r.requests = newRequestTracker()
+	r.writes = newWriteTracker()
+	r.apiReader = mgr.GetAPIReader()
+
+	// Poll the discovery and OpenAPI endpoints for the kinds of created and deleted CRDs
+	discoveryClient, err := clientdiscovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+	r.discovery = newDiscoveryTracker(discovery.NewClient(discoveryClient.RESTClient()),
+		mgr.GetLogger().WithName("discovery"))
+	r.serverVersion = discoveryClient
+	if err := mgr.Add(r.discovery); err != nil {
+		return err
+	}
+	r.schemaChurn = newSchemaChurnTracker(discovery.NewClient(discoveryClient.RESTClient()),
+		mgr.GetLogger().WithName("schemachurn"))
+	if err := mgr.Add(r.schemaChurn); err != nil {
+		return err
+	}
+
+	r.dynamic, err = dynamic.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	// Open the watchers and run the list load of the runs on clients of their own, so that they
+	// load the API server like many clients do rather than wait on the rate limiter of the operator
+	unlimitedConfig := rest.CopyConfig(mgr.GetConfig())
+	unlimitedConfig.QPS = -1
+	dynamicClient, err := dynamic.NewForConfig(unlimitedConfig)
+	if err != nil {
+		return err
+	}
+	r.watches = newWatchTracker(dynamicClient, mgr.GetLogger().WithName("watches"))
+	if err := mgr.Add(r.watches); err != nil {
+		return err
+	}
+	listClient, err := clientdiscovery.NewDiscoveryClientForConfig(unlimitedConfig)
+	if err != nil {
+		return err
+	}
+	r.lists = newListTracker(lists.NewClient(listClient.RESTClient()), mgr.GetLogger().WithName("lists"))
+	if err := mgr.Add(r.lists); err != nil {
+		return err
+	}
+
+	// Measure establishment and churn latencies and follow CRD deletions from the shared CRD informer
+	crdInformer, err := mgr.GetCache().GetInformer(context.Background(), &v1.CustomResourceDefinition{})
+	if err != nil {
+		return err
+	}
+	crdInformer.AddEventHandler(r.establishment.eventHandler())
+	crdInformer.AddEventHandler(r.churnTracker.eventHandler())
+	crdInformer.AddEventHandler(r.schemaChurn.eventHandler())
+	crdInformer.AddEventHandler(r.instances.eventHandler())
+	crdInformer.AddEventHandler(r.watches.eventHandler())
+	crdInformer.AddEventHandler(r.lists.eventHandler())
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1.CustomResourceDefinition{}).
+		For(&examplev1alpha1.ReconTest{}, builder.WithPredicates(
+			// Ignore the status updates the controller makes itself
+			predicate.Or(predicate.GenerationChangedPredicate{}, deletionStartedPredicate()),
+		)).
 		Watches(
 			&source.Kind{Type: &v1.CustomResourceDefinition{}},
-			&handler.EnqueueRequestForObject{},
+			handler.EnqueueRequestsFromMapFunc(ownerRequest),
+			builder.WithPredicates(
+				// Only generated CRDs matter, and only when removed or edited
+				predicate.NewPredicateFuncs(hasRunLabel),
+				predicate.Funcs{CreateFunc: func(event.CreateEvent) bool { return false }},
+				predicate.Or(predicate.GenerationChangedPredicate{}, deletionStartedPredicate()),
+			),
 		).
 		Complete(r)
 }
+
+// hasRunLabel reports whether obj is a CRD generated by a ReconTest run
+func hasRunLabel(obj client.Object) bool {
+	_, ok := obj.GetLabels()[examplev1alpha1.RunLabel]
+	return ok
+}
+
+// ownerRequest maps a generated CRD to a request for the ReconTest that owns it
+func ownerRequest(obj client.Object) []reconcile.Request {
+	namespace, name, err := cache.SplitMetaNamespaceKey(obj.GetAnnotations()[examplev1alpha1.OwnerAnnotation])
+	if err != nil || name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+}
+
+// deletionStartedPredicate passes updates that set the deletion timestamp of an object
+func deletionStartedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+}
@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+)
+
+// maxConfigMapBytes keeps the report below the 1MiB size limit of a ConfigMap with room for its metadata
+const maxConfigMapBytes = 900 * 1024
+
+// reportFieldOwner is the field manager of the report ConfigMaps
+const reportFieldOwner = "recon-test-operator"
+
+// reportDue returns how long until the next report of a run is due, zero when it is due now
+func reportDue(status examplev1alpha1.ReconTestStatus, spec *examplev1alpha1.ReportSpec, now time.Time) time.Duration {
+	if status.Report == nil || status.Report.LastWriteTime == nil {
+		return 0
+	}
+	next := status.Report.LastWriteTime.Add(spec.Interval.Duration)
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// reportConfigMapName returns the name of the ConfigMap the report of a run is written to
+func reportConfigMapName(reconTest *examplev1alpha1.ReconTest, spec *examplev1alpha1.ReportSpec) string {
+	if spec.ConfigMapName != "" {
+		return spec.ConfigMapName
+	}
+	return reconTest.Name + "-report"
+}
+
+// validateReport checks that the operator can write the report of a run where it asks for it
+func (r *ReconTestReconciler) validateReport(spec *examplev1alpha1.ReportSpec) error {
+	for _, destination := range spec.Destinations {
+		if destination == examplev1alpha1.ReportDirectory && r.ReportDir == "" {
+			return errors.New("report directory is not enabled on the operator, start it with --report-dir")
+		}
+	}
+	return nil
+}
+
+// writeReport builds the report of a run from what the trackers collected, writes it to every
+// destination of spec and records the write in status. final marks the last report of the run.
+func (r *ReconTestReconciler) writeReport(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	spec examplev1alpha1.ReconTestSpec, final bool) error {
+	clusterVersion := "unknown"
+	if info, err := r.serverVersion.ServerVersion(); err == nil {
+		clusterVersion = info.GitVersion
+	}
+
+	now := metav1.Now()
+	requests, _ := r.requests.count(reconTest.UID)
+	runReport := report.New(report.Run{
+		Name:            reconTest.Name,
+		Namespace:       reconTest.Namespace,
+		UID:             reconTest.UID,
+		Started:         reconTest.CreationTimestamp.Time,
+		Generated:       now.Time,
+		Final:           final,
+		OperatorVersion: report.OperatorVersion(),
+		ClusterVersion:  clusterVersion,
+		Spec:            spec,
+	}, reconTest.Status, r.establishment.timings(reconTest.UID), requests, r.requests.errorSummary(reconTest.UID))
+	if spec.BaselineRef != nil {
+		runReport.Status.Baseline = r.compareBaseline(ctx, reconTest, spec.BaselineRef, runReport)
+		runReport.Checks = report.Checks(runReport.Status)
+	}
+
+	files, err := runReport.Files(spec.Report.Formats)
+	if err != nil {
+		return err
+	}
+
+	// Every destination is tried, so the final report of a deleted run is not lost to one failing
+	configMap := ""
+	var errs []error
+	for _, destination := range spec.Report.Destinations {
+		switch destination {
+		case examplev1alpha1.ReportConfigMap:
+			configMap = reportConfigMapName(reconTest, spec.Report)
+			err = r.writeReportConfigMap(ctx, reconTest, configMap, files)
+		case examplev1alpha1.ReportDirectory:
+			err = writeReportDir(filepath.Join(r.ReportDir, reconTest.Namespace, reconTest.Name), files)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("writing report to %s: %w", destination, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if final {
+		// The ReconTest is going away, its status no longer matters
+		return nil
+	}
+	return r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		status.Report = &examplev1alpha1.ReportStatus{
+			LastWriteTime: &now,
+			ConfigMap:     configMap,
+			FailedChecks:  int32(runReport.Failed()),
+		}
+		applyBaseline(status, runReport.Status.Baseline, reconTest.Generation)
+	})
+}
+
+// writeReportConfigMap applies the ConfigMap holding the report files of a run. It is applied
+// rather than read and updated, so the controller does not cache every ConfigMap of the cluster,
+// and is not owned by the ReconTest, so the report survives it.
+func (r *ReconTestReconciler) writeReportConfigMap(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	name string, files map[string][]byte) error {
+	data, binaryData, err := configMapData(files)
+	if err != nil {
+		return err
+	}
+
+	owner := reconTest.Namespace + "/" + reconTest.Name
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   reconTest.Namespace,
+			Annotations: map[string]string{examplev1alpha1.OwnerAnnotation: owner},
+		},
+		Data:       data,
+		BinaryData: binaryData,
+	}
+	return r.Patch(ctx, configMap, client.Apply, client.FieldOwner(reportFieldOwner), client.ForceOwnership)
+}
+
+// configMapData places the report files in the data of a ConfigMap, or gzipped in its binary
+// data with a .gz suffix when they are too large for it
+func configMapData(files map[string][]byte) (map[string]string, map[string][]byte, error) {
+	size := 0
+	for _, content := range files {
+		size += len(content)
+	}
+	if size <= maxConfigMapBytes {
+		data := make(map[string]string, len(files))
+		for name, content := range files {
+			data[name] = string(content)
+		}
+		return data, nil, nil
+	}
+
+	size = 0
+	binaryData := make(map[string][]byte, len(files))
+	for name, content := range files {
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		if _, err := w.Write(content); err != nil {
+			return nil, nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, nil, err
+		}
+		binaryData[name+".gz"] = buf.Bytes()
+		size += buf.Len()
+	}
+	if size > maxConfigMapBytes {
+		return nil, nil, fmt.Errorf("report is %d bytes gzipped, more than a ConfigMap holds", size)
+	}
+	return nil, binaryData, nil
+}
+
+// writeReportDir writes the report files of a run to dir, replacing each file at once so
+// readers never see a partial report
+func writeReportDir(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		tmp, err := os.CreateTemp(dir, "."+name+".*")
+		if err != nil {
+			return err
+		}
+		// Temporary files are private, the report is meant to be collected
+		err = tmp.Chmod(0o644)
+		if err == nil {
+			_, err = tmp.Write(content)
+		}
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(tmp.Name(), filepath.Join(dir, name))
+		}
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+			return err
+		}
+	}
+	return nil
+}
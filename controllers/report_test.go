@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+func TestReportDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	spec := &examplev1alpha1.ReportSpec{Interval: metav1.Duration{Duration: time.Minute}}
+	written := func(ago time.Duration) examplev1alpha1.ReconTestStatus {
+		last := metav1.NewTime(now.Add(-ago))
+		return examplev1alpha1.ReconTestStatus{Report: &examplev1alpha1.ReportStatus{LastWriteTime: &last}}
+	}
+
+	for _, tc := range []struct {
+		name   string
+		status examplev1alpha1.ReconTestStatus
+		want   time.Duration
+	}{
+		{"never written", examplev1alpha1.ReconTestStatus{}, 0},
+		{"within the interval", written(45 * time.Second), 15 * time.Second},
+		{"interval passed", written(time.Hour), 0},
+	} {
+		if got := reportDue(tc.status, spec, now); got != tc.want {
+			t.Errorf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestConfigMapData(t *testing.T) {
+	t.Run("small reports stay readable", func(t *testing.T) {
+		data, binaryData, err := configMapData(map[string][]byte{"report.json": []byte(`{"a":1}`)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data["report.json"] != `{"a":1}` || binaryData != nil {
+			t.Errorf("expected the report in data, got %v and %v", data, binaryData)
+		}
+	})
+
+	t.Run("large reports are gzipped", func(t *testing.T) {
+		content := []byte(strings.Repeat("latency 0.25s\n", maxConfigMapBytes/10))
+		data, binaryData, err := configMapData(map[string][]byte{"report.csv": content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data != nil {
+			t.Errorf("expected no data, got %d keys", len(data))
+		}
+		r, err := gzip.NewReader(bytes.NewReader(binaryData["report.csv.gz"]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		unzipped, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(unzipped, content) {
+			t.Error("expected the gzipped report to hold the original content")
+		}
+	})
+
+	t.Run("reports too large even gzipped are rejected", func(t *testing.T) {
+		content := make([]byte, 2*maxConfigMapBytes)
+		rand.New(rand.NewSource(1)).Read(content)
+		if _, _, err := configMapData(map[string][]byte{"report.json": content}); err == nil {
+			t.Error("expected an incompressible report larger than a ConfigMap to be rejected")
+		}
+	})
+}
+
+// unknownVersion is a server that does not tell its version
+type unknownVersion struct{}
+
+func (unknownVersion) ServerVersion() (*version.Info, error) {
+	return nil, errors.New("no version")
+}
+
+func TestWriteReportTriesEveryDestination(t *testing.T) {
+	// The fake client does not support server-side apply, so the ConfigMap cannot be written
+	dir := t.TempDir()
+	r := &ReconTestReconciler{
+		Client:        fake.NewClientBuilder().Build(),
+		ReportDir:     dir,
+		establishment: newEstablishmentTracker(),
+		requests:      newRequestTracker(),
+		serverVersion: unknownVersion{},
+	}
+	run := testRun()
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{Report: &examplev1alpha1.ReportSpec{
+		Formats: []examplev1alpha1.ReportFormat{examplev1alpha1.ReportJSON},
+		Destinations: []examplev1alpha1.ReportDestination{
+			examplev1alpha1.ReportConfigMap, examplev1alpha1.ReportDirectory,
+		},
+	}})
+
+	err := r.writeReport(context.Background(), run, spec, true)
+	if err == nil || !strings.Contains(err.Error(), string(examplev1alpha1.ReportConfigMap)) {
+		t.Errorf("expected the ConfigMap error to be returned, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, run.Namespace, run.Name, "report.json")); err != nil {
+		t.Errorf("expected the report to be written to the directory after the ConfigMap failed: %v", err)
+	}
+}
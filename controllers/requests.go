@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+)
+
+// Operations whose API requests are counted
+const (
+	opCreateCRD      = "CreateCRD"
+	opUpdateCRD      = "UpdateCRD"
+	opApplyCRD       = "ApplyCRD"
+	opDeleteCRD      = "DeleteCRD"
+	opCreateInstance = "CreateInstance"
+)
+
+// errorKey identifies the errors of an operation with the same status reason
+type errorKey struct {
+	operation string
+	reason    string
+}
+
+// classCounts counts the failed requests of a run with errors of one class
+type classCounts struct {
+	errors  int64
+	retries int64
+	givenUp int64
+}
+
+// requestTracker counts the API requests of every run, and their errors by operation and
+// status reason and by error class
+type requestTracker struct {
+	mu       sync.Mutex
+	requests map[types.UID]int64
+	errors   map[types.UID]map[errorKey]int32
+	classes  map[types.UID]map[examplev1alpha1.ErrorClass]*classCounts
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{
+		requests: map[types.UID]int64{},
+		errors:   map[types.UID]map[errorKey]int32{},
+		classes:  map[types.UID]map[examplev1alpha1.ErrorClass]*classCounts{},
+	}
+}
+
+// observe counts a request issued for an operation of a run and the final error it returned,
+// if any. Creates that find the object already there and deletes that find it already gone
+// leave it as intended, so their errors are not counted.
+func (t *requestTracker) observe(reconTest *examplev1alpha1.ReconTest, operation string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if counts := t.record(reconTest, operation, err); counts != nil {
+		counts.givenUp++
+	}
+}
+
+// retried counts a request issued for an operation of a run that failed with err and is retried
+func (t *requestTracker) retried(reconTest *examplev1alpha1.ReconTest, operation string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if counts := t.record(reconTest, operation, err); counts != nil {
+		counts.retries++
+		owner := reconTest.Namespace + "/" + reconTest.Name
+		requestRetriesTotal.WithLabelValues(owner, operation, string(retry.Classify(err))).Inc()
+	}
+}
+
+// record counts a request and its error, and returns the counts of the class of the error, or
+// nil when the request did not fail. t.mu must be held.
+func (t *requestTracker) record(reconTest *examplev1alpha1.ReconTest, operation string, err error) *classCounts {
+	run := reconTest.UID
+	t.requests[run]++
+	if err == nil || apierrors.IsAlreadyExists(err) && isCreate(operation) ||
+		apierrors.IsNotFound(err) && operation == opDeleteCRD {
+		return nil
+	}
+
+	reason := string(apierrors.ReasonForError(err))
+	if reason == "" {
+		reason = "Unknown"
+	}
+	counts := t.errors[run]
+	if counts == nil {
+		counts = map[errorKey]int32{}
+		t.errors[run] = counts
+	}
+	counts[errorKey{operation: operation, reason: reason}]++
+
+	class := retry.Classify(err)
+	requestErrorsTotal.WithLabelValues(reconTest.Namespace+"/"+reconTest.Name, operation, string(class)).Inc()
+	classes := t.classes[run]
+	if classes == nil {
+		classes = map[examplev1alpha1.ErrorClass]*classCounts{}
+		t.classes[run] = classes
+	}
+	if classes[class] == nil {
+		classes[class] = &classCounts{}
+	}
+	classes[class].errors++
+	return classes[class]
+}
+
+// retryFunc returns the retry decisions of policy for the requests of an operation of a run
+// issued by a load engine, counting every retried request
+func (t *requestTracker) retryFunc(reconTest *examplev1alpha1.ReconTest, operation string,
+	policy *retry.Policy) load.RetryFunc {
+	return func(err error, attempt int) (time.Duration, bool) {
+		backoff, ok := policy.Retry(err, attempt)
+		if ok {
+			t.retried(reconTest, operation, err)
+		}
+		return backoff, ok
+	}
+}
+
+// classSummary returns the error counts of a run by class, in the order of retry.Classes
+func (t *requestTracker) classSummary(run types.UID) []examplev1alpha1.ErrorClassStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var summary []examplev1alpha1.ErrorClassStatus
+	for _, class := range retry.Classes {
+		if counts := t.classes[run][class]; counts != nil {
+			summary = append(summary, examplev1alpha1.ErrorClassStatus{
+				Class:   class,
+				Errors:  counts.errors,
+				Retries: counts.retries,
+				GivenUp: counts.givenUp,
+			})
+		}
+	}
+	return summary
+}
+
+// count returns the number of requests of a run and how many of them failed
+func (t *requestTracker) count(run types.UID) (requests, failed int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, count := range t.errors[run] {
+		failed += int64(count)
+	}
+	return t.requests[run], failed
+}
+
+// errorSummary returns the error counts of a run ordered by operation and reason
+func (t *requestTracker) errorSummary(run types.UID) []report.ErrorCount {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	summary := make([]report.ErrorCount, 0, len(t.errors[run]))
+	for key, count := range t.errors[run] {
+		summary = append(summary, report.ErrorCount{Operation: key.operation, Reason: key.reason, Count: count})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Operation != summary[j].Operation {
+			return summary[i].Operation < summary[j].Operation
+		}
+		return summary[i].Reason < summary[j].Reason
+	})
+	return summary
+}
+
+// clear drops the counts of a run
+func (t *requestTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.requests, run)
+	delete(t.errors, run)
+	delete(t.classes, run)
+}
+
+// isCreate reports whether operation creates objects
+func isCreate(operation string) bool {
+	return operation == opCreateCRD || operation == opCreateInstance
+}
+
+// batch is a set of requests of one operation of a run made through a load engine
+type batch struct {
+	operation string
+	cfg       load.Config
+	n         int
+	// request makes the i-th request
+	request func(ctx context.Context, i int) error
+	// result handles the outcome of the i-th request and returns its error if the request failed
+	result func(i int, err error) error
+	// skipped, if set, handles a request that was never made because the batch was aborted first
+	skipped func(i int)
+}
+
+// runBatch makes the requests of b for a run, retried as policy decides, and counts them. Results
+// are handled one at a time. The first failure policy aborts on stops the requests that were not
+// made yet, and is returned.
+func (r *ReconTestReconciler) runBatch(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	policy *retry.Policy, b batch) *examplev1alpha1.AbortStatus {
+	// Results arrive concurrently from the engine workers
+	var mu sync.Mutex
+	var aborted *examplev1alpha1.AbortStatus
+
+	engineCtx, abort := context.WithCancel(ctx)
+	defer abort()
+
+	cfg := b.cfg
+	cfg.Retry = r.requests.retryFunc(reconTest, b.operation, policy)
+	load.NewEngine(cfg).Run(engineCtx, b.n, b.request, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if aborted != nil && errors.Is(err, context.Canceled) {
+			// Never issued, the run was aborted first
+			if b.skipped != nil {
+				b.skipped(i)
+			}
+			return
+		}
+		r.requests.observe(reconTest, b.operation, err)
+		if err := b.result(i, err); err != nil && aborted == nil && policy.Aborts(err) {
+			aborted = abortStatus(b.operation, err, reconTest.Generation)
+			abort()
+		}
+	})
+	return aborted
+}
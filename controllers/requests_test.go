@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+)
+
+func testRun() *examplev1alpha1.ReconTest {
+	return &examplev1alpha1.ReconTest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "run", UID: "run-uid", Generation: 1},
+	}
+}
+
+func TestRequestTrackerObserve(t *testing.T) {
+	resource := schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	exists := apierrors.NewAlreadyExists(resource, "a")
+	gone := apierrors.NewNotFound(resource, "a")
+	conflict := apierrors.NewConflict(resource, "a", errors.New("stale"))
+
+	for _, tc := range []struct {
+		name      string
+		operation string
+		err       error
+		failed    int64
+	}{
+		{"success", opCreateCRD, nil, 0},
+		{"create of an existing CRD", opCreateCRD, exists, 0},
+		{"create of an existing instance", opCreateInstance, exists, 0},
+		{"update of an existing CRD", opUpdateCRD, exists, 1},
+		{"delete of a deleted CRD", opDeleteCRD, gone, 0},
+		{"update of a deleted CRD", opUpdateCRD, gone, 1},
+		{"conflict", opApplyCRD, conflict, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := newRequestTracker()
+			run := testRun()
+			tracker.observe(run, tc.operation, tc.err)
+
+			requests, failed := tracker.count(run.UID)
+			if requests != 1 || failed != tc.failed {
+				t.Errorf("expected 1 request and %d failed, got %d and %d", tc.failed, requests, failed)
+			}
+			if summary := tracker.errorSummary(run.UID); int64(len(summary)) != tc.failed {
+				t.Errorf("expected %d error counts, got %v", tc.failed, summary)
+			}
+		})
+	}
+}
+
+func TestRequestTrackerClasses(t *testing.T) {
+	tracker := newRequestTracker()
+	run := testRun()
+	conflict := apierrors.NewConflict(schema.GroupResource{}, "a", errors.New("stale"))
+	throttled := apierrors.NewTooManyRequests("slow down", 1)
+
+	tracker.retried(run, opUpdateCRD, conflict)
+	tracker.observe(run, opUpdateCRD, conflict)
+	tracker.observe(run, opCreateCRD, throttled)
+
+	summary := tracker.classSummary(run.UID)
+	want := []examplev1alpha1.ErrorClassStatus{
+		{Class: examplev1alpha1.ErrorConflict, Errors: 2, Retries: 1, GivenUp: 1},
+		{Class: examplev1alpha1.ErrorThrottled, Errors: 1, GivenUp: 1},
+	}
+	if len(summary) != len(want) {
+		t.Fatalf("expected %v, got %v", want, summary)
+	}
+	for i := range want {
+		if summary[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], summary[i])
+		}
+	}
+
+	tracker.clear(run.UID)
+	if requests, _ := tracker.count(run.UID); requests != 0 || tracker.classSummary(run.UID) != nil {
+		t.Error("expected the counts of a cleared run to be dropped")
+	}
+}
+
+func TestRunBatchAbortsRemainingRequests(t *testing.T) {
+	r := &ReconTestReconciler{requests: newRequestTracker()}
+	run := testRun()
+	policy := retry.NewPolicy(&examplev1alpha1.RetryPolicy{
+		Rules: []examplev1alpha1.RetryRule{{Class: examplev1alpha1.ErrorForbidden, Action: examplev1alpha1.RetryAbort}},
+	})
+	forbidden := apierrors.NewForbidden(schema.GroupResource{}, "a", errors.New("denied"))
+
+	var made, results, skipped int
+	aborted := r.runBatch(context.Background(), run, policy, batch{
+		operation: opCreateCRD,
+		cfg:       load.Config{Concurrency: 1},
+		n:         10,
+		request: func(ctx context.Context, i int) error {
+			made++
+			if i == 2 {
+				return forbidden
+			}
+			return nil
+		},
+		result: func(i int, err error) error {
+			results++
+			return err
+		},
+		skipped: func(i int) {
+			skipped++
+		},
+	})
+
+	if aborted == nil || aborted.Class != examplev1alpha1.ErrorForbidden || aborted.Operation != opCreateCRD {
+		t.Fatalf("expected the batch to be aborted by the forbidden error, got %+v", aborted)
+	}
+	if made != 3 || results != 3 {
+		t.Errorf("expected the requests after the aborting one not to be made, got %d made and %d results",
+			made, results)
+	}
+	if made+skipped > 10 {
+		t.Errorf("expected every request to be made or skipped at most once, got %d and %d", made, skipped)
+	}
+	if requests, failed := r.requests.count(run.UID); requests != 3 || failed != 1 {
+		t.Errorf("expected 3 requests and 1 failed, got %d and %d", requests, failed)
+	}
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/scenario"
+)
+
+// scenarioRequeue is how often a running phase is checked against its exit criteria
+const scenarioRequeue = 5 * time.Second
+
+// scenarioStage is the running phase of the scenario of a run
+type scenarioStage struct {
+	index   int
+	phase   examplev1alpha1.Phase
+	started time.Time
+	// spec is the spec the phase runs with and hasCRDs whether the run has CRDs during it
+	spec    examplev1alpha1.ReconTestSpec
+	hasCRDs bool
+}
+
+// currentStage returns the running phase of the scenario of a run, starting the scenario when it
+// has not started for the current generation of the run yet. It returns nil once the scenario ended.
+func (r *ReconTestReconciler) currentStage(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	spec examplev1alpha1.ReconTestSpec) (*scenarioStage, error) {
+	if current := reconTest.Status.Scenario; current == nil || current.ObservedGeneration != reconTest.Generation {
+		now := metav1.Now()
+		if err := r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			status.Scenario = newScenarioStatus(spec, reconTest.Generation, now)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, phase := range reconTest.Status.Scenario.Phases {
+		if phase.State != examplev1alpha1.PhaseStateRunning {
+			continue
+		}
+		phaseSpec, hasCRDs := scenario.Spec(spec, i)
+		return &scenarioStage{
+			index:   i,
+			phase:   spec.Phases[i],
+			started: phase.StartTime.Time,
+			spec:    phaseSpec,
+			hasCRDs: hasCRDs,
+		}, nil
+	}
+	return nil, nil
+}
+
+// newScenarioStatus returns the status of a scenario whose first phase starts at now
+func newScenarioStatus(spec examplev1alpha1.ReconTestSpec, generation int64,
+	now metav1.Time) *examplev1alpha1.ScenarioStatus {
+	scenarioStatus := &examplev1alpha1.ScenarioStatus{ObservedGeneration: generation}
+	for i, phase := range spec.Phases {
+		scenarioStatus.Phases = append(scenarioStatus.Phases, examplev1alpha1.PhaseStatus{
+			Name:  scenario.Name(phase, i),
+			Type:  phase.Type,
+			State: examplev1alpha1.PhaseStatePending,
+		})
+	}
+	first := &scenarioStatus.Phases[0]
+	first.State = examplev1alpha1.PhaseStateRunning
+	first.StartTime = &now
+	scenarioStatus.Current = first.Name
+	return scenarioStatus
+}
+
+// countWithCondition returns the number of CRDs of a run on which conditionType is True
+func (r *ReconTestReconciler) countWithCondition(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	conditionType string) (int, error) {
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for i := range crds {
+		if apihelpers.IsCRDConditionTrue(&crds[i], v1.CustomResourceDefinitionConditionType(conditionType)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// advanceScenario checks the running phase of a run against its exit criteria after a pass and
+// moves the scenario on once the phase ended. It returns when the phase is to be checked again.
+func (r *ReconTestReconciler) advanceScenario(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	stage *scenarioStage, progress scenario.Progress) (time.Duration, error) {
+	if stage.phase.Type == examplev1alpha1.ScenarioWaitForCondition {
+		withCondition, err := r.countWithCondition(ctx, reconTest, scenario.Condition(stage.phase))
+		if err != nil {
+			return 0, err
+		}
+		progress.WithCondition = withCondition
+	}
+	progress.Elapsed = time.Since(stage.started)
+	state, message := scenario.Evaluate(stage.phase, progress)
+
+	if state == examplev1alpha1.PhaseStateRunning {
+		// Check timed phases and timeouts when they are due rather than at the next poll
+		after := scenarioRequeue
+		for _, limit := range []*metav1.Duration{stage.phase.Duration, stage.phase.Timeout} {
+			if limit != nil && limit.Duration-progress.Elapsed < after {
+				after = limit.Duration - progress.Elapsed
+			}
+		}
+		return after, r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+			status.Scenario.Phases[stage.index].Message = message
+		})
+	}
+
+	now := metav1.Now()
+	return 0, r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		endPhase(status, stage.index, state, message, now)
+	})
+}
+
+// endPhase records that the phase at index ended in state at now, and starts the next phase or
+// ends the scenario after the last phase or a failed one
+func endPhase(status *examplev1alpha1.ReconTestStatus, index int, state examplev1alpha1.PhaseState,
+	message string, now metav1.Time) {
+	phases := status.Scenario.Phases
+	phases[index].State = state
+	phases[index].Message = message
+	phases[index].CompletionTime = &now
+
+	if state != examplev1alpha1.PhaseStateFailed && index+1 < len(phases) {
+		phases[index+1].State = examplev1alpha1.PhaseStateRunning
+		phases[index+1].StartTime = &now
+		status.Scenario.Current = phases[index+1].Name
+		return
+	}
+
+	status.Scenario.Current = ""
+	status.Scenario.CompletionTime = &now
+	if state != examplev1alpha1.PhaseStateFailed {
+		status.Phase = examplev1alpha1.PhaseCompleted
+		return
+	}
+	status.Phase = examplev1alpha1.PhaseFailed
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               examplev1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: status.Scenario.ObservedGeneration,
+		Reason:             "PhaseFailed",
+		Message:            fmt.Sprintf("Phase %s failed: %s", phases[index].Name, message),
+	})
+}
@@ -0,0 +1,409 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/discovery"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+)
+
+// schemaPublishTick is how often OpenAPI v3 is polled for the revisions awaiting publication
+const schemaPublishTick = time.Second
+
+// schemaPublishTimeout is how long a revision is awaited in OpenAPI v3 before it is given up
+const schemaPublishTimeout = time.Minute * 5
+
+// schemaChurnRequeue is how soon a run with schema updates still awaited is reconciled again
+const schemaChurnRequeue = time.Second * 5
+
+// pendingRevision is a schema update of a CRD not yet seen established and published
+type pendingRevision struct {
+	run        types.UID
+	owner      string
+	gvk        schema.GroupVersionKind
+	revision   int
+	generation int64
+	started    time.Time
+	// established and published are set once the update was seen established and in OpenAPI v3
+	established bool
+	published   bool
+}
+
+// revisionSamples holds the latencies of the schema updates of one run
+type revisionSamples struct {
+	established []time.Duration
+	published   []time.Duration
+	timedOut    int32
+}
+
+// schemaChurnTracker follows the CRDs updated by schema churn until the controller sees them
+// established at their new generation and OpenAPI v3 publishes their new revision. It runs as
+// a manager runnable that polls OpenAPI v3 while updates are awaited.
+type schemaChurnTracker struct {
+	client *discovery.Client
+	log    logr.Logger
+
+	mu sync.Mutex
+	// pending holds the last update of every CRD by name
+	pending map[string]*pendingRevision
+	samples map[types.UID]*revisionSamples
+	// unsupported is set once the API server turned out not to serve OpenAPI v3
+	unsupported bool
+}
+
+func newSchemaChurnTracker(client *discovery.Client, log logr.Logger) *schemaChurnTracker {
+	return &schemaChurnTracker{
+		client:  client,
+		log:     log,
+		pending: map[string]*pendingRevision{},
+		samples: map[types.UID]*revisionSamples{},
+	}
+}
+
+// updated records the Update call, started at the given time, that brought crd to revision.
+// crd is the object returned by the call, at the generation of the update. The API server keeps
+// Established True across updates, so the informer may deliver the updated CRD before it is
+// awaited: the returned object already shows it established and is observed at once.
+func (t *schemaChurnTracker) updated(reconTest *examplev1alpha1.ReconTest, crd *v1.CustomResourceDefinition,
+	revision int, started time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// An earlier update of the CRD still awaited is overtaken by this one
+	t.pending[crd.Name] = &pendingRevision{
+		run:        reconTest.UID,
+		owner:      reconTest.Namespace + "/" + reconTest.Name,
+		gvk:        storageKind(crd),
+		revision:   revision,
+		generation: crd.Generation,
+		started:    started,
+		published:  t.unsupported,
+	}
+	t.establish(crd)
+}
+
+// observed records that crd was seen established, completing the establishment of its update
+func (t *schemaChurnTracker) observed(crd *v1.CustomResourceDefinition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.establish(crd)
+}
+
+// establish records the update of crd as established when crd shows Established True at the
+// generation of the update. The caller holds mu.
+func (t *schemaChurnTracker) establish(crd *v1.CustomResourceDefinition) {
+	p := t.pending[crd.Name]
+	if p == nil || p.established || crd.Generation < p.generation ||
+		!apihelpers.IsCRDConditionTrue(crd, v1.Established) {
+		return
+	}
+	elapsed := time.Since(p.started)
+	p.established = true
+	t.runSamples(p.run).established = append(t.runSamples(p.run).established, elapsed)
+	schemaUpdateToEstablishedSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+	t.complete(crd.Name, p)
+}
+
+// complete drops the update of a CRD once it was seen established and published. The caller holds mu.
+func (t *schemaChurnTracker) complete(crdName string, p *pendingRevision) {
+	if p.established && p.published {
+		delete(t.pending, crdName)
+	}
+}
+
+// pendingCount returns the number of schema updates of a run still awaited
+func (t *schemaChurnTracker) pendingCount(run types.UID) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for _, p := range t.pending {
+		if p.run == run {
+			count++
+		}
+	}
+	return count
+}
+
+// summary returns the update-to-established and update-to-published latencies of a run and its
+// publication timeouts
+func (t *schemaChurnTracker) summary(run types.UID) (established, published *examplev1alpha1.LatencySummary,
+	timedOut int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := t.samples[run]
+	if samples == nil {
+		return nil, nil, 0
+	}
+	if len(samples.established) > 0 {
+		summary := latencySummary(samples.established)
+		established = &summary
+	}
+	if len(samples.published) > 0 {
+		summary := latencySummary(samples.published)
+		published = &summary
+	}
+	return established, published, samples.timedOut
+}
+
+// clear drops everything kept for a run
+func (t *schemaChurnTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.samples, run)
+	for name, p := range t.pending {
+		if p.run == run {
+			delete(t.pending, name)
+		}
+	}
+}
+
+// eventHandler feeds CRD informer update and delete events into the tracker
+func (t *schemaChurnTracker) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, obj interface{}) {
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.observed(crd)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.mu.Lock()
+				defer t.mu.Unlock()
+				delete(t.pending, crd.Name)
+			}
+		},
+	}
+}
+
+// Start polls OpenAPI v3 for the revisions awaiting publication until ctx is done
+func (t *schemaChurnTracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(schemaPublishTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// NeedLeaderElection runs the poller on the leader only, the replica that updates the CRDs
+func (t *schemaChurnTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// poll fetches the OpenAPI v3 document of every group version with revisions awaiting publication
+// and records those it publishes, then gives up on the ones awaited for too long
+func (t *schemaChurnTracker) poll(ctx context.Context) {
+	t.mu.Lock()
+	gvs := map[schema.GroupVersion]bool{}
+	for _, p := range t.pending {
+		if !p.published {
+			gvs[p.gvk.GroupVersion()] = true
+		}
+	}
+	t.mu.Unlock()
+
+	for gv := range gvs {
+		descriptions, err := t.client.Descriptions(ctx, gv)
+		polled := time.Now()
+
+		t.mu.Lock()
+		switch {
+		case errors.Is(err, discovery.ErrUnsupported):
+			t.markUnsupported()
+		case err != nil:
+			t.log.Error(err, "Failed to poll OpenAPI v3", "groupVersion", gv.String())
+		default:
+			t.published(gv, descriptions, polled)
+		}
+		t.mu.Unlock()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expire(time.Now())
+}
+
+// published records the revisions of gv whose mark shows in the descriptions of their kinds by the
+// time OpenAPI v3 was polled. The caller holds mu.
+func (t *schemaChurnTracker) published(gv schema.GroupVersion, descriptions map[string]string, polled time.Time) {
+	for name, p := range t.pending {
+		if p.published || p.gvk.GroupVersion() != gv ||
+			!strings.HasSuffix(descriptions[p.gvk.Kind], schemagen.RevisionDescription(p.revision)) {
+			continue
+		}
+		elapsed := polled.Sub(p.started)
+		p.published = true
+		t.runSamples(p.run).published = append(t.runSamples(p.run).published, elapsed)
+		schemaUpdateToPublishedSeconds.WithLabelValues(p.owner).Observe(elapsed.Seconds())
+		t.complete(name, p)
+	}
+}
+
+// markUnsupported stops awaiting publication on an API server that does not serve OpenAPI v3.
+// The caller holds mu.
+func (t *schemaChurnTracker) markUnsupported() {
+	if !t.unsupported {
+		t.log.Info("OpenAPI v3 not served, schema updates are not awaited in it")
+	}
+	t.unsupported = true
+	for name, p := range t.pending {
+		p.published = true
+		t.complete(name, p)
+	}
+}
+
+// expire gives up on the updates awaited for longer than schemaPublishTimeout. The caller holds mu.
+func (t *schemaChurnTracker) expire(now time.Time) {
+	for name, p := range t.pending {
+		if now.Sub(p.started) < schemaPublishTimeout {
+			continue
+		}
+		if !p.published {
+			t.runSamples(p.run).timedOut++
+			schemaPublishTimeoutsTotal.WithLabelValues(p.owner).Inc()
+		}
+		delete(t.pending, name)
+	}
+}
+
+// runSamples returns the samples of run, creating them on first use. The caller holds mu.
+func (t *schemaChurnTracker) runSamples(run types.UID) *revisionSamples {
+	samples := t.samples[run]
+	if samples == nil {
+		samples = &revisionSamples{}
+		t.samples[run] = samples
+	}
+	return samples
+}
+
+// schemaChurnDue returns how long until the next schema churn round of a run is due, zero when it is due now
+func schemaChurnDue(status examplev1alpha1.ReconTestStatus, churn *examplev1alpha1.SchemaChurnSpec,
+	now time.Time) time.Duration {
+	if status.SchemaChurn == nil || status.SchemaChurn.LastRoundTime == nil {
+		return 0
+	}
+	next := status.SchemaChurn.LastRoundTime.Add(churn.Interval.Duration)
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// churnSchemas moves Percent of the CRDs of a run, chosen at random, to their next schema revision
+// at the schema churn rate. Updates the API server rejects as invalid are counted as rejected.
+// Failed updates are retried from the live CRD, given up or abort the run as policy decides.
+func (r *ReconTestReconciler) churnSchemas(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec, policy *retry.Policy) error {
+	churn := spec.SchemaChurn
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return err
+	}
+
+	candidates := make([]*v1.CustomResourceDefinition, 0, len(crds))
+	for i := range crds {
+		if crds[i].DeletionTimestamp == nil {
+			candidates = append(candidates, &crds[i])
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	targets := candidates[:(len(candidates)*int(churn.Percent)+99)/100]
+
+	roundStart := metav1.Now()
+	logger.Info(fmt.Sprintf("Updating the schema of %d of %d CRDs", len(targets), len(candidates)))
+
+	updated, rejected := int32(0), int32(0)
+	revisions := make([]*v1.CustomResourceDefinition, len(targets))
+	updateStarts := make([]time.Time, len(targets))
+
+	aborted := r.runBatch(ctx, reconTest, policy, batch{
+		operation: opUpdateCRD,
+		cfg:       load.Config{Concurrency: 1, QPS: float64(churn.QPS)},
+		n:         len(targets),
+		request: func(ctx context.Context, i int) error {
+			live := targets[i]
+			if revisions[i] != nil {
+				// A retried update starts again from the CRD as it is now
+				live = &v1.CustomResourceDefinition{}
+				if err := r.Get(ctx, client.ObjectKeyFromObject(targets[i]), live); err != nil {
+					return err
+				}
+			}
+			revision := live.DeepCopy()
+			if err := crdgen.Revise(revision, churn.Changes, crdgen.SchemaRevision(live)+1); err != nil {
+				return err
+			}
+			revisions[i] = revision
+			updateStarts[i] = time.Now()
+			return r.Update(ctx, revision)
+		},
+		result: func(i int, err error) error {
+			switch {
+			case err == nil:
+				revision := crdgen.SchemaRevision(revisions[i])
+				change := churn.Changes[(revision-1)%len(churn.Changes)]
+				r.schemaChurn.updated(reconTest, revisions[i], revision, updateStarts[i])
+				schemaUpdatesTotal.WithLabelValues(reconTest.Namespace+"/"+reconTest.Name, string(change)).Inc()
+				updated++
+			case apierrors.IsNotFound(err):
+				// Deleted since the round started
+			default:
+				if apierrors.IsInvalid(err) {
+					// An incompatible change the API server does not accept
+					logger.Info(fmt.Sprintf("Schema update rejected: %s", targets[i].Name), "error", err.Error())
+					rejected++
+				} else {
+					logger.Error(err, fmt.Sprintf("Failed to update the schema of CRD: %s", targets[i].Name))
+				}
+				return err
+			}
+			return nil
+		},
+	})
+
+	return r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		if status.SchemaChurn == nil {
+			status.SchemaChurn = &examplev1alpha1.SchemaChurnStatus{}
+		}
+		status.SchemaChurn.Rounds++
+		status.SchemaChurn.Updated += updated
+		status.SchemaChurn.Rejected += rejected
+		status.SchemaChurn.LastRoundTime = &roundStart
+		if aborted != nil {
+			markAborted(status, aborted)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+)
+
+func TestSchemaChurnDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	churn := &examplev1alpha1.SchemaChurnSpec{Interval: metav1.Duration{Duration: time.Minute}}
+	last := metav1.NewTime(now.Add(-15 * time.Second))
+	status := examplev1alpha1.ReconTestStatus{SchemaChurn: &examplev1alpha1.SchemaChurnStatus{LastRoundTime: &last}}
+
+	if due := schemaChurnDue(examplev1alpha1.ReconTestStatus{}, churn, now); due != 0 {
+		t.Errorf("expected the first round to be due, got %s", due)
+	}
+	if due := schemaChurnDue(status, churn, now); due != 45*time.Second {
+		t.Errorf("expected the next round in 45s, got %s", due)
+	}
+}
+
+func TestSchemaChurnTrackerObserved(t *testing.T) {
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{})
+	crd := crdgen.CRD(nil, spec, 1, schemagen.Complex(), nil)
+	crd.Generation = 2
+	tracker := newSchemaChurnTracker(nil, logr.Discard())
+	// Publication is not awaited when OpenAPI v3 is not served
+	tracker.unsupported = true
+	run := testRun()
+	tracker.updated(run, crd, 1, time.Now())
+
+	stale := crd.DeepCopy()
+	stale.Generation = 1
+	stale.Status.Conditions = []v1.CustomResourceDefinitionCondition{{Type: v1.Established, Status: v1.ConditionTrue}}
+	tracker.observed(stale)
+	if tracker.pendingCount(run.UID) != 1 {
+		t.Error("expected an update not to be complete before its generation is established")
+	}
+
+	current := stale.DeepCopy()
+	current.Generation = 2
+	tracker.observed(current)
+	if tracker.pendingCount(run.UID) != 0 {
+		t.Error("expected the update to be complete once its generation is established")
+	}
+	established, published, timedOut := tracker.summary(run.UID)
+	if established == nil || established.Samples != 1 || published != nil || timedOut != 0 {
+		t.Errorf("unexpected summary %+v, %+v, %d", established, published, timedOut)
+	}
+
+	tracker.clear(run.UID)
+	if established, _, _ := tracker.summary(run.UID); established != nil {
+		t.Error("expected the samples of a cleared run to be dropped")
+	}
+}
+
+func TestSchemaChurnTrackerUpdatedAfterTheEvent(t *testing.T) {
+	spec := crdgen.WithDefaults(examplev1alpha1.ReconTestSpec{})
+	crd := crdgen.CRD(nil, spec, 1, schemagen.Complex(), nil)
+	crd.Generation = 2
+	crd.Status.Conditions = []v1.CustomResourceDefinitionCondition{{Type: v1.Established, Status: v1.ConditionTrue}}
+	tracker := newSchemaChurnTracker(nil, logr.Discard())
+	tracker.unsupported = true
+	run := testRun()
+
+	// The event of the update arrives while nothing awaits it
+	tracker.observed(crd)
+	tracker.updated(run, crd, 1, time.Now())
+	if tracker.pendingCount(run.UID) != 0 {
+		t.Error("expected the update to be complete when the returned CRD is established")
+	}
+	if established, _, _ := tracker.summary(run.UID); established == nil || established.Samples != 1 {
+		t.Errorf("expected one established sample, got %+v", established)
+	}
+}
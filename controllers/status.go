@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+)
+
+// maxReportedFailures caps the number of CRDFailure entries kept in status
+const maxReportedFailures = 100
+
+// crdPass summarises one pass of createAllCRDs over the CRDs of a run
+type crdPass struct {
+	desired  int32
+	created  int32
+	existing int32
+	drifted  int32
+	failures []examplev1alpha1.CRDFailure
+
+	// done is set when the run has no work left once its CRDs are present
+	done bool
+
+	// lastErr is the last error returned by the API server during the pass
+	lastErr error
+	// aborted records the error that aborted the run during the pass, if any
+	aborted *examplev1alpha1.AbortStatus
+}
+
+// recordFailure counts a CRD that failed to be created and keeps its error
+func (p *crdPass) recordFailure(crdName string, err error) {
+	p.lastErr = err
+	p.failures = append(p.failures, examplev1alpha1.CRDFailure{
+		Name:    crdName,
+		Reason:  string(apierrors.ReasonForError(err)),
+		Message: err.Error(),
+	})
+}
+
+// abort records that the run was aborted during the pass, when aborted is set. Only the first
+// abort is kept.
+func (p *crdPass) abort(aborted *examplev1alpha1.AbortStatus) {
+	if p.aborted == nil {
+		p.aborted = aborted
+	}
+}
+
+// abortStatus returns the record of a run aborted by err, returned to a request of operation
+func abortStatus(operation string, err error, generation int64) *examplev1alpha1.AbortStatus {
+	return &examplev1alpha1.AbortStatus{
+		Class:              retry.Classify(err),
+		Operation:          operation,
+		Message:            err.Error(),
+		Time:               metav1.Now(),
+		ObservedGeneration: generation,
+	}
+}
+
+// applyTo writes the outcome of the pass into status
+func (p *crdPass) applyTo(status *examplev1alpha1.ReconTestStatus, generation int64) {
+	status.ObservedGeneration = generation
+	status.Desired = p.desired
+	status.Created = p.created
+	status.Existing = p.existing
+	status.Drifted = p.drifted
+	status.Failed = int32(len(p.failures))
+	status.FailedCRDs = p.failures
+	if len(status.FailedCRDs) > maxReportedFailures {
+		status.FailedCRDs = status.FailedCRDs[:maxReportedFailures]
+	}
+
+	present := p.created + p.existing
+	switch {
+	case len(p.failures) > 0:
+		status.Phase = examplev1alpha1.PhaseFailed
+	case present >= p.desired && p.done:
+		status.Phase = examplev1alpha1.PhaseCompleted
+	case present >= p.desired:
+		status.Phase = examplev1alpha1.PhaseSteady
+	default:
+		status.Phase = examplev1alpha1.PhaseCreating
+	}
+
+	ready := metav1.Condition{
+		Type:               examplev1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "AllCRDsPresent",
+		Message:            fmt.Sprintf("%d of %d CRDs present", present, p.desired),
+	}
+	if present < p.desired {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "CRDsMissing"
+	}
+	meta.SetStatusCondition(&status.Conditions, ready)
+
+	degraded := metav1.Condition{
+		Type:               examplev1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             "NoFailures",
+		Message:            "All CRD creations succeeded",
+	}
+	if len(p.failures) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "CreateFailed"
+		degraded.Message = fmt.Sprintf("%d CRDs failed, last error: %v", len(p.failures), p.lastErr)
+	}
+	meta.SetStatusCondition(&status.Conditions, degraded)
+	if p.aborted != nil {
+		markAborted(status, p.aborted)
+	}
+}
+
+// markAborted records that an error aborted the run. The run stays aborted until its spec changes.
+func markAborted(status *examplev1alpha1.ReconTestStatus, aborted *examplev1alpha1.AbortStatus) {
+	if status.Errors == nil {
+		status.Errors = &examplev1alpha1.ErrorStatus{}
+	}
+	status.Errors.Aborted = aborted
+	status.Phase = examplev1alpha1.PhaseFailed
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               examplev1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: aborted.ObservedGeneration,
+		Reason:             "Aborted",
+		Message: fmt.Sprintf("Run aborted by a %s error of %s, change the spec to resume: %s",
+			aborted.Class, aborted.Operation, aborted.Message),
+	})
+}
+
+// isAborted reports whether an error aborted the current generation of a run
+func isAborted(reconTest *examplev1alpha1.ReconTest) bool {
+	errs := reconTest.Status.Errors
+	return errs != nil && errs.Aborted != nil && errs.Aborted.ObservedGeneration == reconTest.Generation
+}
+
+// markInvalidSpec records that the spec of a run cannot be turned into CRDs
+func markInvalidSpec(status *examplev1alpha1.ReconTestStatus, generation int64, err error) {
+	status.ObservedGeneration = generation
+	status.Phase = examplev1alpha1.PhaseFailed
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               examplev1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "InvalidSpec",
+		Message:            err.Error(),
+	})
+}
+
+// patchStatus applies mutate to the status of reconTest and patches it through the status subresource
+func (r *ReconTestReconciler) patchStatus(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	mutate func(status *examplev1alpha1.ReconTestStatus)) error {
+	base := reconTest.DeepCopy()
+	mutate(&reconTest.Status)
+	return r.Status().Patch(ctx, reconTest, client.MergeFrom(base))
+}
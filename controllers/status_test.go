@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func TestCRDPassApplyTo(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		pass         crdPass
+		wantPhase    examplev1alpha1.ReconTestPhase
+		wantReady    metav1.ConditionStatus
+		wantDegraded metav1.ConditionStatus
+	}{
+		{"creating", crdPass{desired: 4, created: 2, existing: 1},
+			examplev1alpha1.PhaseCreating, metav1.ConditionFalse, metav1.ConditionFalse},
+		{"steady", crdPass{desired: 4, created: 2, existing: 2},
+			examplev1alpha1.PhaseSteady, metav1.ConditionTrue, metav1.ConditionFalse},
+		{"completed", crdPass{desired: 4, existing: 4, done: true},
+			examplev1alpha1.PhaseCompleted, metav1.ConditionTrue, metav1.ConditionFalse},
+		{"done but missing CRDs", crdPass{desired: 4, existing: 3, done: true},
+			examplev1alpha1.PhaseCreating, metav1.ConditionFalse, metav1.ConditionFalse},
+		{"failed", crdPass{desired: 4, created: 3, done: true, failures: []examplev1alpha1.CRDFailure{{Name: "a"}},
+			lastErr: errors.New("boom")},
+			examplev1alpha1.PhaseFailed, metav1.ConditionFalse, metav1.ConditionTrue},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status := &examplev1alpha1.ReconTestStatus{}
+			tc.pass.applyTo(status, 3)
+
+			if status.Phase != tc.wantPhase {
+				t.Errorf("expected phase %s, got %s", tc.wantPhase, status.Phase)
+			}
+			if status.ObservedGeneration != 3 {
+				t.Errorf("expected observed generation 3, got %d", status.ObservedGeneration)
+			}
+			if ready := meta.FindStatusCondition(status.Conditions, examplev1alpha1.ConditionReady); ready == nil ||
+				ready.Status != tc.wantReady {
+				t.Errorf("expected Ready %s, got %+v", tc.wantReady, ready)
+			}
+			degraded := meta.FindStatusCondition(status.Conditions, examplev1alpha1.ConditionDegraded)
+			if degraded == nil || degraded.Status != tc.wantDegraded {
+				t.Errorf("expected Degraded %s, got %+v", tc.wantDegraded, degraded)
+			}
+		})
+	}
+}
+
+func TestCRDPassApplyToCapsFailures(t *testing.T) {
+	pass := crdPass{desired: maxReportedFailures + 10}
+	for i := 0; i < maxReportedFailures+10; i++ {
+		pass.recordFailure("crd", errors.New("boom"))
+	}
+	status := &examplev1alpha1.ReconTestStatus{}
+	pass.applyTo(status, 1)
+
+	if status.Failed != maxReportedFailures+10 {
+		t.Errorf("expected every failure to be counted, got %d", status.Failed)
+	}
+	if len(status.FailedCRDs) != maxReportedFailures {
+		t.Errorf("expected %d reported failures, got %d", maxReportedFailures, len(status.FailedCRDs))
+	}
+}
+
+func TestCRDPassAbortKeepsFirst(t *testing.T) {
+	pass := crdPass{desired: 1}
+	pass.abort(nil)
+	first := abortStatus(opCreateCRD, errors.New("first"), 2)
+	pass.abort(first)
+	pass.abort(abortStatus(opCreateCRD, errors.New("second"), 2))
+
+	status := &examplev1alpha1.ReconTestStatus{}
+	pass.applyTo(status, 2)
+	if status.Errors == nil || status.Errors.Aborted != first {
+		t.Fatalf("expected the first abort to be kept, got %+v", status.Errors)
+	}
+	if status.Phase != examplev1alpha1.PhaseFailed {
+		t.Errorf("expected an aborted run to fail, got %s", status.Phase)
+	}
+	degraded := meta.FindStatusCondition(status.Conditions, examplev1alpha1.ConditionDegraded)
+	if degraded == nil || degraded.Reason != "Aborted" {
+		t.Errorf("expected Degraded with reason Aborted, got %+v", degraded)
+	}
+}
@@ -0,0 +1,352 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/watch"
+)
+
+// watchTick is how often the watchers of every run are opened or closed and their probes written
+const watchTick = time.Second
+
+// watchRequeue is how soon a run with watchers is reconciled again to report them
+const watchRequeue = time.Second * 15
+
+// watchedKind is a generated kind the watchers of a run follow
+type watchedKind struct {
+	crd       *v1.CustomResourceDefinition
+	gvr       schema.GroupVersionResource
+	version   string
+	namespace string
+	// probeExists is set once the probe of the kind was created
+	probeExists bool
+}
+
+// watchRun holds the watch settings, watched kinds and samples of one run
+type watchRun struct {
+	owner     string
+	reconTest *examplev1alpha1.ReconTest
+	settings  examplev1alpha1.WatchSpec
+	kinds     map[string]*watchedKind
+	nextProbe time.Time
+
+	watchers  int32
+	events    int64
+	latencies stats.Reservoir[time.Duration]
+	ended     map[watch.Reason]int32
+}
+
+// openKind is a kind the watchers of a run are open on
+type openKind struct {
+	run      types.UID
+	gvr      schema.GroupVersionResource
+	settings examplev1alpha1.WatchSpec
+	stop     context.CancelFunc
+}
+
+// watchTracker opens the watchers of every run on its established kinds and measures how long
+// the events of their probe custom resources take to reach every watcher. It runs as a manager
+// runnable that opens and closes watchers as the kinds of the runs come and go.
+type watchTracker struct {
+	client dynamic.Interface
+	log    logr.Logger
+
+	mu   sync.Mutex
+	runs map[types.UID]*watchRun
+	// open holds the kinds with open watchers by CRD name
+	open map[string]*openKind
+}
+
+func newWatchTracker(dynamicClient dynamic.Interface, log logr.Logger) *watchTracker {
+	return &watchTracker{
+		client: dynamicClient,
+		log:    log,
+		runs:   map[types.UID]*watchRun{},
+		open:   map[string]*openKind{},
+	}
+}
+
+// sync sets the kinds the watchers of a run follow to those of its established crds
+func (t *watchTracker) sync(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	crds []v1.CustomResourceDefinition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run := t.runs[reconTest.UID]
+	if run == nil {
+		run = &watchRun{
+			owner: reconTest.Namespace + "/" + reconTest.Name,
+			ended: map[watch.Reason]int32{},
+		}
+		t.runs[reconTest.UID] = run
+	}
+	run.reconTest = reconTest.DeepCopy()
+	run.settings = *spec.Watches
+
+	version := crdgen.InstancesVersion(spec)
+	namespace := instancesNamespace(reconTest, spec)
+	kinds := make(map[string]*watchedKind, len(crds))
+	for i := range crds {
+		crd := &crds[i]
+		if crd.DeletionTimestamp != nil || !apihelpers.IsCRDConditionTrue(crd, v1.Established) {
+			continue
+		}
+		kind := &watchedKind{
+			crd:     crd.DeepCopy(),
+			gvr:     instanceResource(crd, version),
+			version: version,
+		}
+		if crd.Spec.Scope == v1.NamespaceScoped {
+			kind.namespace = namespace
+		}
+		if known := run.kinds[crd.Name]; known != nil && known.crd.UID == crd.UID {
+			kind.probeExists = known.probeExists
+		}
+		kinds[crd.Name] = kind
+	}
+	run.kinds = kinds
+}
+
+// forget stops watching the kind of a deleted CRD
+func (t *watchTracker) forget(crdName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, run := range t.runs {
+		delete(run.kinds, crdName)
+	}
+}
+
+// summary returns the watchers of a run and what they saw, or nil when it has no watchers
+func (t *watchTracker) summary(run types.UID) *examplev1alpha1.WatchStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := t.runs[run]
+	if r == nil {
+		return nil
+	}
+	status := &examplev1alpha1.WatchStatus{
+		Watchers: r.watchers,
+		Events:   r.events,
+		Restarts: r.ended[watch.Closed],
+		Expired:  r.ended[watch.Expired],
+		Failed:   r.ended[watch.Failed],
+	}
+	if r.latencies.Count() > 0 {
+		latency := reservoirSummary(&r.latencies)
+		status.DeliveryLatency = &latency
+	}
+	return status
+}
+
+// clear drops a run, its watchers are closed on the next tick
+func (t *watchTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r := t.runs[run]; r != nil {
+		watchersOpen.DeleteLabelValues(r.owner)
+	}
+	delete(t.runs, run)
+}
+
+// eventHandler feeds CRD informer delete events into the tracker
+func (t *watchTracker) eventHandler() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if crd, ok := obj.(*v1.CustomResourceDefinition); ok {
+				t.forget(crd.Name)
+			}
+		},
+	}
+}
+
+// Start keeps the watchers of every run open on its kinds and writes their probes until ctx is done
+func (t *watchTracker) Start(ctx context.Context) error {
+	ticker := time.NewTicker(watchTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.openWatchers(ctx)
+			for _, due := range t.dueProbes(time.Now()) {
+				t.probe(ctx, due.run, due.kind)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection runs the watchers on the leader only, the replica that creates the CRDs
+func (t *watchTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// openWatchers opens the watchers of the kinds that have none yet and closes those of the kinds
+// no longer watched, or watched with other settings
+func (t *watchTracker) openWatchers(ctx context.Context) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for crdName, open := range t.open {
+		run := t.runs[open.run]
+		if run == nil || run.settings != open.settings {
+			open.stop()
+			delete(t.open, crdName)
+			continue
+		}
+		if kind := run.kinds[crdName]; kind == nil || kind.gvr != open.gvr {
+			open.stop()
+			delete(t.open, crdName)
+		}
+	}
+
+	for uid, run := range t.runs {
+		for crdName, kind := range run.kinds {
+			if t.open[crdName] != nil {
+				continue
+			}
+			watchCtx, stop := context.WithCancel(ctx)
+			t.open[crdName] = &openKind{run: uid, gvr: kind.gvr, settings: run.settings, stop: stop}
+			for i := int32(0); i < run.settings.PerKind; i++ {
+				go t.runWatcher(watchCtx, run, kind.gvr, run.settings.Mode)
+			}
+		}
+	}
+
+	for uid, run := range t.runs {
+		run.watchers = 0
+		for _, open := range t.open {
+			if open.run == uid {
+				run.watchers += open.settings.PerKind
+			}
+		}
+		watchersOpen.WithLabelValues(run.owner).Set(float64(run.watchers))
+	}
+}
+
+// runWatcher follows gvr in mode for a run until ctx is done, recording the delivery latency of
+// every probe write it sees after it started
+func (t *watchTracker) runWatcher(ctx context.Context, run *watchRun, gvr schema.GroupVersionResource,
+	mode examplev1alpha1.WatchMode) {
+	started := time.Now()
+	// seen holds the last write of every probe the watcher saw, so probes delivered again on
+	// a relist are not measured twice
+	seen := map[string]time.Time{}
+
+	watch.Run(ctx, t.client, gvr, mode, watch.Handler{
+		Event: func(obj *unstructured.Unstructured, deleted bool) {
+			delivered := time.Now()
+			written, err := time.Parse(time.RFC3339Nano, obj.GetAnnotations()[examplev1alpha1.ProbeWrittenAnnotation])
+			measured := err == nil && !deleted && written.After(started) && written.After(seen[obj.GetName()])
+
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			run.events++
+			if measured {
+				seen[obj.GetName()] = written
+				elapsed := delivered.Sub(written)
+				run.latencies.Add(elapsed)
+				watchDeliverySeconds.WithLabelValues(run.owner).Observe(elapsed.Seconds())
+			}
+		},
+		Ended: func(reason watch.Reason, err error) {
+			if reason == watch.Failed {
+				t.log.Error(err, "Watch failed", "recontest", run.owner, "resource", gvr.String())
+			}
+
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			run.ended[reason]++
+			watchesEndedTotal.WithLabelValues(run.owner, string(reason)).Inc()
+		},
+	})
+}
+
+// dueProbe is a kind whose probe is to be written
+type dueProbe struct {
+	run  *watchRun
+	kind *watchedKind
+}
+
+// dueProbes returns the kinds of the runs whose next probe write is due, and schedules their following one
+func (t *watchTracker) dueProbes(now time.Time) []dueProbe {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var due []dueProbe
+	for _, run := range t.runs {
+		if len(run.kinds) == 0 || now.Before(run.nextProbe) {
+			continue
+		}
+		run.nextProbe = now.Add(run.settings.ProbeInterval.Duration)
+		for _, kind := range run.kinds {
+			due = append(due, dueProbe{run: run, kind: kind})
+		}
+	}
+	return due
+}
+
+// probe writes the probe custom resource of a watched kind, creating it on its first write and
+// updating the time of the write on the following ones
+func (t *watchTracker) probe(ctx context.Context, run *watchRun, kind *watchedKind) {
+	t.mu.Lock()
+	reconTest, exists := run.reconTest, kind.probeExists
+	t.mu.Unlock()
+
+	obj, err := instanceFor(reconTest, kind.crd, kind.version, kind.namespace, 0)
+	if err != nil {
+		t.log.Error(err, "Failed to synthesize watch probe", "recontest", run.owner, "crd", kind.crd.Name)
+		return
+	}
+	obj.SetName(kind.crd.Spec.Names.Singular + "-watch-probe")
+	written := time.Now().Format(time.RFC3339Nano)
+
+	// The dynamic client needs no discovery of the new kind, unlike a client with a REST mapper
+	resource := t.client.Resource(kind.gvr).Namespace(kind.namespace)
+	if exists {
+		patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, examplev1alpha1.ProbeWrittenAnnotation, written)
+		_, err = resource.Patch(ctx, obj.GetName(), types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	} else {
+		obj.SetAnnotations(map[string]string{examplev1alpha1.ProbeWrittenAnnotation: written})
+		_, err = resource.Create(ctx, obj, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// Left over by an earlier leader, its next write updates it
+			err = nil
+		}
+	}
+	if err != nil {
+		t.log.Error(err, "Failed to write watch probe", "recontest", run.owner, "crd", kind.crd.Name)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// A probe deleted with its CRD is created again once the CRD is recreated
+	kind.probeExists = err == nil || exists && !apierrors.IsNotFound(err)
+}
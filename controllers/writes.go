@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/load"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/retry"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+// writeSamples holds the CRD writes of one field manager
+type writeSamples struct {
+	writes    int64
+	conflicts int64
+	latencies stats.Reservoir[time.Duration]
+}
+
+// writeRun holds the writes of every field manager of one run and the managed fields of its CRDs
+type writeRun struct {
+	owner    string
+	managers map[string]*writeSamples
+	// entriesMax and bytes describe the managedFields of the CRDs of the run as of the last pass
+	entriesMax int32
+	bytes      []int
+}
+
+// writeTracker measures the CRD writes of every run by field manager and the managed fields
+// they leave on its CRDs
+type writeTracker struct {
+	mu   sync.Mutex
+	runs map[types.UID]*writeRun
+}
+
+func newWriteTracker() *writeTracker {
+	return &writeTracker{runs: map[types.UID]*writeRun{}}
+}
+
+// run returns the writes of reconTest, creating them on first use. The caller holds mu.
+func (t *writeTracker) run(reconTest *examplev1alpha1.ReconTest) *writeRun {
+	run := t.runs[reconTest.UID]
+	if run == nil {
+		run = &writeRun{
+			owner:    reconTest.Namespace + "/" + reconTest.Name,
+			managers: map[string]*writeSamples{},
+		}
+		t.runs[reconTest.UID] = run
+	}
+	return run
+}
+
+// observe records a CRD write of a run by manager, its latency and the error it returned
+func (t *writeTracker) observe(reconTest *examplev1alpha1.ReconTest, manager string, latency time.Duration,
+	err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run := t.run(reconTest)
+	samples := run.managers[manager]
+	if samples == nil {
+		samples = &writeSamples{}
+		run.managers[manager] = samples
+	}
+	samples.writes++
+	samples.latencies.Add(latency)
+	crdWriteSeconds.WithLabelValues(run.owner, manager).Observe(latency.Seconds())
+	if apierrors.IsConflict(err) {
+		samples.conflicts++
+		crdWriteConflictsTotal.WithLabelValues(run.owner, manager).Inc()
+	}
+}
+
+// observeManagedFields records the size of the managedFields of the CRDs of a run
+func (t *writeTracker) observeManagedFields(reconTest *examplev1alpha1.ReconTest, crds []v1.CustomResourceDefinition) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	run := t.run(reconTest)
+	run.entriesMax = 0
+	run.bytes = run.bytes[:0]
+	for i := range crds {
+		managedFields := crds[i].ManagedFields
+		if entries := int32(len(managedFields)); entries > run.entriesMax {
+			run.entriesMax = entries
+		}
+		// Marshalling only fails on invalid raw JSON, which the API server does not return
+		raw, _ := json.Marshal(managedFields)
+		run.bytes = append(run.bytes, len(raw))
+	}
+	sort.Ints(run.bytes)
+	if len(run.bytes) > 0 {
+		crdManagedFieldsBytes.WithLabelValues(run.owner).Set(float64(run.bytes[len(run.bytes)-1]))
+	}
+}
+
+// applyTo sets the writes of a run by the managers of settings in status
+func (t *writeTracker) applyTo(run types.UID, settings *examplev1alpha1.WriteSpec,
+	status *examplev1alpha1.WriteStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status.Mode = settings.Mode
+	r := t.runs[run]
+	if r == nil {
+		return
+	}
+	if samples := r.managers[settings.FieldManager]; samples != nil {
+		status.Writes, status.Conflicts = samples.writes, samples.conflicts
+		latency := reservoirSummary(&samples.latencies)
+		status.Latency = &latency
+	}
+	status.ManagedFieldsEntriesMax = r.entriesMax
+	if len(r.bytes) > 0 {
+		status.ManagedFieldsBytesP50 = int64(r.bytes[(len(r.bytes)+1)/2-1])
+		status.ManagedFieldsBytesMax = int64(r.bytes[len(r.bytes)-1])
+	}
+	if settings.Competitor == nil {
+		return
+	}
+	if samples := r.managers[settings.Competitor.FieldManager]; samples != nil {
+		if status.Competitor == nil {
+			status.Competitor = &examplev1alpha1.CompetitorStatus{}
+		}
+		status.Competitor.Applies, status.Competitor.Conflicts = samples.writes, samples.conflicts
+		latency := reservoirSummary(&samples.latencies)
+		status.Competitor.Latency = &latency
+	}
+}
+
+// clear drops the writes of a run
+func (t *writeTracker) clear(run types.UID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r := t.runs[run]; r != nil {
+		crdManagedFieldsBytes.DeleteLabelValues(r.owner)
+	}
+	delete(t.runs, run)
+}
+
+// writeMode returns the mode the CRDs of spec are written in
+func writeMode(spec examplev1alpha1.ReconTestSpec) examplev1alpha1.WriteMode {
+	if spec.Writes == nil {
+		return examplev1alpha1.WriteCreate
+	}
+	return spec.Writes.Mode
+}
+
+// writeOperation returns the operation the requests of the CRD writes of spec are counted under
+func writeOperation(spec examplev1alpha1.ReconTestSpec) string {
+	if writeMode(spec) == examplev1alpha1.WriteServerSideApply {
+		return opApplyCRD
+	}
+	return opCreateCRD
+}
+
+// createCRD creates or, in the ServerSideApply mode, applies a generated CRD as the controller
+func (r *ReconTestReconciler) createCRD(ctx context.Context, reconTest *examplev1alpha1.ReconTest,
+	spec examplev1alpha1.ReconTestSpec, crd *v1.CustomResourceDefinition) error {
+	if spec.Writes == nil {
+		return r.Create(ctx, crd)
+	}
+	return r.timedWrite(reconTest, spec.Writes.FieldManager, func() error {
+		if spec.Writes.Mode == examplev1alpha1.WriteServerSideApply {
+			return r.apply(ctx, crd, spec.Writes.FieldManager, spec.Writes.Force)
+		}
+		return r.Create(ctx, crd, client.FieldOwner(spec.Writes.FieldManager))
+	})
+}
+
+// apply applies obj with server-side apply as manager
+func (r *ReconTestReconciler) apply(ctx context.Context, obj client.Object, manager string, force bool,
+	extra ...client.PatchOption) error {
+	opts := append([]client.PatchOption{client.FieldOwner(manager)}, extra...)
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return r.Patch(ctx, obj, client.Apply, opts...)
+}
+
+// timedWrite issues a CRD write of a run as manager and records it
+func (r *ReconTestReconciler) timedWrite(reconTest *examplev1alpha1.ReconTest, manager string,
+	write func() error) error {
+	start := time.Now()
+	err := write()
+	r.writes.observe(reconTest, manager, time.Since(start), err)
+	return err
+}
+
+// rewrite is an existing CRD of a run to be written again
+type rewrite struct {
+	live     *v1.CustomResourceDefinition
+	intended *v1.CustomResourceDefinition
+}
+
+// rewritten returns live with the spec and the spec annotations of intended, as an Update writes it
+func rewritten(live, intended *v1.CustomResourceDefinition) *v1.CustomResourceDefinition {
+	updated := live.DeepCopy()
+	updated.Spec = intended.Spec
+	if updated.Annotations == nil {
+		updated.Annotations = map[string]string{}
+	}
+	for _, annotation := range []string{
+		examplev1alpha1.SpecHashAnnotation,
+		examplev1alpha1.SchemaRevisionAnnotation,
+	} {
+		if value, ok := intended.Annotations[annotation]; ok {
+			updated.Annotations[annotation] = value
+		}
+	}
+	return updated
+}
+
+// rewriteCRDs writes the intended spec of the existing CRDs of a run again in the Update or
+// ServerSideApply mode, at the rate set in spec. Failed writes are retried from the live CRD,
+// given up or abort the run as policy decides.
+func (r *ReconTestReconciler) rewriteCRDs(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec, rewrites []rewrite,
+	policy *retry.Policy, pass *crdPass) {
+	operation := opUpdateCRD
+	if spec.Writes.Mode == examplev1alpha1.WriteServerSideApply {
+		operation = opApplyCRD
+	}
+	attempted := make([]bool, len(rewrites))
+
+	pass.abort(r.runBatch(ctx, reconTest, policy, batch{
+		operation: operation,
+		cfg:       loadConfig(spec.Rate),
+		n:         len(rewrites),
+		request: func(ctx context.Context, i int) error {
+			if operation == opApplyCRD {
+				return r.timedWrite(reconTest, spec.Writes.FieldManager, func() error {
+					return r.apply(ctx, rewrites[i].intended.DeepCopy(), spec.Writes.FieldManager, spec.Writes.Force)
+				})
+			}
+
+			live := rewrites[i].live
+			if attempted[i] {
+				// A retried update starts again from the CRD as it is now
+				live = &v1.CustomResourceDefinition{}
+				if err := r.Get(ctx, client.ObjectKeyFromObject(rewrites[i].live), live); err != nil {
+					return err
+				}
+			}
+			attempted[i] = true
+			updated := rewritten(live, rewrites[i].intended)
+			return r.timedWrite(reconTest, spec.Writes.FieldManager, func() error {
+				return r.Update(ctx, updated, client.FieldOwner(spec.Writes.FieldManager))
+			})
+		},
+		result: func(i int, err error) error {
+			if err == nil || apierrors.IsNotFound(err) {
+				// A CRD deleted since the pass started is created again by the next one
+				return nil
+			}
+			crdName := rewrites[i].live.Name
+			logger.Error(err, fmt.Sprintf("Failed to write CRD: %s", crdName))
+			pass.recordFailure(crdName, err)
+			return err
+		},
+	}))
+}
+
+// competitorDue returns how long until the next round of the competitor of a run is due, zero when it is due now
+func competitorDue(status examplev1alpha1.ReconTestStatus, competitor *examplev1alpha1.CompetitorSpec,
+	now time.Time) time.Duration {
+	if status.Writes == nil || status.Writes.Competitor == nil || status.Writes.Competitor.LastRoundTime == nil {
+		return 0
+	}
+	next := status.Writes.Competitor.LastRoundTime.Add(competitor.Interval.Duration)
+	if next.Before(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// compete applies the versions of Percent of the CRDs of a run, chosen at random, as the
+// competing field manager with a schema description of its own. Its requests are not the
+// requests of the run: their conflicts are expected and only counted as competitor conflicts.
+func (r *ReconTestReconciler) compete(ctx context.Context, logger logr.Logger,
+	reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec) error {
+	competitor := spec.Writes.Competitor
+	crds, err := r.listRunCRDs(ctx, reconTest)
+	if err != nil {
+		return err
+	}
+
+	candidates := make([]*v1.CustomResourceDefinition, 0, len(crds))
+	for i := range crds {
+		if crds[i].DeletionTimestamp == nil {
+			candidates = append(candidates, &crds[i])
+		}
+	}
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	targets := candidates[:(len(candidates)*int(competitor.Percent)+99)/100]
+
+	round := int32(1)
+	if status := reconTest.Status.Writes; status != nil && status.Competitor != nil {
+		round = status.Competitor.Rounds + 1
+	}
+	roundStart := metav1.Now()
+	logger.Info(fmt.Sprintf("Competing for %d of %d CRDs as %s", len(targets), len(candidates),
+		competitor.FieldManager))
+
+	engine := load.NewEngine(load.Config{Concurrency: 1, QPS: float64(competitor.QPS)})
+	engine.Run(ctx, len(targets), func(ctx context.Context, i int) error {
+		obj, err := competingApply(targets[i], competitor.FieldManager, round)
+		if err != nil {
+			return err
+		}
+		return r.timedWrite(reconTest, competitor.FieldManager, func() error {
+			return r.apply(ctx, obj, competitor.FieldManager, competitor.Force)
+		})
+	}, func(i int, err error) {
+		if err != nil && !apierrors.IsConflict(err) && !apierrors.IsNotFound(err) {
+			logger.Error(err, fmt.Sprintf("Failed to apply CRD as the competitor: %s", targets[i].Name))
+		}
+	})
+
+	return r.patchStatus(ctx, reconTest, func(status *examplev1alpha1.ReconTestStatus) {
+		if status.Writes == nil {
+			status.Writes = &examplev1alpha1.WriteStatus{Mode: spec.Writes.Mode}
+		}
+		if status.Writes.Competitor == nil {
+			status.Writes.Competitor = &examplev1alpha1.CompetitorStatus{}
+		}
+		status.Writes.Competitor.Rounds = round
+		status.Writes.Competitor.LastRoundTime = &roundStart
+	})
+}
+
+// competingApply returns the apply configuration of the competitor for crd in round: its versions
+// with a schema description of the competitor, which the controller owns too, and an annotation
+// only the competitor owns
+func competingApply(crd *v1.CustomResourceDefinition, manager string, round int32) (*unstructured.Unstructured, error) {
+	versions := make([]v1.CustomResourceDefinitionVersion, len(crd.Spec.Versions))
+	for i := range crd.Spec.Versions {
+		crd.Spec.Versions[i].DeepCopyInto(&versions[i])
+		if schema := versions[i].Schema; schema != nil && schema.OpenAPIV3Schema != nil {
+			schema.OpenAPIV3Schema.Description = fmt.Sprintf("Applied by %s in round %d", manager, round)
+		}
+	}
+	raw, err := json.Marshal(versions)
+	if err != nil {
+		return nil, err
+	}
+	var unstructuredVersions []interface{}
+	if err := json.Unmarshal(raw, &unstructuredVersions); err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+	obj.SetName(crd.Name)
+	obj.SetAnnotations(map[string]string{examplev1alpha1.CompetitorAnnotation: strconv.Itoa(int(round))})
+	if err := unstructured.SetNestedSlice(obj.Object, unstructuredVersions, "spec", "versions"); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+func TestWriteTrackerApplyTo(t *testing.T) {
+	settings := &examplev1alpha1.WriteSpec{
+		Mode:         examplev1alpha1.WriteServerSideApply,
+		FieldManager: "operator",
+		Competitor:   &examplev1alpha1.CompetitorSpec{FieldManager: "competitor"},
+	}
+	conflict := apierrors.NewConflict(schema.GroupResource{}, "a", errors.New("owned by operator"))
+	tracker := newWriteTracker()
+	run := testRun()
+
+	tracker.observe(run, "operator", time.Millisecond, nil)
+	tracker.observe(run, "operator", 3*time.Millisecond, nil)
+	tracker.observe(run, "competitor", time.Millisecond, conflict)
+	managed := func(entries int) v1.CustomResourceDefinition {
+		crd := v1.CustomResourceDefinition{}
+		for i := 0; i < entries; i++ {
+			crd.ManagedFields = append(crd.ManagedFields, metav1.ManagedFieldsEntry{Manager: "m"})
+		}
+		return crd
+	}
+	tracker.observeManagedFields(run, []v1.CustomResourceDefinition{managed(1), managed(3), managed(2)})
+
+	status := &examplev1alpha1.WriteStatus{}
+	tracker.applyTo(run.UID, settings, status)
+	if status.Mode != settings.Mode || status.Writes != 2 || status.Conflicts != 0 || status.Latency.Samples != 2 {
+		t.Errorf("unexpected writes of the operator: %+v", status)
+	}
+	if status.ManagedFieldsEntriesMax != 3 || status.ManagedFieldsBytesP50 >= status.ManagedFieldsBytesMax {
+		t.Errorf("unexpected managedFields sizes: %+v", status)
+	}
+	if status.Competitor == nil || status.Competitor.Applies != 1 || status.Competitor.Conflicts != 1 {
+		t.Errorf("unexpected writes of the competitor: %+v", status.Competitor)
+	}
+
+	tracker.clear(run.UID)
+	cleared := &examplev1alpha1.WriteStatus{}
+	tracker.applyTo(run.UID, settings, cleared)
+	if cleared.Writes != 0 || cleared.Competitor != nil {
+		t.Errorf("expected the writes of a cleared run to be dropped, got %+v", cleared)
+	}
+}
+
+func TestWriteTrackerKeepsABoundedSample(t *testing.T) {
+	tracker := newWriteTracker()
+	run := testRun()
+	const writes = 4 * stats.ReservoirSize
+	for i := 0; i < writes; i++ {
+		tracker.observe(run, "operator", time.Millisecond, nil)
+	}
+	if kept := len(tracker.runs[run.UID].managers["operator"].latencies.Sorted()); kept != stats.ReservoirSize {
+		t.Errorf("expected %d latencies kept, got %d", stats.ReservoirSize, kept)
+	}
+
+	status := &examplev1alpha1.WriteStatus{}
+	tracker.applyTo(run.UID, &examplev1alpha1.WriteSpec{FieldManager: "operator"}, status)
+	if status.Writes != writes || status.Latency.Samples != writes {
+		t.Errorf("expected %d writes, got %+v", writes, status)
+	}
+}
+
+func TestWriteOperation(t *testing.T) {
+	for _, tc := range []struct {
+		writes *examplev1alpha1.WriteSpec
+		want   string
+	}{
+		{nil, opCreateCRD},
+		{&examplev1alpha1.WriteSpec{Mode: examplev1alpha1.WriteCreate}, opCreateCRD},
+		{&examplev1alpha1.WriteSpec{Mode: examplev1alpha1.WriteUpdate}, opCreateCRD},
+		{&examplev1alpha1.WriteSpec{Mode: examplev1alpha1.WriteServerSideApply}, opApplyCRD},
+	} {
+		if got := writeOperation(examplev1alpha1.ReconTestSpec{Writes: tc.writes}); got != tc.want {
+			t.Errorf("%+v: expected %s, got %s", tc.writes, tc.want, got)
+		}
+	}
+}
+
+func TestCompetitorDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	competitor := &examplev1alpha1.CompetitorSpec{Interval: metav1.Duration{Duration: 30 * time.Second}}
+	last := metav1.NewTime(now.Add(-10 * time.Second))
+	status := examplev1alpha1.ReconTestStatus{
+		Writes: &examplev1alpha1.WriteStatus{Competitor: &examplev1alpha1.CompetitorStatus{LastRoundTime: &last}},
+	}
+
+	if due := competitorDue(examplev1alpha1.ReconTestStatus{}, competitor, now); due != 0 {
+		t.Errorf("expected the first round to be due, got %s", due)
+	}
+	if due := competitorDue(status, competitor, now); due != 20*time.Second {
+		t.Errorf("expected the next round in 20s, got %s", due)
+	}
+	if due := competitorDue(status, competitor, now.Add(time.Minute)); due != 0 {
+		t.Errorf("expected the next round to be due, got %s", due)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/compare"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+)
+
+// Output formats of the compare command
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// ErrRegressed is returned by Compare when a metric regressed against the baseline
+var ErrRegressed = errors.New("regressed against the baseline")
+
+// Compare compares the JSON report of a run with the report of a baseline run and writes
+// the change of every metric to stdout. It returns ErrRegressed when a metric regressed,
+// so CI jobs fail on regressions.
+func Compare(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	var baselinePath, currentPath, output string
+	var tolerance, confidence float64
+	fs.StringVar(&baselinePath, "baseline", "", "The JSON report of the baseline run, gzipped or not.")
+	fs.StringVar(&currentPath, "current", "", "The JSON report of the run compared with the baseline, gzipped or not.")
+	fs.Float64Var(&tolerance, "tolerance", 10,
+		"How much worse than the baseline a metric may get, in percent, before it counts as a regression.")
+	fs.Float64Var(&confidence, "confidence", 95,
+		"The confidence level, in percent, at which a change must be significant to count as a regression.")
+	fs.StringVar(&output, "output", outputText, "The format of the comparison, text or json.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if baselinePath == "" || currentPath == "" {
+		return errors.New("both --baseline and --current are required")
+	}
+	if output != outputText && output != outputJSON {
+		return fmt.Errorf("unknown output %q, must be %s or %s", output, outputText, outputJSON)
+	}
+	if tolerance < 0 || confidence <= 0 || confidence >= 100 {
+		return fmt.Errorf("tolerance must not be negative and confidence must be between 0 and 100, got %v and %v",
+			tolerance, confidence)
+	}
+
+	baseline, err := readReport(baselinePath)
+	if err != nil {
+		return err
+	}
+	current, err := readReport(currentPath)
+	if err != nil {
+		return err
+	}
+
+	result := compare.Compare(baseline, current, compare.Options{
+		TolerancePercent: tolerance,
+		Alpha:            1 - confidence/100,
+	})
+	if output == outputJSON {
+		raw, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(stdout, string(raw)); err != nil {
+			return err
+		}
+	} else if err := result.WriteText(stdout); err != nil {
+		return err
+	}
+
+	if result.Regressions > 0 {
+		return fmt.Errorf("%d of %d metrics %w", result.Regressions, len(result.Metrics), ErrRegressed)
+	}
+	return nil
+}
+
+// readReport reads the JSON report at path
+func readReport(path string) (*report.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := report.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/compare"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+)
+
+// writeReport writes the JSON report of a run whose 20 CRDs were established after established
+// plus up to a second
+func writeReport(t *testing.T, dir, name string, established time.Duration) string {
+	var crds []report.CRD
+	for i := 0; i < 20; i++ {
+		seconds := (established + time.Duration(i)*50*time.Millisecond).Seconds()
+		crds = append(crds, report.CRD{Name: "crd", Created: time.Unix(int64(i), 0), EstablishedSeconds: &seconds})
+	}
+	raw, err := report.New(report.Run{Name: name, Namespace: "default"}, examplev1alpha1.ReconTestStatus{},
+		crds, 20, nil).JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name+".json")
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCompareFailsOnRegressions(t *testing.T) {
+	dir := t.TempDir()
+	baseline := writeReport(t, dir, "baseline", time.Second)
+
+	out := &strings.Builder{}
+	err := Compare([]string{"--baseline", baseline, "--current", writeReport(t, dir, "same", time.Second)}, out)
+	if err != nil {
+		t.Fatalf("expected no regressions, got %v:\n%s", err, out)
+	}
+
+	out.Reset()
+	err = Compare([]string{"--baseline", baseline, "--current", writeReport(t, dir, "slower", 3*time.Second),
+		"--output", "json"}, out)
+	if !errors.Is(err, ErrRegressed) {
+		t.Fatalf("expected ErrRegressed, got %v", err)
+	}
+	result := &compare.Result{}
+	if err := json.Unmarshal([]byte(out.String()), result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Regressions == 0 || result.Current.Name != "default/slower" {
+		t.Errorf("unexpected comparison: %+v", result)
+	}
+}
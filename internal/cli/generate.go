@@ -0,0 +1,300 @@
+// Package cli implements the subcommands of the operator binary that run
+// without a cluster.
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// Output formats of the generate command
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+// generateOptions holds the flags of the generate command that are not part of a ReconTestSpec
+type generateOptions struct {
+	from       string
+	outputDir  string
+	format     string
+	singleFile string
+	webhookURL string
+	caBundle   string
+}
+
+// Generate writes the CRDs of a ReconTest run to disk, or to stdout when no
+// output directory is given. The run is described by a ReconTest manifest,
+// by flags, or by both, in which case the flags that are set override the
+// manifest. The CRDs are built by the same code the controller uses.
+func Generate(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	opts := generateOptions{}
+	fs.StringVar(&opts.from, "from", "", "A ReconTest manifest whose spec describes the run.")
+	fs.StringVar(&opts.outputDir, "output-dir", "",
+		"The directory the CRDs are written to, one file per CRD. The CRDs are written to stdout when empty.")
+	fs.StringVar(&opts.format, "format", formatYAML, "The format of the CRDs, yaml or json.")
+	fs.StringVar(&opts.singleFile, "single-file", "",
+		"Write every CRD into this file of the output directory as a single multi-document stream.")
+	fs.StringVar(&opts.webhookURL, "conversion-webhook-url", "",
+		"The base URL of the conversion webhook of the operator, for CRDs with webhook conversion.")
+	fs.StringVar(&opts.caBundle, "conversion-ca-bundle", "",
+		"A PEM file with the CA bundle of the conversion webhook, for CRDs with webhook conversion.")
+
+	var (
+		count, startIndex                    int
+		group, namePrefix, kindPrefix, scope string
+		preset                               string
+		depth, fanOut, arrayNesting          int
+		enums, enumSize, patterns, formats   int
+		targetBytes                          int
+		celDensity, celRulesPerNode          int
+		celCost                              string
+		celMaxItems                          int
+		versionCount                         int
+		storageVersion, conversion           string
+	)
+	fs.IntVar(&count, "count", 0, "The number of CRDs to generate.")
+	fs.IntVar(&startIndex, "start-index", 0, "The index of the first CRD.")
+	fs.StringVar(&group, "group", "", "The API group of the CRDs.")
+	fs.StringVar(&namePrefix, "name-prefix", "", "The prefix of the plural name of the CRDs.")
+	fs.StringVar(&kindPrefix, "kind-prefix", "", "The prefix of the kind of the CRDs.")
+	fs.StringVar(&scope, "scope", "", "The scope of the CRDs, Namespaced or Cluster.")
+	fs.StringVar(&preset, "schema-preset", "", "A named schema preset. The other schema flags are ignored when set.")
+	fs.IntVar(&depth, "schema-depth", 0, "The nesting depth of objects under spec.")
+	fs.IntVar(&fanOut, "schema-fan-out", 0, "The number of properties of every object.")
+	fs.IntVar(&arrayNesting, "schema-array-nesting", 0, "The number of arrays around the first nested object.")
+	fs.IntVar(&enums, "schema-enums", 0, "The maximum number of string properties restricted to an enum.")
+	fs.IntVar(&enumSize, "schema-enum-size", 0, "The number of values of every enum.")
+	fs.IntVar(&patterns, "schema-patterns", 0, "The maximum number of string properties validated by a pattern.")
+	fs.IntVar(&formats, "schema-formats", 0, "The maximum number of string properties validated by a format.")
+	fs.IntVar(&targetBytes, "schema-target-bytes", 0, "The minimum serialized size of the schema, at most 1MiB.")
+	fs.IntVar(&celDensity, "cel-density", 0,
+		"The percentage of objects and arrays below spec that carry x-kubernetes-validations rules.")
+	fs.IntVar(&celRulesPerNode, "cel-rules-per-node", 0, "The maximum number of rules of every object or array.")
+	fs.StringVar(&celCost, "cel-cost", "", "The most expensive class of rules, Low, Medium or High.")
+	fs.IntVar(&celMaxItems, "cel-max-items", 0, "The maximum number of items of every array of a schema with rules.")
+	fs.IntVar(&versionCount, "versions", 0, "The number of served versions, named v1alpha1, v1alpha2 and so on.")
+	fs.StringVar(&storageVersion, "storage-version", "", "The name of the storage version.")
+	fs.StringVar(&conversion, "conversion", "", "The conversion strategy of the CRDs, None or Webhook.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if opts.format != formatYAML && opts.format != formatJSON {
+		return fmt.Errorf("unknown format %q, must be %s or %s", opts.format, formatYAML, formatJSON)
+	}
+
+	spec := examplev1alpha1.ReconTestSpec{}
+	if opts.from != "" {
+		reconTest, err := readReconTest(opts.from)
+		if err != nil {
+			return err
+		}
+		spec = reconTest.Spec
+	}
+
+	// Only the flags given on the command line override the manifest
+	schemaSpec := func() *examplev1alpha1.SchemaSpec {
+		if spec.Schema == nil {
+			spec.Schema = &examplev1alpha1.SchemaSpec{}
+		}
+		return spec.Schema
+	}
+	celSpec := func() *examplev1alpha1.CELSpec {
+		if schemaSpec().CEL == nil {
+			spec.Schema.CEL = &examplev1alpha1.CELSpec{}
+		}
+		return spec.Schema.CEL
+	}
+	versionsSpec := func() *examplev1alpha1.VersionsSpec {
+		if spec.Versions == nil {
+			spec.Versions = &examplev1alpha1.VersionsSpec{}
+		}
+		return spec.Versions
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "count":
+			spec.Count = int32(count)
+		case "start-index":
+			spec.StartIndex = int32(startIndex)
+		case "group":
+			spec.Group = group
+		case "name-prefix":
+			spec.NamePrefix = namePrefix
+		case "kind-prefix":
+			spec.KindPrefix = kindPrefix
+		case "scope":
+			spec.Scope = examplev1alpha1.CRDScope(scope)
+		case "schema-preset":
+			schemaSpec().Preset = examplev1alpha1.SchemaPreset(preset)
+		case "schema-depth":
+			schemaSpec().Depth = int32(depth)
+		case "schema-fan-out":
+			schemaSpec().FanOut = int32(fanOut)
+		case "schema-array-nesting":
+			schemaSpec().ArrayNesting = int32(arrayNesting)
+		case "schema-enums":
+			schemaSpec().Enums = int32(enums)
+		case "schema-enum-size":
+			schemaSpec().EnumSize = int32(enumSize)
+		case "schema-patterns":
+			schemaSpec().Patterns = int32(patterns)
+		case "schema-formats":
+			schemaSpec().Formats = int32(formats)
+		case "schema-target-bytes":
+			schemaSpec().TargetBytes = int32(targetBytes)
+		case "cel-density":
+			celSpec().Density = int32(celDensity)
+		case "cel-rules-per-node":
+			celSpec().RulesPerNode = int32(celRulesPerNode)
+		case "cel-cost":
+			celSpec().Cost = examplev1alpha1.CELCost(celCost)
+		case "cel-max-items":
+			celSpec().MaxItems = int32(celMaxItems)
+		case "versions":
+			versionsSpec().Count = int32(versionCount)
+		case "storage-version":
+			versionsSpec().Storage = storageVersion
+		case "conversion":
+			versionsSpec().Conversion = examplev1alpha1.ConversionStrategy(conversion)
+		}
+	})
+
+	spec = crdgen.WithDefaults(spec)
+	if spec.Count < 1 || spec.StartIndex < 1 {
+		return fmt.Errorf("count and start-index must be at least 1, got %d and %d", spec.Count, spec.StartIndex)
+	}
+	if spec.Scope != examplev1alpha1.NamespacedScope && spec.Scope != examplev1alpha1.ClusterScope {
+		return fmt.Errorf("unknown scope %q, must be %s or %s",
+			spec.Scope, examplev1alpha1.NamespacedScope, examplev1alpha1.ClusterScope)
+	}
+	if err := crdgen.Validate(spec); err != nil {
+		return err
+	}
+	webhook, err := webhookConfig(spec, opts)
+	if err != nil {
+		return err
+	}
+	schema, err := crdgen.Schema(spec)
+	if err != nil {
+		return err
+	}
+
+	crds := make([]*v1.CustomResourceDefinition, 0, spec.Count)
+	for _, index := range crdgen.Indices(spec) {
+		crds = append(crds, crdgen.CRD(nil, spec, index, schema, webhook))
+	}
+	return writeCRDs(stdout, opts, crds)
+}
+
+// webhookConfig returns where the CRDs of spec reach the conversion webhook, or nil when they do not use it
+func webhookConfig(spec examplev1alpha1.ReconTestSpec, opts generateOptions) (*crdgen.WebhookConfig, error) {
+	if !crdgen.UsesWebhook(spec) {
+		return nil, nil
+	}
+	if opts.webhookURL == "" {
+		return nil, fmt.Errorf("webhook conversion requires --conversion-webhook-url")
+	}
+
+	webhook := &crdgen.WebhookConfig{URL: opts.webhookURL}
+	if opts.caBundle != "" {
+		caBundle, err := os.ReadFile(opts.caBundle)
+		if err != nil {
+			return nil, err
+		}
+		webhook.CABundle = caBundle
+	}
+	return webhook, nil
+}
+
+// readReconTest decodes the ReconTest manifest at path, rejecting unknown fields
+func readReconTest(path string) (*examplev1alpha1.ReconTest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	reconTest := &examplev1alpha1.ReconTest{}
+	if err := yaml.UnmarshalStrict(raw, reconTest); err != nil {
+		return nil, fmt.Errorf("failed to decode ReconTest %s: %w", path, err)
+	}
+	return reconTest, nil
+}
+
+// writeCRDs writes crds to stdout, to a single file or to one file per CRD as set by opts
+func writeCRDs(stdout io.Writer, opts generateOptions, crds []*v1.CustomResourceDefinition) error {
+	if opts.outputDir == "" {
+		return writeStream(stdout, opts.format, crds)
+	}
+	if err := os.MkdirAll(opts.outputDir, 0o755); err != nil {
+		return err
+	}
+
+	if opts.singleFile != "" {
+		buf := &bytes.Buffer{}
+		if err := writeStream(buf, opts.format, crds); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(opts.outputDir, opts.singleFile), buf.Bytes(), 0o644)
+	}
+
+	for _, crd := range crds {
+		raw, err := encode(opts.format, crd)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(opts.outputDir, fmt.Sprintf("%s.%s", crd.Name, opts.format))
+		if err := os.WriteFile(path, raw, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeStream writes crds as YAML documents separated by "---", or as concatenated JSON objects
+func writeStream(w io.Writer, format string, crds []*v1.CustomResourceDefinition) error {
+	for i, crd := range crds {
+		if i > 0 && format == formatYAML {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		raw, err := encode(format, crd)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encode serializes a CRD in format, ending with a newline
+func encode(format string, crd *v1.CustomResourceDefinition) ([]byte, error) {
+	if format == formatJSON {
+		raw, err := json.MarshalIndent(crd, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(raw, '\n'), nil
+	}
+	return yaml.Marshal(crd)
+}
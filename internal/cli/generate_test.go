@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func TestGenerateFromManifestWithOverrides(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "recontest.yaml")
+	if err := os.WriteFile(manifest, []byte(`apiVersion: example.anirudh.io/v1alpha1
+kind: ReconTest
+metadata:
+  name: sample
+spec:
+  count: 10
+  group: test.example.io
+  scope: Cluster
+  schema:
+    depth: 2
+    fanOut: 3
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out")
+	if err := Generate([]string{"--from", manifest, "--count", "2", "--start-index", "5", "--output-dir", out},
+		io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(entries))
+	}
+
+	raw, err := os.ReadFile(filepath.Join(out, "complexrecontests6.test.example.io.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd := &v1.CustomResourceDefinition{}
+	if err := yaml.UnmarshalStrict(raw, crd); err != nil {
+		t.Fatal(err)
+	}
+	if crd.Spec.Scope != v1.ClusterScoped || crd.Spec.Names.Kind != "ComplexRecontest6" {
+		t.Errorf("unexpected scope %q or kind %q", crd.Spec.Scope, crd.Spec.Names.Kind)
+	}
+	spec := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]
+	if len(spec.Properties) != 3 {
+		t.Errorf("expected the manifest fanOut of 3, got %d properties", len(spec.Properties))
+	}
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "b.yaml"} {
+		if err := Generate([]string{"--count", "3", "--output-dir", dir, "--single-file", name}, io.Discard); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := os.ReadFile(filepath.Join(dir, "a.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "b.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Error("two runs with the same options produced different output")
+	}
+}
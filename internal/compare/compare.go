@@ -0,0 +1,268 @@
+// Package compare compares the reports of two ReconTest runs metric by metric and flags
+// the metrics that regressed, so a slower API server or operator build shows up as a
+// failed comparison rather than as a hunch.
+package compare
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+// Options decide when a change of a metric counts as a regression
+type Options struct {
+	// TolerancePercent is how much worse than the baseline a metric may get, in percent of
+	// its baseline value
+	TolerancePercent float64
+	// Alpha is the significance level a tested change must reach, one minus the confidence
+	Alpha float64
+}
+
+// Unit is the unit of the values of a metric
+type Unit string
+
+// Units of the compared metrics
+const (
+	Seconds   Unit = "s"
+	Percent   Unit = "%"
+	PerSecond Unit = "/s"
+)
+
+// Run identifies a compared run
+type Run struct {
+	Name            string `json:"name"`
+	OperatorVersion string `json:"operatorVersion"`
+	ClusterVersion  string `json:"clusterVersion"`
+}
+
+// Metric compares one metric of two runs
+type Metric struct {
+	Name     string  `json:"name"`
+	Unit     Unit    `json:"unit"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	// DeltaPercent is the change from the baseline in percent of its value, positive when
+	// the metric grew. A metric that was zero in the baseline changes by 100% once it is not.
+	DeltaPercent float64 `json:"deltaPercent"`
+	// PValue is the p-value of the significance test of the change, unset for metrics
+	// that are not tested
+	PValue *float64 `json:"pValue,omitempty"`
+	// Regressed is set when the metric got worse by more than the tolerance and the change
+	// is significant, or untested
+	Regressed bool `json:"regressed"`
+}
+
+// Result is the comparison of a run with its baseline
+type Result struct {
+	Baseline    Run      `json:"baseline"`
+	Current     Run      `json:"current"`
+	Metrics     []Metric `json:"metrics"`
+	Regressions int      `json:"regressions"`
+}
+
+// Compare compares current with baseline. Latency percentiles share the p-value of a
+// Mann-Whitney U test of the latency samples they are computed from, the error rate is
+// tested with a two-proportion z-test and the establishment throughput with a Mann-Whitney
+// U test of its time buckets. Metrics missing from either report are left out.
+func Compare(baseline, current *report.Report, opts Options) *Result {
+	result := &Result{Baseline: runOf(baseline), Current: runOf(current)}
+
+	for _, latency := range []struct {
+		name    string
+		samples func(report.CRD) *float64
+	}{
+		{"CreateCallLatency", func(crd report.CRD) *float64 { return &crd.CreateSeconds }},
+		{"NamesAcceptedLatency", func(crd report.CRD) *float64 { return crd.NamesAcceptedSeconds }},
+		{"EstablishedLatency", func(crd report.CRD) *float64 { return crd.EstablishedSeconds }},
+	} {
+		b, c := crdSamples(baseline, latency.samples), crdSamples(current, latency.samples)
+		if len(b) == 0 || len(c) == 0 {
+			continue
+		}
+		_, p := stats.MannWhitneyU(b, c)
+		bSummary, cSummary := stats.Summarize(durations(b)), stats.Summarize(durations(c))
+		for _, percentile := range []struct {
+			name     string
+			baseline time.Duration
+			current  time.Duration
+		}{
+			{"P50", bSummary.P50, cSummary.P50},
+			{"P90", bSummary.P90, cSummary.P90},
+			{"P99", bSummary.P99, cSummary.P99},
+		} {
+			result.add(opts, true, Metric{
+				Name:     latency.name + percentile.name,
+				Unit:     Seconds,
+				Baseline: percentile.baseline.Seconds(),
+				Current:  percentile.current.Seconds(),
+				PValue:   &p,
+			})
+		}
+	}
+
+	// Only summaries of the instance create latency are reported, it cannot be tested
+	if b, c := instanceCreate(baseline), instanceCreate(current); b != nil && c != nil {
+		result.add(opts, true, Metric{Name: "InstanceCreateLatencyP50", Unit: Seconds,
+			Baseline: b.P50.Seconds(), Current: c.P50.Seconds()})
+		result.add(opts, true, Metric{Name: "InstanceCreateLatencyP99", Unit: Seconds,
+			Baseline: b.P99.Seconds(), Current: c.P99.Seconds()})
+	}
+
+	if baseline.Requests > 0 && current.Requests > 0 {
+		bErrors, cErrors := errorCount(baseline), errorCount(current)
+		p := stats.TwoProportionP(bErrors, baseline.Requests, cErrors, current.Requests)
+		result.add(opts, true, Metric{
+			Name:     "ErrorRate",
+			Unit:     Percent,
+			Baseline: 100 * float64(bErrors) / float64(baseline.Requests),
+			Current:  100 * float64(cErrors) / float64(current.Requests),
+			PValue:   &p,
+		})
+	}
+
+	if b, c := establishedPerSecond(baseline), establishedPerSecond(current); len(b) > 0 && len(c) > 0 {
+		_, p := stats.MannWhitneyU(b, c)
+		result.add(opts, false, Metric{
+			Name:     "EstablishedThroughput",
+			Unit:     PerSecond,
+			Baseline: mean(b),
+			Current:  mean(c),
+			PValue:   &p,
+		})
+	}
+	return result
+}
+
+// add computes the change of metric and whether it regressed, and adds it to the result.
+// higherIsWorse tells in which direction the metric gets worse.
+func (r *Result) add(opts Options, higherIsWorse bool, metric Metric) {
+	switch {
+	case metric.Baseline != 0:
+		metric.DeltaPercent = 100 * (metric.Current - metric.Baseline) / metric.Baseline
+	case metric.Current != 0:
+		metric.DeltaPercent = 100
+	}
+	worse := metric.DeltaPercent
+	if !higherIsWorse {
+		worse = -worse
+	}
+	metric.Regressed = worse > opts.TolerancePercent && (metric.PValue == nil || *metric.PValue < opts.Alpha)
+	if metric.Regressed {
+		r.Regressions++
+	}
+	r.Metrics = append(r.Metrics, metric)
+}
+
+// Values formats the baseline and current value, the change and the p-value of the metric
+// for display. The p-value is empty when the metric is not tested.
+func (m Metric) Values() (baseline, current, delta, pValue string) {
+	baseline, current = formatValue(m.Baseline, m.Unit), formatValue(m.Current, m.Unit)
+	delta = strconv.FormatFloat(m.DeltaPercent, 'f', 1, 64) + "%"
+	if m.DeltaPercent >= 0 {
+		delta = "+" + delta
+	}
+	if m.PValue != nil {
+		pValue = strconv.FormatFloat(*m.PValue, 'g', 3, 64)
+	}
+	return baseline, current, delta, pValue
+}
+
+// WriteText writes the comparison as a table, one metric per row
+func (r *Result) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Baseline:\t%s\t%s\t%s\n", r.Baseline.Name, r.Baseline.OperatorVersion, r.Baseline.ClusterVersion)
+	fmt.Fprintf(tw, "Current:\t%s\t%s\t%s\n\n", r.Current.Name, r.Current.OperatorVersion, r.Current.ClusterVersion)
+	fmt.Fprintln(tw, "METRIC\tBASELINE\tCURRENT\tDELTA\tP-VALUE\tREGRESSED")
+	for _, metric := range r.Metrics {
+		baseline, current, delta, pValue := metric.Values()
+		if pValue == "" {
+			pValue = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%t\n", metric.Name, baseline, current, delta, pValue, metric.Regressed)
+	}
+	fmt.Fprintf(tw, "\n%d of %d metrics regressed\n", r.Regressions, len(r.Metrics))
+	return tw.Flush()
+}
+
+// formatValue formats a value of a metric with its unit
+func formatValue(v float64, unit Unit) string {
+	if unit == Seconds {
+		return time.Duration(v * float64(time.Second)).Round(time.Millisecond).String()
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64) + string(unit)
+}
+
+// runOf identifies the run of r
+func runOf(r *report.Report) Run {
+	return Run{
+		Name:            r.Run.Namespace + "/" + r.Run.Name,
+		OperatorVersion: r.Run.OperatorVersion,
+		ClusterVersion:  r.Run.ClusterVersion,
+	}
+}
+
+// crdSamples returns the latencies, in seconds, that sample returns for the CRDs of r that have one
+func crdSamples(r *report.Report, sample func(report.CRD) *float64) []float64 {
+	var samples []float64
+	for _, crd := range r.CRDs {
+		if s := sample(crd); s != nil {
+			samples = append(samples, *s)
+		}
+	}
+	return samples
+}
+
+// durations converts samples in seconds into durations
+func durations(samples []float64) []time.Duration {
+	converted := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		converted[i] = time.Duration(s * float64(time.Second))
+	}
+	return converted
+}
+
+// instanceCreate returns the summary of the instance create latency of r, if any
+func instanceCreate(r *report.Report) *stats.Summary {
+	instances := r.Status.Instances
+	if instances == nil || instances.CreateLatency == nil || instances.CreateLatency.Samples == 0 {
+		return nil
+	}
+	return &stats.Summary{
+		Count: int(instances.CreateLatency.Samples),
+		P50:   instances.CreateLatency.P50.Duration,
+		P90:   instances.CreateLatency.P90.Duration,
+		P99:   instances.CreateLatency.P99.Duration,
+	}
+}
+
+// errorCount returns the number of failed requests of r
+func errorCount(r *report.Report) int64 {
+	var count int64
+	for _, e := range r.Errors {
+		count += int64(e.Count)
+	}
+	return count
+}
+
+// establishedPerSecond returns the establishment rate of every throughput bucket of r
+func establishedPerSecond(r *report.Report) []float64 {
+	rates := make([]float64, 0, len(r.Throughput))
+	for _, bucket := range r.Throughput {
+		rates = append(rates, float64(bucket.Established)/report.BucketWidth.Seconds())
+	}
+	return rates
+}
+
+// mean returns the mean of values
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
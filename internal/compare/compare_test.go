@@ -0,0 +1,83 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/report"
+)
+
+// testReport returns the report of a run whose 50 CRDs were established after established
+// plus up to a second, with failed of 100 requests failing
+func testReport(name string, established time.Duration, failed int32) *report.Report {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var crds []report.CRD
+	for i := 0; i < 50; i++ {
+		seconds := (established + time.Duration(i)*20*time.Millisecond).Seconds()
+		crds = append(crds, report.CRD{
+			Name:               "crd",
+			Created:            start.Add(time.Duration(i) * time.Second),
+			CreateSeconds:      0.1,
+			EstablishedSeconds: &seconds,
+		})
+	}
+	errors := []report.ErrorCount{{Operation: "CreateCRD", Reason: "TooManyRequests", Count: failed}}
+	return report.New(report.Run{Name: name, Namespace: "default"},
+		examplev1alpha1.ReconTestStatus{}, crds, 100, errors)
+}
+
+func metric(result *Result, name string) *Metric {
+	for i := range result.Metrics {
+		if result.Metrics[i].Name == name {
+			return &result.Metrics[i]
+		}
+	}
+	return nil
+}
+
+func TestCompareFlagsSignificantRegressions(t *testing.T) {
+	opts := Options{TolerancePercent: 10, Alpha: 0.05}
+	result := Compare(testReport("baseline", time.Second, 1), testReport("current", 2*time.Second, 20), opts)
+
+	for _, name := range []string{"EstablishedLatencyP50", "EstablishedLatencyP99", "ErrorRate"} {
+		m := metric(result, name)
+		if m == nil || !m.Regressed {
+			t.Errorf("expected %s to regress, got %+v", name, m)
+		}
+	}
+	for _, name := range []string{"CreateCallLatencyP50", "EstablishedThroughput"} {
+		m := metric(result, name)
+		if m == nil || m.Regressed {
+			t.Errorf("expected %s not to regress, got %+v", name, m)
+		}
+	}
+	if metric(result, "NamesAcceptedLatencyP50") != nil {
+		t.Errorf("expected metrics missing from the reports to be left out")
+	}
+
+	text := &strings.Builder{}
+	if err := result.WriteText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "regressed\n") {
+		t.Errorf("expected a summary line, got:\n%s", text)
+	}
+}
+
+func TestCompareToleratesInsignificantChanges(t *testing.T) {
+	// 2% slower is within tolerance, 1 more failed request is not significant
+	result := Compare(testReport("baseline", time.Second, 1), testReport("current", 1020*time.Millisecond, 2),
+		Options{TolerancePercent: 10, Alpha: 0.05})
+	if result.Regressions != 0 {
+		t.Errorf("expected no regressions, got %+v", result.Metrics)
+	}
+
+	// Without tolerance the error rate doubles, but not significantly
+	result = Compare(testReport("baseline", time.Second, 1), testReport("current", time.Second, 2),
+		Options{Alpha: 0.05})
+	if m := metric(result, "ErrorRate"); m == nil || m.DeltaPercent != 100 || m.Regressed {
+		t.Errorf("expected an insignificant error rate change, got %+v", m)
+	}
+}
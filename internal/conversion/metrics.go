@@ -0,0 +1,34 @@
+package conversion
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/series"
+)
+
+var (
+	conversionSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "recontest_conversion_seconds",
+		Help:    "Time the conversion webhook took to answer a ConversionReview, injected latency included.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"recontest"})
+
+	conversionRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "recontest_conversion_requests_total",
+		Help: "Number of ConversionReview requests answered by the conversion webhook, by result.",
+	}, []string{"recontest", "result"})
+)
+
+func init() {
+	// Register with the controller-runtime registry served on --metrics-bind-address
+	metrics.Registry.MustRegister(
+		conversionSeconds,
+		conversionRequestsTotal,
+	)
+}
+
+// DeleteRunMetrics deletes the conversion series of a deleted run, given as <namespace>/<name>
+func DeleteRunMetrics(owner string) {
+	series.Delete("recontest", owner, conversionSeconds, conversionRequestsTotal)
+}
@@ -0,0 +1,152 @@
+// Package conversion implements the conversion webhook of the generated CRDs.
+// It converts custom resources between the versions built by crdgen and injects
+// the latency and errors configured on the ReconTest of each run, so that the
+// cost of conversion can be measured as the number of CRDs grows.
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// Results of a conversion request, used as metric label values
+const (
+	resultConverted = "converted"
+	resultInjected  = "injected_error"
+	resultFailed    = "failed"
+)
+
+// Handler serves the ConversionReview requests sent by the API server to crdgen.ConversionPath
+type Handler struct {
+	reader client.Reader
+	log    logr.Logger
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewHandler returns a Handler that reads the fault injection settings of each run through reader
+func NewHandler(reader client.Reader, log logr.Logger) *Handler {
+	return &Handler{
+		reader: reader,
+		log:    log,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// ServeHTTP converts the objects of a ConversionReview
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+
+	review := &apiextensionsv1.ConversionReview{}
+	if err := json.NewDecoder(req.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(w, "expected a ConversionReview request", http.StatusBadRequest)
+		return
+	}
+
+	owner, faults := h.faults(req.Context(), req.URL.Path)
+	if faults.Latency.Duration > 0 {
+		timer := time.NewTimer(faults.Latency.Duration)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	result := resultConverted
+	response := &apiextensionsv1.ConversionResponse{UID: review.Request.UID}
+	if h.injectError(faults.ErrorPercent) {
+		result = resultInjected
+		response.Result = metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: "conversion error injected by the ReconTest",
+		}
+	} else if converted, err := convertAll(review.Request); err != nil {
+		result = resultFailed
+		h.log.Error(err, "Failed to convert objects", "recontest", owner)
+		response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		response.ConvertedObjects = converted
+		response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	}
+
+	conversionRequestsTotal.WithLabelValues(owner, result).Inc()
+	conversionSeconds.WithLabelValues(owner).Observe(time.Since(start).Seconds())
+
+	review.Request = nil
+	review.Response = response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		h.log.Error(err, "Failed to write ConversionReview response")
+	}
+}
+
+// faults returns the owner of the run a request path belongs to and the faults it asks for.
+// Paths without a run, or of runs that no longer exist, get no faults.
+func (h *Handler) faults(ctx context.Context, path string) (string, examplev1alpha1.ConversionWebhookSpec) {
+	owner := strings.Trim(strings.TrimPrefix(path, crdgen.ConversionPath), "/")
+	namespace, name, ok := strings.Cut(owner, "/")
+	if !ok {
+		return owner, examplev1alpha1.ConversionWebhookSpec{}
+	}
+
+	reconTest := &examplev1alpha1.ReconTest{}
+	if err := h.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, reconTest); err != nil {
+		return owner, examplev1alpha1.ConversionWebhookSpec{}
+	}
+	if reconTest.Spec.Versions == nil || reconTest.Spec.Versions.Webhook == nil {
+		return owner, examplev1alpha1.ConversionWebhookSpec{}
+	}
+	return owner, *reconTest.Spec.Versions.Webhook
+}
+
+// injectError reports whether a request should fail, percent times out of a hundred
+func (h *Handler) injectError(percent int32) bool {
+	if percent <= 0 {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.rand.Int31n(100) < percent
+}
+
+// convertAll converts every object of a request to its desired version
+func convertAll(request *apiextensionsv1.ConversionRequest) ([]runtime.RawExtension, error) {
+	converted := make([]runtime.RawExtension, 0, len(request.Objects))
+	for i := range request.Objects {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(request.Objects[i].Raw); err != nil {
+			return nil, fmt.Errorf("decoding object %d: %w", i, err)
+		}
+		if err := crdgen.Convert(obj, request.DesiredAPIVersion); err != nil {
+			return nil, fmt.Errorf("converting %s: %w", obj.GetName(), err)
+		}
+		raw, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+	return converted, nil
+}
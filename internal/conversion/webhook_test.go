@@ -0,0 +1,89 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// review sends a ConversionReview of obj to h on path and returns the response
+func review(t *testing.T, h http.Handler, path string, obj map[string]interface{},
+	desired string) *apiextensionsv1.ConversionResponse {
+	t.Helper()
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               "1",
+			DesiredAPIVersion: desired,
+			Objects:           []runtime.RawExtension{{Raw: raw}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	out := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Response
+}
+
+func TestHandlerConvertsAndInjectsErrors(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := examplev1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	reconTest := &examplev1alpha1.ReconTest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "faulty"},
+		Spec: examplev1alpha1.ReconTestSpec{
+			Versions: &examplev1alpha1.VersionsSpec{
+				Count:      2,
+				Conversion: examplev1alpha1.ConversionWebhook,
+				Webhook:    &examplev1alpha1.ConversionWebhookSpec{ErrorPercent: 100},
+			},
+		},
+	}
+	h := NewHandler(fake.NewClientBuilder().WithScheme(scheme).WithObjects(reconTest).Build(), logr.Discard())
+
+	obj := map[string]interface{}{
+		"apiVersion": "example.anirudh.io/v1alpha1",
+		"kind":       "ComplexRecontest1",
+		"metadata":   map[string]interface{}{"name": "sample"},
+		"spec":       map[string]interface{}{"name": "value"},
+	}
+
+	response := review(t, h, "/convert", obj, "example.anirudh.io/v1alpha2")
+	if response.Result.Status != metav1.StatusSuccess || len(response.ConvertedObjects) != 1 {
+		t.Fatalf("expected a successful conversion, got %+v", response.Result)
+	}
+	converted := &unstructured.Unstructured{}
+	if err := converted.UnmarshalJSON(response.ConvertedObjects[0].Raw); err != nil {
+		t.Fatal(err)
+	}
+	if value, _, _ := unstructured.NestedString(converted.Object, "spec", "v2", "nameV2"); value != "value" {
+		t.Fatalf("expected spec.name to move to spec.v2.nameV2, got %v", converted.Object["spec"])
+	}
+
+	response = review(t, h, "/convert/default/faulty", obj, "example.anirudh.io/v1alpha2")
+	if response.Result.Status != metav1.StatusFailure {
+		t.Fatalf("expected an injected error, got %+v", response.Result)
+	}
+}
@@ -0,0 +1,65 @@
+package crdgen
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsvalidation "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/validation"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/synth"
+)
+
+func TestCELRulesAreAcceptedAndSatisfied(t *testing.T) {
+	for _, cost := range []examplev1alpha1.CELCost{
+		examplev1alpha1.CELCostLow, examplev1alpha1.CELCostMedium, examplev1alpha1.CELCostHigh,
+	} {
+		for _, schemaSpec := range []examplev1alpha1.SchemaSpec{
+			{Preset: "Complex", CEL: &examplev1alpha1.CELSpec{}},
+			// Rules below nested arrays are estimated for every item of every enclosing array
+			{Depth: 3, FanOut: 4, ArrayNesting: 2, Patterns: 4, Formats: 5, Enums: 2,
+				CEL: &examplev1alpha1.CELSpec{MaxItems: 8}},
+		} {
+			schemaSpec.CEL.RulesPerNode, schemaSpec.CEL.Cost = 16, cost
+			spec := WithDefaults(examplev1alpha1.ReconTestSpec{Schema: &schemaSpec})
+			schema, err := Schema(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The API server compiles the rules and checks their estimated cost on creation
+			crd := &apiextensions.CustomResourceDefinition{}
+			err = v1.Convert_v1_CustomResourceDefinition_To_apiextensions_CustomResourceDefinition(
+				CRD(nil, spec, 1, schema, nil), crd, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			crd.Status.StoredVersions = []string{StorageVersion(spec)}
+			if errs := apiextensionsvalidation.ValidateCustomResourceDefinition(context.Background(), crd); len(errs) > 0 {
+				t.Fatalf("%s rules of %+v rejected: %v", cost, schemaSpec, errs.ToAggregate())
+			}
+
+			structural, err := structuralschema.NewStructural(crd.Spec.Validation.OpenAPIV3Schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			validator := cel.NewValidator(structural, cel.PerCallLimit)
+			for seed := int64(0); seed < 10; seed++ {
+				obj, err := synth.Object(schema, seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				requireValid(t, schema, obj.Object)
+				errs, _ := validator.Validate(context.Background(), nil, structural, obj.Object, nil,
+					cel.RuntimeCELCostBudget)
+				if len(errs) > 0 {
+					t.Fatalf("%s rules of %+v not satisfied: %v", cost, schemaSpec, errs.ToAggregate())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,347 @@
+// Package crdgen turns a ReconTestSpec into the CustomResourceDefinitions of a run.
+// It is shared by the controller and the offline generate command, so the CRDs
+// created in a cluster and the ones written to disk are identical.
+package crdgen
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+)
+
+// Values used for ReconTestSpec fields that are left unset
+const (
+	defaultCount      = 1000
+	defaultGroup      = "example.anirudh.io"
+	defaultNamePrefix = "complexrecontests"
+	defaultKindPrefix = "ComplexRecontest"
+	defaultStartIndex = 1
+
+	defaultSchemaDepth    = 3
+	defaultSchemaFanOut   = 4
+	defaultSchemaEnumSize = 4
+
+	defaultCELDensity      = 100
+	defaultCELRulesPerNode = 1
+	defaultCELMaxItems     = 16
+
+	defaultChurnPercent  = 10
+	defaultChurnInterval = time.Minute
+
+	defaultSchemaChurnPercent  = 10
+	defaultSchemaChurnInterval = time.Minute
+
+	defaultDiscoveryInterval = time.Second
+	defaultDiscoveryTimeout  = 5 * time.Minute
+
+	defaultReportInterval = time.Minute
+
+	defaultWatchesPerKind     = 1
+	defaultWatchProbeInterval = 10 * time.Second
+
+	defaultListPageSize = 500
+
+	defaultFieldManager       = "recontest-operator"
+	defaultCompetitorManager  = "recontest-competitor"
+	defaultCompetitorPercent  = 10
+	defaultCompetitorInterval = 30 * time.Second
+
+	defaultBaselineConfidence = 95
+)
+
+// WithDefaults returns a copy of spec with every unset field set to its default
+func WithDefaults(spec examplev1alpha1.ReconTestSpec) examplev1alpha1.ReconTestSpec {
+	if spec.Count == 0 {
+		spec.Count = defaultCount
+	}
+	if spec.Group == "" {
+		spec.Group = defaultGroup
+	}
+	if spec.NamePrefix == "" {
+		spec.NamePrefix = defaultNamePrefix
+	}
+	if spec.KindPrefix == "" {
+		spec.KindPrefix = defaultKindPrefix
+	}
+	if spec.Scope == "" {
+		spec.Scope = examplev1alpha1.NamespacedScope
+	}
+	if spec.StartIndex == 0 {
+		spec.StartIndex = defaultStartIndex
+	}
+	if spec.DriftPolicy == "" {
+		spec.DriftPolicy = examplev1alpha1.DriftIgnore
+	}
+	if spec.CleanupPolicy == "" {
+		spec.CleanupPolicy = examplev1alpha1.CleanupDelete
+	}
+	if spec.Versions != nil {
+		spec.Versions = spec.Versions.DeepCopy()
+		if spec.Versions.Count == 0 {
+			spec.Versions.Count = 1
+		}
+		if spec.Versions.Conversion == "" {
+			spec.Versions.Conversion = examplev1alpha1.ConversionNone
+		}
+	}
+	if spec.Churn != nil {
+		spec.Churn = spec.Churn.DeepCopy()
+		if spec.Churn.Percent == 0 {
+			spec.Churn.Percent = defaultChurnPercent
+		}
+		if spec.Churn.Interval.Duration == 0 {
+			spec.Churn.Interval.Duration = defaultChurnInterval
+		}
+	}
+	if spec.SchemaChurn != nil {
+		spec.SchemaChurn = spec.SchemaChurn.DeepCopy()
+		if spec.SchemaChurn.Percent == 0 {
+			spec.SchemaChurn.Percent = defaultSchemaChurnPercent
+		}
+		if spec.SchemaChurn.Interval.Duration == 0 {
+			spec.SchemaChurn.Interval.Duration = defaultSchemaChurnInterval
+		}
+		if len(spec.SchemaChurn.Changes) == 0 {
+			spec.SchemaChurn.Changes = []examplev1alpha1.SchemaChange{
+				examplev1alpha1.SchemaAddOptionalProperty,
+				examplev1alpha1.SchemaLoosenMaximum,
+				examplev1alpha1.SchemaAddEnumValue,
+			}
+		}
+	}
+	if spec.Discovery != nil {
+		spec.Discovery = spec.Discovery.DeepCopy()
+		if len(spec.Discovery.Endpoints) == 0 {
+			spec.Discovery.Endpoints = []examplev1alpha1.DiscoveryEndpoint{
+				examplev1alpha1.DiscoveryGroupVersion,
+				examplev1alpha1.DiscoveryAggregated,
+				examplev1alpha1.DiscoveryOpenAPIV3,
+			}
+		}
+		if spec.Discovery.Interval.Duration == 0 {
+			spec.Discovery.Interval.Duration = defaultDiscoveryInterval
+		}
+		if spec.Discovery.Timeout.Duration == 0 {
+			spec.Discovery.Timeout.Duration = defaultDiscoveryTimeout
+		}
+	}
+	if spec.Watches != nil {
+		spec.Watches = spec.Watches.DeepCopy()
+		if spec.Watches.PerKind == 0 {
+			spec.Watches.PerKind = defaultWatchesPerKind
+		}
+		if spec.Watches.Mode == "" {
+			spec.Watches.Mode = examplev1alpha1.WatchRaw
+		}
+		if spec.Watches.ProbeInterval.Duration == 0 {
+			spec.Watches.ProbeInterval.Duration = defaultWatchProbeInterval
+		}
+	}
+	if spec.Lists != nil {
+		spec.Lists = spec.Lists.DeepCopy()
+		if len(spec.Lists.Targets) == 0 {
+			spec.Lists.Targets = []examplev1alpha1.ListTarget{
+				examplev1alpha1.ListCRDs,
+				examplev1alpha1.ListCustomResources,
+			}
+		}
+		if spec.Lists.PageSize == nil {
+			pageSize := int32(defaultListPageSize)
+			spec.Lists.PageSize = &pageSize
+		}
+		if spec.Lists.ResourceVersion == "" {
+			spec.Lists.ResourceVersion = examplev1alpha1.ListResourceVersionNone
+		}
+	}
+	if spec.Writes != nil {
+		spec.Writes = spec.Writes.DeepCopy()
+		if spec.Writes.Mode == "" {
+			spec.Writes.Mode = examplev1alpha1.WriteCreate
+		}
+		if spec.Writes.FieldManager == "" {
+			spec.Writes.FieldManager = defaultFieldManager
+		}
+		if competitor := spec.Writes.Competitor; competitor != nil {
+			if competitor.FieldManager == "" {
+				competitor.FieldManager = defaultCompetitorManager
+			}
+			if competitor.Percent == 0 {
+				competitor.Percent = defaultCompetitorPercent
+			}
+			if competitor.Interval.Duration == 0 {
+				competitor.Interval.Duration = defaultCompetitorInterval
+			}
+		}
+	}
+	if spec.Report != nil {
+		spec.Report = spec.Report.DeepCopy()
+		if len(spec.Report.Formats) == 0 {
+			spec.Report.Formats = []examplev1alpha1.ReportFormat{
+				examplev1alpha1.ReportJSON,
+				examplev1alpha1.ReportCSV,
+				examplev1alpha1.ReportJUnit,
+			}
+		}
+		if len(spec.Report.Destinations) == 0 {
+			spec.Report.Destinations = []examplev1alpha1.ReportDestination{examplev1alpha1.ReportConfigMap}
+		}
+		if spec.Report.Interval.Duration == 0 {
+			spec.Report.Interval.Duration = defaultReportInterval
+		}
+	}
+	if spec.BaselineRef != nil {
+		spec.BaselineRef = spec.BaselineRef.DeepCopy()
+		// A tolerance of zero is meaningful, only the confidence has an unset value
+		if spec.BaselineRef.Confidence == 0 {
+			spec.BaselineRef.Confidence = defaultBaselineConfidence
+		}
+	}
+	if spec.Schema != nil {
+		spec.Schema = spec.Schema.DeepCopy()
+		if spec.Schema.Depth == 0 {
+			spec.Schema.Depth = defaultSchemaDepth
+		}
+		if spec.Schema.FanOut == 0 {
+			spec.Schema.FanOut = defaultSchemaFanOut
+		}
+		if spec.Schema.EnumSize == 0 {
+			spec.Schema.EnumSize = defaultSchemaEnumSize
+		}
+		if cel := spec.Schema.CEL; cel != nil {
+			if cel.Density == 0 {
+				cel.Density = defaultCELDensity
+			}
+			if cel.RulesPerNode == 0 {
+				cel.RulesPerNode = defaultCELRulesPerNode
+			}
+			if cel.Cost == "" {
+				cel.Cost = examplev1alpha1.CELCostLow
+			}
+			if cel.MaxItems == 0 {
+				cel.MaxItems = defaultCELMaxItems
+			}
+		}
+	}
+	return spec
+}
+
+// Schema returns the OpenAPI v3 schema shared by the CRDs generated for spec
+func Schema(spec examplev1alpha1.ReconTestSpec) (*v1.JSONSchemaProps, error) {
+	if spec.Schema == nil {
+		return schemagen.Complex(), nil
+	}
+
+	var schema *v1.JSONSchemaProps
+	var err error
+	if spec.Schema.Preset != "" {
+		schema, err = schemagen.Preset(string(spec.Schema.Preset))
+	} else {
+		schema, err = schemagen.Generate(schemagen.Params{
+			Depth:        int(spec.Schema.Depth),
+			FanOut:       int(spec.Schema.FanOut),
+			ArrayNesting: int(spec.Schema.ArrayNesting),
+			Enums:        int(spec.Schema.Enums),
+			EnumSize:     int(spec.Schema.EnumSize),
+			Patterns:     int(spec.Schema.Patterns),
+			Formats:      int(spec.Schema.Formats),
+			TargetBytes:  int(spec.Schema.TargetBytes),
+		})
+	}
+	if err != nil || spec.Schema.CEL == nil {
+		return schema, err
+	}
+
+	if _, err := schemagen.AddValidations(schema, schemagen.CELParams{
+		Density:      int(spec.Schema.CEL.Density),
+		RulesPerNode: int(spec.Schema.CEL.RulesPerNode),
+		Cost:         string(spec.Schema.CEL.Cost),
+		MaxItems:     int(spec.Schema.CEL.MaxItems),
+	}); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Indices returns the indices of all CRDs generated for spec
+func Indices(spec examplev1alpha1.ReconTestSpec) []int {
+	indices := make([]int, 0, spec.Count)
+	for i := int(spec.StartIndex); i < int(spec.StartIndex)+int(spec.Count); i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// Names returns the spec.names of the CRD with the given index
+func Names(spec examplev1alpha1.ReconTestSpec, index int) v1.CustomResourceDefinitionNames {
+	kind := fmt.Sprintf("%s%d", spec.KindPrefix, index)
+	return v1.CustomResourceDefinitionNames{
+		Kind:     kind,
+		ListKind: kind + "List",
+		Plural:   fmt.Sprintf("%s%d", spec.NamePrefix, index),
+		Singular: strings.ToLower(kind),
+	}
+}
+
+// Name returns the object name of the CRD with the given index, in the <plural>.<group> form
+func Name(spec examplev1alpha1.ReconTestSpec, index int) string {
+	return fmt.Sprintf("%s%d.%s", spec.NamePrefix, index, spec.Group)
+}
+
+// CRD creates the CustomResourceDefinition for the given index of a run, using a copy of schema.
+// reconTest is the owner of the run and may be nil when the CRD is generated outside a cluster,
+// in which case the CRD carries no run label or owner annotation. webhook is only used when the
+// versions of spec are converted by the conversion webhook.
+func CRD(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	index int, schema *v1.JSONSchemaProps, webhook *WebhookConfig) *v1.CustomResourceDefinition {
+	crd := &v1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: Name(spec, index),
+			Labels: map[string]string{
+				"generated-by": "complex-recontest-controller",
+				"complexity":   "high",
+				"index":        fmt.Sprintf("%d", index),
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group:      spec.Group,
+			Names:      Names(spec, index),
+			Scope:      v1.ResourceScope(spec.Scope),
+			Versions:   versions(spec, schema),
+			Conversion: conversion(reconTest, spec, webhook),
+		},
+	}
+	crd.Annotations[examplev1alpha1.SpecHashAnnotation] = SpecHash(crd.Spec)
+	if reconTest != nil {
+		crd.Labels[examplev1alpha1.RunLabel] = string(reconTest.UID)
+		crd.Annotations[examplev1alpha1.OwnerAnnotation] = fmt.Sprintf("%s/%s", reconTest.Namespace, reconTest.Name)
+	}
+
+	return crd
+}
+
+// SpecHash returns a hash of the parts of a CRD spec the generator controls. Fields the
+// API server defaults are normalised, so the hash of a live spec matches its intended spec.
+func SpecHash(spec v1.CustomResourceDefinitionSpec) string {
+	spec = *spec.DeepCopy()
+	if spec.Conversion != nil && spec.Conversion.Strategy == v1.NoneConverter {
+		spec.Conversion = nil
+	}
+	spec.PreserveUnknownFields = false
+
+	// Marshalling only fails on invalid raw JSON, which neither the generator nor the API server produces
+	raw, _ := json.Marshal(spec)
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
@@ -0,0 +1,83 @@
+package crdgen
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+)
+
+// SchemaRevision returns the schema revision of a generated CRD, zero when schema churn never updated it
+func SchemaRevision(crd *v1.CustomResourceDefinition) int {
+	revision, err := strconv.Atoi(crd.Annotations[examplev1alpha1.SchemaRevisionAnnotation])
+	if err != nil || revision < 0 {
+		return 0
+	}
+	return revision
+}
+
+// Revise brings the schema of every version of crd from its schema revision up to revision.
+// Revision n applies the n-th of changes, wrapping around, so a CRD generated afresh and
+// revised to n has the same spec as one updated n times. The revision and spec hash
+// annotations of crd are updated to match. With webhook conversion only the first version
+// is changed, and the others are derived from it again with VersionSchema, so the fields
+// the changes add or remove are renamed and moved like every other field.
+func Revise(crd *v1.CustomResourceDefinition, changes []examplev1alpha1.SchemaChange, revision int) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	webhook := crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == v1.WebhookConverter
+	for next := SchemaRevision(crd) + 1; next <= revision; next++ {
+		change := string(changes[(next-1)%len(changes)])
+		for i := range crd.Spec.Versions {
+			version := &crd.Spec.Versions[i]
+			if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil ||
+				(webhook && version.Name != VersionName(1)) {
+				continue
+			}
+			if err := schemagen.Mutate(version.Schema.OpenAPIV3Schema, change, next); err != nil {
+				return err
+			}
+		}
+	}
+	if webhook {
+		if err := deriveVersions(crd); err != nil {
+			return err
+		}
+	}
+
+	if crd.Annotations == nil {
+		crd.Annotations = map[string]string{}
+	}
+	crd.Annotations[examplev1alpha1.SchemaRevisionAnnotation] = strconv.Itoa(revision)
+	crd.Annotations[examplev1alpha1.SpecHashAnnotation] = SpecHash(crd.Spec)
+	return nil
+}
+
+// deriveVersions replaces the schema of every later version of a CRD converted by the webhook
+// with the one VersionSchema derives from its first version
+func deriveVersions(crd *v1.CustomResourceDefinition) error {
+	var first *v1.JSONSchemaProps
+	for _, version := range crd.Spec.Versions {
+		if version.Name == VersionName(1) && version.Schema != nil {
+			first = version.Schema.OpenAPIV3Schema
+		}
+	}
+	if first == nil {
+		return fmt.Errorf("CRD %s has no schema of version %s to derive the others from", crd.Name, VersionName(1))
+	}
+	for i := range crd.Spec.Versions {
+		version := &crd.Spec.Versions[i]
+		index, err := versionIndex(version.Name)
+		if err != nil {
+			return err
+		}
+		if index > 1 {
+			version.Schema = &v1.CustomResourceValidation{OpenAPIV3Schema: VersionSchema(first, index)}
+		}
+	}
+	return nil
+}
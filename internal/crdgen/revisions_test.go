@@ -0,0 +1,91 @@
+package crdgen
+
+import (
+	"reflect"
+	"testing"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/synth"
+)
+
+func TestReviseMatchesIncrementalUpdates(t *testing.T) {
+	spec := WithDefaults(examplev1alpha1.ReconTestSpec{
+		Versions:    &examplev1alpha1.VersionsSpec{Count: 2},
+		SchemaChurn: &examplev1alpha1.SchemaChurnSpec{},
+	})
+	changes := spec.SchemaChurn.Changes
+
+	incremental := CRD(nil, spec, 1, schemagen.Complex(), nil)
+	for revision := 1; revision <= 4; revision++ {
+		// Objects valid before a compatible change stay valid after it
+		before, err := synth.Object(incremental.Spec.Versions[0].Schema.OpenAPIV3Schema, int64(revision))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Revise(incremental, changes, revision); err != nil {
+			t.Fatal(err)
+		}
+		requireValid(t, incremental.Spec.Versions[0].Schema.OpenAPIV3Schema, before.Object)
+	}
+
+	direct := CRD(nil, spec, 1, schemagen.Complex(), nil)
+	if err := Revise(direct, changes, 4); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(direct.Spec, incremental.Spec) {
+		t.Error("revising at once and one revision at a time produced different specs")
+	}
+	if SchemaRevision(direct) != 4 {
+		t.Errorf("expected revision 4, got %d", SchemaRevision(direct))
+	}
+	if direct.Annotations[examplev1alpha1.SpecHashAnnotation] != SpecHash(direct.Spec) {
+		t.Error("expected the spec hash to follow the revised spec")
+	}
+}
+
+func TestReviseWithWebhookConversion(t *testing.T) {
+	spec := WithDefaults(examplev1alpha1.ReconTestSpec{
+		Versions: &examplev1alpha1.VersionsSpec{Count: 3, Conversion: examplev1alpha1.ConversionWebhook},
+		SchemaChurn: &examplev1alpha1.SchemaChurnSpec{Changes: []examplev1alpha1.SchemaChange{
+			examplev1alpha1.SchemaAddRequiredProperty, examplev1alpha1.SchemaRemoveProperty,
+		}},
+	})
+	crd := CRD(nil, spec, 1, schemagen.Complex(), nil)
+	if err := Revise(crd, spec.SchemaChurn.Changes, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	first := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+	if _, ok := first.Properties["spec"].Properties["revision1"]; !ok {
+		t.Fatal("expected revision 1 to add its property to the first version")
+	}
+	for index := 2; index <= 3; index++ {
+		schema := crd.Spec.Versions[index-1].Schema.OpenAPIV3Schema
+		// Every version holds the same fields, renamed and moved, so conversion loses nothing
+		if !reflect.DeepEqual(schema, VersionSchema(first, index)) {
+			t.Errorf("expected %s to be derived from the revised first version", VersionName(index))
+		}
+	}
+
+	for from := 1; from <= 3; from++ {
+		original, err := synth.Object(crd.Spec.Versions[from-1].Schema.OpenAPIV3Schema, int64(from))
+		if err != nil {
+			t.Fatal(err)
+		}
+		original.SetAPIVersion("example.anirudh.io/" + VersionName(from))
+		for to := 1; to <= 3; to++ {
+			converted := original.DeepCopy()
+			if err := Convert(converted, "example.anirudh.io/"+VersionName(to)); err != nil {
+				t.Fatal(err)
+			}
+			requireValid(t, crd.Spec.Versions[to-1].Schema.OpenAPIV3Schema, converted.Object)
+			if err := Convert(converted, original.GetAPIVersion()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(original.Object, converted.Object) {
+				t.Fatalf("converting %s to %s and back lost data", VersionName(from), VersionName(to))
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package crdgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// ConversionPath is the path the conversion webhook of the operator is served on. The
+// CRDs of a run append /<namespace>/<name> of their ReconTest to it.
+const ConversionPath = "/convert"
+
+// versionPrefix is the common prefix of the names of the generated versions
+const versionPrefix = "v1alpha"
+
+// etcdRequestLimit is the default size limit of the requests etcd accepts, 1.5MiB
+const etcdRequestLimit = 3 << 19
+
+// crdHeadroom is the room a CRD needs beside the schemas of its versions under etcdRequestLimit,
+// for its metadata, managedFields, names and status
+const crdHeadroom = 256 << 10
+
+// WebhookConfig tells the API server where to reach the conversion webhook of the operator
+type WebhookConfig struct {
+	// Service is the service in front of the webhook server when the operator runs in the cluster
+	Service *ServiceReference
+	// URL is the base URL of the webhook server when the operator runs outside the cluster
+	URL string
+	// CABundle is the PEM encoded CA bundle that signed the serving certificate of the webhook server
+	CABundle []byte
+}
+
+// ServiceReference names the service in front of the webhook server
+type ServiceReference struct {
+	Namespace string
+	Name      string
+	Port      int32
+}
+
+// VersionName returns the name of the version with the given 1-based index
+func VersionName(index int) string {
+	return fmt.Sprintf("%s%d", versionPrefix, index)
+}
+
+// versionIndex returns the 1-based index of a generated version name
+func versionIndex(name string) (int, error) {
+	index, err := strconv.Atoi(strings.TrimPrefix(name, versionPrefix))
+	if err != nil || !strings.HasPrefix(name, versionPrefix) || index < 1 {
+		return 0, fmt.Errorf("%q is not a generated version", name)
+	}
+	return index, nil
+}
+
+// versionCount returns the number of versions of the CRDs generated for spec
+func versionCount(spec examplev1alpha1.ReconTestSpec) int {
+	if spec.Versions == nil {
+		return 1
+	}
+	return int(spec.Versions.Count)
+}
+
+// StorageVersion returns the name of the storage version of the CRDs generated for spec
+func StorageVersion(spec examplev1alpha1.ReconTestSpec) string {
+	if spec.Versions == nil || spec.Versions.Storage == "" {
+		return VersionName(1)
+	}
+	return spec.Versions.Storage
+}
+
+// UsesWebhook reports whether the CRDs generated for spec are converted by the webhook of the operator
+func UsesWebhook(spec examplev1alpha1.ReconTestSpec) bool {
+	return spec.Versions != nil && spec.Versions.Conversion == examplev1alpha1.ConversionWebhook
+}
+
+// Validate checks the version settings of spec, which the ReconTest schema cannot check on its own,
+// and that the schema of every version fits in a CRD
+func Validate(spec examplev1alpha1.ReconTestSpec) error {
+	count := versionCount(spec)
+	for _, version := range []struct{ field, name string }{
+		{"versions.storage", StorageVersion(spec)},
+		{"instances.version", InstancesVersion(spec)},
+	} {
+		index, err := versionIndex(version.name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", version.field, err)
+		}
+		if index > count {
+			return fmt.Errorf("%s: %s is not one of the %d generated versions", version.field, version.name, count)
+		}
+	}
+	// Every served version holds its own copy of the schema
+	if spec.Schema != nil && int(spec.Schema.TargetBytes)*count+crdHeadroom > etcdRequestLimit {
+		return fmt.Errorf("schema.targetBytes: %d versions of %d bytes do not fit in a CRD under the %d byte "+
+			"request limit of etcd, at most %d bytes each do", count, spec.Schema.TargetBytes, etcdRequestLimit,
+			(etcdRequestLimit-crdHeadroom)/count)
+	}
+	return nil
+}
+
+// InstancesVersion returns the version the custom resources of the CRDs generated for spec are written in
+func InstancesVersion(spec examplev1alpha1.ReconTestSpec) string {
+	if spec.Instances == nil || spec.Instances.Version == "" {
+		return StorageVersion(spec)
+	}
+	return spec.Instances.Version
+}
+
+// versions returns the versions of a CRD generated for spec
+func versions(spec examplev1alpha1.ReconTestSpec, schema *v1.JSONSchemaProps) []v1.CustomResourceDefinitionVersion {
+	storage := StorageVersion(spec)
+	out := make([]v1.CustomResourceDefinitionVersion, 0, versionCount(spec))
+	for index := 1; index <= versionCount(spec); index++ {
+		versionSchema := schema.DeepCopy()
+		// Without a webhook the API server only rewrites apiVersion, so the schemas must match
+		if UsesWebhook(spec) {
+			versionSchema = VersionSchema(schema, index)
+		}
+
+		name := VersionName(index)
+		out = append(out, v1.CustomResourceDefinitionVersion{
+			Name:    name,
+			Served:  true,
+			Storage: name == storage,
+			Schema: &v1.CustomResourceValidation{
+				OpenAPIV3Schema: versionSchema,
+			},
+		})
+	}
+	return out
+}
+
+// conversion returns the conversion settings of a CRD generated for spec, or nil for the None strategy
+func conversion(reconTest *examplev1alpha1.ReconTest, spec examplev1alpha1.ReconTestSpec,
+	webhook *WebhookConfig) *v1.CustomResourceConversion {
+	if !UsesWebhook(spec) {
+		return nil
+	}
+
+	path := ConversionPath
+	if reconTest != nil {
+		path = fmt.Sprintf("%s/%s/%s", ConversionPath, reconTest.Namespace, reconTest.Name)
+	}
+	clientConfig := &v1.WebhookClientConfig{}
+	if webhook != nil {
+		clientConfig.CABundle = webhook.CABundle
+		if webhook.Service != nil {
+			port := webhook.Service.Port
+			clientConfig.Service = &v1.ServiceReference{
+				Namespace: webhook.Service.Namespace,
+				Name:      webhook.Service.Name,
+				Path:      &path,
+				Port:      &port,
+			}
+		} else {
+			url := strings.TrimSuffix(webhook.URL, "/") + path
+			clientConfig.URL = &url
+		}
+	}
+
+	return &v1.CustomResourceConversion{
+		Strategy: v1.WebhookConverter,
+		Webhook: &v1.WebhookConversion{
+			ClientConfig:             clientConfig,
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
+// VersionSchema returns the schema of the version with the given index when versions are
+// converted by the webhook. The first version is schema itself. Every later version renames
+// the spec fields of the first one with a V<index> suffix and moves them under spec.v<index>.
+func VersionSchema(schema *v1.JSONSchemaProps, index int) *v1.JSONSchemaProps {
+	root := schema.DeepCopy()
+	spec, ok := root.Properties["spec"]
+	if index == 1 || !ok {
+		return root
+	}
+
+	suffix := fmt.Sprintf("V%d", index)
+	renamed := v1.JSONSchemaProps{
+		Type:       "object",
+		Properties: make(map[string]v1.JSONSchemaProps, len(spec.Properties)),
+	}
+	for name, prop := range spec.Properties {
+		renamed.Properties[name+suffix] = prop
+	}
+	for _, name := range spec.Required {
+		renamed.Required = append(renamed.Required, name+suffix)
+	}
+
+	wrapper := fmt.Sprintf("v%d", index)
+	spec.Properties = map[string]v1.JSONSchemaProps{wrapper: renamed}
+	spec.Required = []string{wrapper}
+	root.Properties["spec"] = spec
+	return root
+}
+
+// Convert rewrites a custom resource of a generated CRD from its current version to the
+// version of apiVersion, undoing and applying the renames and moves of VersionSchema
+func Convert(obj *unstructured.Unstructured, apiVersion string) error {
+	from, err := versionIndex(obj.GroupVersionKind().Version)
+	if err != nil {
+		return err
+	}
+	to, err := versionIndex(apiVersion[strings.LastIndex(apiVersion, "/")+1:])
+	if err != nil {
+		return err
+	}
+
+	if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
+		obj.Object["spec"] = specFromFirst(specToFirst(spec, from), to)
+	}
+	obj.SetAPIVersion(apiVersion)
+	return nil
+}
+
+// specToFirst returns the spec of the first version holding the same data as spec of version index
+func specToFirst(spec map[string]interface{}, index int) map[string]interface{} {
+	if index == 1 {
+		return spec
+	}
+
+	suffix := fmt.Sprintf("V%d", index)
+	inner, _ := spec[fmt.Sprintf("v%d", index)].(map[string]interface{})
+	out := make(map[string]interface{}, len(inner))
+	for name, value := range inner {
+		out[strings.TrimSuffix(name, suffix)] = value
+	}
+	return out
+}
+
+// specFromFirst returns the spec of version index holding the same data as spec of the first version
+func specFromFirst(spec map[string]interface{}, index int) map[string]interface{} {
+	if index == 1 {
+		return spec
+	}
+
+	suffix := fmt.Sprintf("V%d", index)
+	renamed := make(map[string]interface{}, len(spec))
+	for name, value := range spec {
+		renamed[name+suffix] = value
+	}
+	return map[string]interface{}{fmt.Sprintf("v%d", index): renamed}
+}
@@ -0,0 +1,92 @@
+package crdgen
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/synth"
+)
+
+// requireValid fails the test unless content passes the validation the API server applies for schema
+func requireValid(t *testing.T, schema *v1.JSONSchemaProps, content map[string]interface{}) {
+	t.Helper()
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internal, nil); err != nil {
+		t.Fatalf("converting schema: %v", err)
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+	if err != nil {
+		t.Fatalf("building validator: %v", err)
+	}
+	if errs := validation.ValidateCustomResource(nil, content, validator); len(errs) > 0 {
+		t.Fatalf("content does not conform to the schema: %v", errs.ToAggregate())
+	}
+}
+
+func TestConvertRoundTripsBetweenVersions(t *testing.T) {
+	schema := schemagen.Complex()
+	const versions = 3
+
+	for from := 1; from <= versions; from++ {
+		original, err := synth.Object(VersionSchema(schema, from), int64(from))
+		if err != nil {
+			t.Fatal(err)
+		}
+		original.SetAPIVersion("example.anirudh.io/" + VersionName(from))
+
+		for to := 1; to <= versions; to++ {
+			converted := original.DeepCopy()
+			if err := Convert(converted, "example.anirudh.io/"+VersionName(to)); err != nil {
+				t.Fatal(err)
+			}
+			requireValid(t, VersionSchema(schema, to), converted.Object)
+
+			if err := Convert(converted, original.GetAPIVersion()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(original.Object, converted.Object) {
+				t.Fatalf("converting %s to %s and back lost data", VersionName(from), VersionName(to))
+			}
+		}
+	}
+}
+
+func TestValidateRejectsUnknownVersions(t *testing.T) {
+	spec := WithDefaults(examplev1alpha1.ReconTestSpec{
+		Versions:  &examplev1alpha1.VersionsSpec{Count: 2, Storage: "v1alpha2"},
+		Instances: &examplev1alpha1.InstancesSpec{PerCRD: 1, Version: "v1alpha1"},
+	})
+	if err := Validate(spec); err != nil {
+		t.Fatalf("expected a valid spec, got %v", err)
+	}
+
+	spec.Versions.Storage = "v1alpha3"
+	if err := Validate(spec); err == nil {
+		t.Fatal("expected a storage version outside the generated versions to be rejected")
+	}
+}
+
+func TestValidateRejectsSchemasLargerThanACRD(t *testing.T) {
+	spec := WithDefaults(examplev1alpha1.ReconTestSpec{
+		Schema: &examplev1alpha1.SchemaSpec{Depth: 2, FanOut: 2, TargetBytes: 1 << 20},
+	})
+	if err := Validate(spec); err != nil {
+		t.Fatalf("expected a single version of 1MiB to fit, got %v", err)
+	}
+
+	spec.Versions = &examplev1alpha1.VersionsSpec{Count: 2}
+	if err := Validate(spec); err == nil {
+		t.Fatal("expected two versions of 1MiB to be rejected")
+	}
+	spec.Schema.TargetBytes = 512 << 10
+	if err := Validate(spec); err != nil {
+		t.Fatalf("expected two versions of 512KiB to fit, got %v", err)
+	}
+}
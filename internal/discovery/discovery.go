@@ -0,0 +1,284 @@
+// Package discovery reads the kinds the API server publishes to clients through
+// its discovery and OpenAPI endpoints. A CRD is only usable by kubectl and other
+// clients once its kind shows up there, which can lag well behind the CRD being
+// established while many CRDs change at once.
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// ErrUnsupported is returned for endpoints the API server does not serve
+var ErrUnsupported = errors.New("endpoint not served by the API server")
+
+// aggregatedAccept asks for the aggregated discovery document, falling back to the legacy group list
+const aggregatedAccept = "application/json;g=apidiscovery.k8s.io;v=v2;as=APIGroupDiscoveryList," +
+	"application/json;g=apidiscovery.k8s.io;v=v2beta1;as=APIGroupDiscoveryList,application/json"
+
+// Kinds holds the kinds an endpoint publishes for each group version
+type Kinds map[schema.GroupVersion]sets.String
+
+// Has reports whether kind is published in its group version
+func (k Kinds) Has(gvk schema.GroupVersionKind) bool {
+	return k[gvk.GroupVersion()].Has(gvk.Kind)
+}
+
+// Client fetches the discovery and OpenAPI documents of an API server
+type Client struct {
+	rest rest.Interface
+}
+
+// NewClient returns a Client issuing its requests through restClient, such as the
+// RESTClient of a discovery client
+func NewClient(restClient rest.Interface) *Client {
+	return &Client{rest: restClient}
+}
+
+// Kinds returns the kinds endpoint publishes for each of gvs. Group versions that are
+// not published at all have no kinds. ErrUnsupported is returned when the API server
+// does not serve the endpoint.
+func (c *Client) Kinds(ctx context.Context, endpoint examplev1alpha1.DiscoveryEndpoint,
+	gvs []schema.GroupVersion) (Kinds, error) {
+	switch endpoint {
+	case examplev1alpha1.DiscoveryGroupVersion:
+		return c.groupVersionKinds(ctx, gvs)
+	case examplev1alpha1.DiscoveryAggregated:
+		return c.aggregatedKinds(ctx, gvs)
+	case examplev1alpha1.DiscoveryOpenAPIV2:
+		return c.openAPIV2Kinds(ctx, gvs)
+	case examplev1alpha1.DiscoveryOpenAPIV3:
+		return c.openAPIV3Kinds(ctx, gvs)
+	default:
+		return nil, fmt.Errorf("unknown discovery endpoint %q", endpoint)
+	}
+}
+
+// get returns the body of the document at path, or nil when it does not exist
+func (c *Client) get(ctx context.Context, path, accept string) ([]byte, error) {
+	raw, err := c.rest.Get().AbsPath(path).SetHeader("Accept", accept).Do(ctx).Raw()
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// groupVersionKinds reads the discovery document of every group version
+func (c *Client) groupVersionKinds(ctx context.Context, gvs []schema.GroupVersion) (Kinds, error) {
+	kinds := emptyKinds(gvs)
+	for _, gv := range gvs {
+		raw, err := c.get(ctx, "/apis/"+gv.String(), "application/json")
+		if err != nil {
+			return nil, err
+		}
+		if raw == nil {
+			continue
+		}
+
+		resources := &metav1.APIResourceList{}
+		if err := json.Unmarshal(raw, resources); err != nil {
+			return nil, fmt.Errorf("decoding discovery of %s: %w", gv, err)
+		}
+		for _, resource := range resources.APIResources {
+			// Subresources such as status repeat the kind of their resource
+			if !strings.Contains(resource.Name, "/") {
+				kinds[gv].Insert(resource.Kind)
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// aggregatedDiscovery is the part of an APIGroupDiscoveryList that names the published kinds
+type aggregatedDiscovery struct {
+	Kind  string `json:"kind"`
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Versions []struct {
+			Version   string `json:"version"`
+			Resources []struct {
+				ResponseKind struct {
+					Kind string `json:"kind"`
+				} `json:"responseKind"`
+			} `json:"resources"`
+		} `json:"versions"`
+	} `json:"items"`
+}
+
+// aggregatedKinds reads the aggregated discovery document, which lists every group at once
+func (c *Client) aggregatedKinds(ctx context.Context, gvs []schema.GroupVersion) (Kinds, error) {
+	raw, err := c.get(ctx, "/apis", aggregatedAccept)
+	if err != nil {
+		return nil, err
+	}
+	document := &aggregatedDiscovery{}
+	if raw != nil {
+		if err := json.Unmarshal(raw, document); err != nil {
+			return nil, fmt.Errorf("decoding aggregated discovery: %w", err)
+		}
+	}
+	// Older API servers ignore the Accept header and answer with a plain APIGroupList
+	if document.Kind != "APIGroupDiscoveryList" {
+		return nil, ErrUnsupported
+	}
+
+	kinds := emptyKinds(gvs)
+	for _, group := range document.Items {
+		for _, version := range group.Versions {
+			published, ok := kinds[schema.GroupVersion{Group: group.Metadata.Name, Version: version.Version}]
+			if !ok {
+				continue
+			}
+			for _, resource := range version.Resources {
+				published.Insert(resource.ResponseKind.Kind)
+			}
+		}
+	}
+	return kinds, nil
+}
+
+// openAPIDocument is the part of an OpenAPI v2 or v3 document that names the published kinds
+type openAPIDocument struct {
+	Definitions map[string]json.RawMessage `json:"definitions"`
+	Components  struct {
+		Schemas map[string]json.RawMessage `json:"schemas"`
+	} `json:"components"`
+}
+
+// openAPIV2Kinds reads the OpenAPI v2 document, which holds the definitions of every kind
+func (c *Client) openAPIV2Kinds(ctx context.Context, gvs []schema.GroupVersion) (Kinds, error) {
+	raw, err := c.get(ctx, "/openapi/v2", "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, ErrUnsupported
+	}
+	document := &openAPIDocument{}
+	if err := json.Unmarshal(raw, document); err != nil {
+		return nil, fmt.Errorf("decoding OpenAPI v2: %w", err)
+	}
+
+	kinds := emptyKinds(gvs)
+	for name := range document.Definitions {
+		addDefinition(kinds, name)
+	}
+	return kinds, nil
+}
+
+// openAPIV3Kinds reads the OpenAPI v3 document of every group version
+func (c *Client) openAPIV3Kinds(ctx context.Context, gvs []schema.GroupVersion) (Kinds, error) {
+	kinds := emptyKinds(gvs)
+	for _, gv := range gvs {
+		raw, err := c.get(ctx, "/openapi/v3/apis/"+gv.String(), "application/json")
+		if err != nil {
+			return nil, err
+		}
+		if raw == nil {
+			// A group version that is not published yet, unless OpenAPI v3 is not served at all
+			root, err := c.get(ctx, "/openapi/v3", "application/json")
+			if err != nil {
+				return nil, err
+			}
+			if root == nil {
+				return nil, ErrUnsupported
+			}
+			continue
+		}
+
+		document := &openAPIDocument{}
+		if err := json.Unmarshal(raw, document); err != nil {
+			return nil, fmt.Errorf("decoding OpenAPI v3 of %s: %w", gv, err)
+		}
+		for name := range document.Components.Schemas {
+			addDefinition(kinds, name)
+		}
+	}
+	return kinds, nil
+}
+
+// Descriptions returns the description of the schema of every kind the OpenAPI v3 document of gv
+// publishes, by kind. A group version that is not published has no kinds. ErrUnsupported is
+// returned when the API server does not serve OpenAPI v3.
+func (c *Client) Descriptions(ctx context.Context, gv schema.GroupVersion) (map[string]string, error) {
+	raw, err := c.get(ctx, "/openapi/v3/apis/"+gv.String(), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	descriptions := map[string]string{}
+	if raw == nil {
+		root, err := c.get(ctx, "/openapi/v3", "application/json")
+		if err != nil {
+			return nil, err
+		}
+		if root == nil {
+			return nil, ErrUnsupported
+		}
+		return descriptions, nil
+	}
+
+	document := &openAPIDocument{}
+	if err := json.Unmarshal(raw, document); err != nil {
+		return nil, fmt.Errorf("decoding OpenAPI v3 of %s: %w", gv, err)
+	}
+	kinds := emptyKinds([]schema.GroupVersion{gv})
+	for name, definition := range document.Components.Schemas {
+		addDefinition(kinds, name)
+		kind := name[strings.LastIndex(name, ".")+1:]
+		if !kinds[gv].Has(kind) {
+			continue
+		}
+		var described struct {
+			Description string `json:"description"`
+		}
+		if err := json.Unmarshal(definition, &described); err != nil {
+			return nil, fmt.Errorf("decoding OpenAPI v3 schema %s: %w", name, err)
+		}
+		descriptions[kind] = described.Description
+	}
+	return descriptions, nil
+}
+
+// emptyKinds returns Kinds with an empty set for each of gvs
+func emptyKinds(gvs []schema.GroupVersion) Kinds {
+	kinds := make(Kinds, len(gvs))
+	for _, gv := range gvs {
+		kinds[gv] = sets.NewString()
+	}
+	return kinds
+}
+
+// addDefinition adds the kind of an OpenAPI definition name to kinds when its group version is tracked.
+// Definitions of custom resources are named <reversed group>.<version>.<kind>, for example
+// io.anirudh.example.v1alpha1.ComplexRecontest1.
+func addDefinition(kinds Kinds, name string) {
+	parts := strings.Split(name, ".")
+	if len(parts) < 3 {
+		return
+	}
+	group := parts[:len(parts)-2]
+	for i, j := 0, len(group)-1; i < j; i, j = i+1, j-1 {
+		group[i], group[j] = group[j], group[i]
+	}
+	gv := schema.GroupVersion{Group: strings.Join(group, "."), Version: parts[len(parts)-2]}
+	if published, ok := kinds[gv]; ok {
+		published.Insert(parts[len(parts)-1])
+	}
+}
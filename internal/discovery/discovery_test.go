@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientdiscovery "k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// documents are served by the fake API server, by path
+var documents = map[string]string{
+	"/apis/example.anirudh.io/v1alpha1": `{"kind":"APIResourceList","groupVersion":"example.anirudh.io/v1alpha1",
+		"resources":[{"name":"complexrecontests1","kind":"ComplexRecontest1"},
+		{"name":"complexrecontests1/status","kind":"ComplexRecontest1"}]}`,
+	"/openapi/v2": `{"definitions":{"io.anirudh.example.v1alpha1.ComplexRecontest1":{},
+		"io.k8s.api.core.v1.Pod":{}}}`,
+	"/openapi/v3": `{"paths":{}}`,
+	"/openapi/v3/apis/example.anirudh.io/v1alpha1": `{"components":{"schemas":{
+		"io.anirudh.example.v1alpha1.ComplexRecontest2":{"description":"Schema revision 2"}}}}`,
+}
+
+func TestKindsReadsEveryEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/apis" {
+			if strings.Contains(req.Header.Get("Accept"), "as=APIGroupDiscoveryList") {
+				_, _ = w.Write([]byte(`{"kind":"APIGroupDiscoveryList","items":[{"metadata":{"name":"example.anirudh.io"},
+					"versions":[{"version":"v1alpha1","resources":[{"responseKind":{"kind":"ComplexRecontest3"}}]}]}]}`))
+				return
+			}
+		}
+		document, ok := documents[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(document))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := clientdiscovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(discoveryClient.RESTClient())
+
+	gv := schema.GroupVersion{Group: "example.anirudh.io", Version: "v1alpha1"}
+	missing := schema.GroupVersion{Group: "example.anirudh.io", Version: "v1alpha2"}
+	for endpoint, kind := range map[examplev1alpha1.DiscoveryEndpoint]string{
+		examplev1alpha1.DiscoveryGroupVersion: "ComplexRecontest1",
+		examplev1alpha1.DiscoveryOpenAPIV2:    "ComplexRecontest1",
+		examplev1alpha1.DiscoveryOpenAPIV3:    "ComplexRecontest2",
+		examplev1alpha1.DiscoveryAggregated:   "ComplexRecontest3",
+	} {
+		kinds, err := client.Kinds(context.Background(), endpoint, []schema.GroupVersion{gv, missing})
+		if err != nil {
+			t.Fatalf("%s: %v", endpoint, err)
+		}
+		if !kinds.Has(gv.WithKind(kind)) || kinds[gv].Len() != 1 || kinds[missing].Len() != 0 {
+			t.Errorf("%s: expected only %s, got %v", endpoint, kind, kinds)
+		}
+	}
+
+	descriptions, err := client.Descriptions(context.Background(), gv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(descriptions) != 1 || descriptions["ComplexRecontest2"] != "Schema revision 2" {
+		t.Errorf("expected the description of ComplexRecontest2, got %v", descriptions)
+	}
+}
+
+func TestKindsReportsUnsupportedEndpoints(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/apis" {
+			// API servers before 1.26 answer with the legacy group list whatever is accepted
+			_, _ = w.Write([]byte(`{"kind":"APIGroupList","groups":[]}`))
+			return
+		}
+		http.NotFound(w, req)
+	}))
+	defer server.Close()
+
+	discoveryClient, err := clientdiscovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(discoveryClient.RESTClient())
+
+	gvs := []schema.GroupVersion{{Group: "example.anirudh.io", Version: "v1alpha1"}}
+	for _, endpoint := range []examplev1alpha1.DiscoveryEndpoint{
+		examplev1alpha1.DiscoveryAggregated, examplev1alpha1.DiscoveryOpenAPIV2, examplev1alpha1.DiscoveryOpenAPIV3,
+	} {
+		if _, err := client.Kinds(context.Background(), endpoint, gvs); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%s: expected ErrUnsupported, got %v", endpoint, err)
+		}
+	}
+}
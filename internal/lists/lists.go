@@ -0,0 +1,120 @@
+// Package lists issues paginated LIST requests against the API server and measures their
+// latency and response size. Every CRD adds to what clients list on startup and relist,
+// so read latency is as much a cost of many CRDs as their creation is.
+package lists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// CRDsPath is the collection of the CustomResourceDefinitions of the cluster
+const CRDsPath = "/apis/apiextensions.k8s.io/v1/customresourcedefinitions"
+
+// Options are the parameters of a list
+type Options struct {
+	// Limit is the number of items per page, zero for the whole collection at once
+	Limit int64
+	// ResourceVersion is the resourceVersion semantics of the first page
+	ResourceVersion examplev1alpha1.ListResourceVersion
+	// LabelSelector restricts the listed objects
+	LabelSelector string
+}
+
+// Page is the result of one list request
+type Page struct {
+	// Latency is the time the request took, until its whole response was read
+	Latency time.Duration
+	// Bytes is the size of the response
+	Bytes int
+	// Items is the number of objects in the page
+	Items int
+}
+
+// Client lists collections of the API server
+type Client struct {
+	rest rest.Interface
+}
+
+// NewClient returns a Client issuing its requests through restClient, such as the
+// RESTClient of a discovery client
+func NewClient(restClient rest.Interface) *Client {
+	return &Client{rest: restClient}
+}
+
+// List reads every page of the collection at path and reports each one to observe. The first
+// page reads at resourceVersion as opts asks, resourceVersion being the resource version of the
+// previous list of the collection, if any. It returns the resource version of the list.
+func (c *Client) List(ctx context.Context, path, resourceVersion string, opts Options,
+	observe func(Page)) (string, error) {
+	continueToken := ""
+	for {
+		req := c.rest.Get().AbsPath(path)
+		if opts.Limit > 0 {
+			req = req.Param("limit", strconv.FormatInt(opts.Limit, 10))
+		}
+		if opts.LabelSelector != "" {
+			req = req.Param("labelSelector", opts.LabelSelector)
+		}
+		if continueToken != "" {
+			// Later pages are read at the resource version of the first one
+			req = req.Param("continue", continueToken)
+		} else {
+			req = withResourceVersion(req, resourceVersion, opts.ResourceVersion)
+		}
+
+		start := time.Now()
+		body, err := req.DoRaw(ctx)
+		latency := time.Since(start)
+		if err != nil {
+			return "", err
+		}
+
+		var list struct {
+			Metadata metav1.ListMeta   `json:"metadata"`
+			Items    []json.RawMessage `json:"items"`
+		}
+		if err := json.Unmarshal(body, &list); err != nil {
+			return "", fmt.Errorf("decoding list of %s: %w", path, err)
+		}
+		observe(Page{Latency: latency, Bytes: len(body), Items: len(list.Items)})
+
+		if continueToken == "" {
+			resourceVersion = list.Metadata.ResourceVersion
+		}
+		if continueToken = list.Metadata.Continue; continueToken == "" {
+			return resourceVersion, nil
+		}
+	}
+}
+
+// withResourceVersion sets the resourceVersion of the first page of a list following previous,
+// the resource version of the previous list
+func withResourceVersion(req *rest.Request, previous string,
+	semantics examplev1alpha1.ListResourceVersion) *rest.Request {
+	match := metav1.ResourceVersionMatchExact
+	switch semantics {
+	case examplev1alpha1.ListNotOlderThan:
+		match = metav1.ResourceVersionMatchNotOlderThan
+		if previous == "" {
+			// Anything the watch cache has
+			previous = "0"
+		}
+	case examplev1alpha1.ListExact:
+		if previous == "" {
+			// Nothing to match exactly before the first list
+			return req
+		}
+	default:
+		return req
+	}
+	return req.Param("resourceVersion", previous).Param("resourceVersionMatch", string(match))
+}
@@ -0,0 +1,79 @@
+package lists
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	clientdiscovery "k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func TestListReadsEveryPage(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries = append(queries, req.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL.Query().Get("continue") == "" {
+			_, _ = w.Write([]byte(`{"metadata":{"resourceVersion":"42","continue":"next"},"items":[{},{}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"metadata":{"resourceVersion":"42"},"items":[{}]}`))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := clientdiscovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(discoveryClient.RESTClient())
+
+	var pages []Page
+	resourceVersion, err := client.List(context.Background(), CRDsPath, "7", Options{
+		Limit:           2,
+		ResourceVersion: examplev1alpha1.ListNotOlderThan,
+		LabelSelector:   "example.anirudh.io/run",
+	}, func(page Page) { pages = append(pages, page) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resourceVersion != "42" {
+		t.Errorf("expected the resource version of the list, got %q", resourceVersion)
+	}
+	if len(pages) != 2 || pages[0].Items != 2 || pages[1].Items != 1 || pages[0].Bytes == 0 {
+		t.Fatalf("expected pages of 2 and 1 items, got %+v", pages)
+	}
+	first, second := queries[0], queries[1]
+	if first.Get("limit") != "2" || first.Get("resourceVersion") != "7" ||
+		first.Get("resourceVersionMatch") != "NotOlderThan" || first.Get("labelSelector") == "" {
+		t.Errorf("unexpected parameters of the first page: %v", first)
+	}
+	if second.Get("continue") != "next" || second.Has("resourceVersion") || second.Get("labelSelector") == "" {
+		t.Errorf("unexpected parameters of the second page: %v", second)
+	}
+}
+
+func TestResourceVersionSemantics(t *testing.T) {
+	for _, tc := range []struct {
+		semantics examplev1alpha1.ListResourceVersion
+		previous  string
+		want      url.Values
+	}{
+		{examplev1alpha1.ListResourceVersionNone, "7", url.Values{}},
+		{examplev1alpha1.ListNotOlderThan, "", url.Values{"resourceVersion": {"0"},
+			"resourceVersionMatch": {"NotOlderThan"}}},
+		{examplev1alpha1.ListExact, "", url.Values{}},
+		{examplev1alpha1.ListExact, "7", url.Values{"resourceVersion": {"7"}, "resourceVersionMatch": {"Exact"}}},
+	} {
+		req := withResourceVersion(rest.NewRequestWithClient(&url.URL{}, "", rest.ClientContentConfig{}, nil),
+			tc.previous, tc.semantics)
+		if got := req.URL().Query(); got.Encode() != tc.want.Encode() {
+			t.Errorf("%s after %q: expected %v, got %v", tc.semantics, tc.previous, tc.want, got)
+		}
+	}
+}
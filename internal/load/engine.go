@@ -0,0 +1,224 @@
+// Package load issues API requests for a ReconTest run from a bounded pool of
+// workers at a controlled, optionally ramping, rate.
+package load
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RampProfile is the shape of the rate increase during the ramp-up
+type RampProfile string
+
+const (
+	// RampNone issues requests at the target rate from the start
+	RampNone RampProfile = ""
+	// RampLinear raises the rate linearly from zero to the target rate
+	RampLinear RampProfile = "Linear"
+	// RampStep raises the rate to the target rate in equal steps
+	RampStep RampProfile = "Step"
+)
+
+// maxThrottledAttempts bounds how often a throttled request is retried without a RetryFunc
+const maxThrottledAttempts = 5
+
+// defaultThrottleDelay is used when a throttled response carries no Retry-After
+const defaultThrottleDelay = time.Second
+
+// RetryFunc decides whether a call that failed with err on its attempt-th attempt is
+// retried, and how long to wait before retrying it
+type RetryFunc func(err error, attempt int) (time.Duration, bool)
+
+// Config controls the concurrency and rate of an Engine
+type Config struct {
+	// Concurrency is the number of requests in flight at once
+	Concurrency int
+	// QPS is the target number of requests per second; zero means unlimited
+	QPS float64
+	// Burst is the number of requests that may be issued at once above QPS
+	Burst int
+	// Ramp is the shape of the rate increase
+	Ramp RampProfile
+	// RampDuration is how long the rate takes to reach QPS
+	RampDuration time.Duration
+	// RampSteps is the number of steps of a RampStep ramp
+	RampSteps int
+	// Retry decides which failed calls are retried. Only throttled calls are retried
+	// when it is nil.
+	Retry RetryFunc
+}
+
+// Engine runs requests with bounded concurrency behind a token bucket
+type Engine struct {
+	cfg     Config
+	limiter *rate.Limiter
+	start   time.Time
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// NewEngine returns an Engine for cfg
+func NewEngine(cfg Config) *Engine {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Burst < 1 {
+		cfg.Burst = cfg.Concurrency
+	}
+	if cfg.RampSteps < 1 {
+		cfg.RampSteps = 1
+	}
+
+	limit := rate.Inf
+	if cfg.QPS > 0 {
+		limit = rate.Limit(cfg.QPS)
+	}
+	return &Engine{
+		cfg:     cfg,
+		limiter: rate.NewLimiter(limit, cfg.Burst),
+	}
+}
+
+// Run calls fn for every index in [0, n) from the worker pool and reports the
+// final error of each call to result, from the worker that made it. Failed calls
+// are retried as cfg.Retry decides. A retried call rejected with 429 Too Many
+// Requests pauses every worker for at least the delay the server asked for. Run
+// returns once every call is done or ctx is cancelled.
+func (e *Engine) Run(ctx context.Context, n int, fn func(ctx context.Context, i int) error,
+	result func(i int, err error)) {
+	e.start = time.Now()
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < e.cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				result(i, e.do(ctx, i, fn))
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// do makes a single call of fn, retrying it while it fails with errors that are retried
+func (e *Engine) do(ctx context.Context, i int, fn func(ctx context.Context, i int) error) error {
+	for attempt := 1; ; attempt++ {
+		if err := e.wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn(ctx, i)
+		if err == nil {
+			return nil
+		}
+		backoff, retry := e.retry(err, attempt)
+		if !retry {
+			return err
+		}
+
+		if !apierrors.IsTooManyRequests(err) {
+			if err := sleep(ctx, backoff); err != nil {
+				return err
+			}
+			continue
+		}
+		// The server is overloaded, slow every worker down rather than this one
+		delay := defaultThrottleDelay
+		if seconds, ok := apierrors.SuggestsClientDelay(err); ok && seconds > 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		if backoff > delay {
+			delay = backoff
+		}
+		e.pause(delay)
+	}
+}
+
+// retry decides whether a call that failed with err on its attempt-th attempt is retried
+func (e *Engine) retry(err error, attempt int) (time.Duration, bool) {
+	if e.cfg.Retry != nil {
+		return e.cfg.Retry(err, attempt)
+	}
+	return 0, apierrors.IsTooManyRequests(err) && attempt < maxThrottledAttempts
+}
+
+// sleep waits for d unless ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// wait blocks until the engine is not paused and the token bucket allows another request
+func (e *Engine) wait(ctx context.Context) error {
+	e.mu.Lock()
+	pause := time.Until(e.pausedUntil)
+	e.mu.Unlock()
+
+	if err := sleep(ctx, pause); err != nil {
+		return err
+	}
+
+	if e.cfg.QPS > 0 {
+		e.limiter.SetLimit(e.currentLimit(time.Since(e.start)))
+	}
+	return e.limiter.Wait(ctx)
+}
+
+// pause stops every worker from issuing requests for d
+func (e *Engine) pause(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(e.pausedUntil) {
+		e.pausedUntil = until
+	}
+}
+
+// currentLimit returns the rate allowed by the ramp profile after elapsed
+func (e *Engine) currentLimit(elapsed time.Duration) rate.Limit {
+	if e.cfg.Ramp == RampNone || e.cfg.RampDuration <= 0 || elapsed >= e.cfg.RampDuration {
+		return rate.Limit(e.cfg.QPS)
+	}
+
+	progress := float64(elapsed) / float64(e.cfg.RampDuration)
+	if e.cfg.Ramp == RampStep {
+		// Start at the first step rather than at zero
+		step := int(progress*float64(e.cfg.RampSteps)) + 1
+		progress = float64(step) / float64(e.cfg.RampSteps)
+	}
+
+	// Never drop below one request per second so a ramp cannot stall
+	limit := e.cfg.QPS * progress
+	if limit < 1 {
+		limit = math.Min(1, e.cfg.QPS)
+	}
+	return rate.Limit(limit)
+}
@@ -0,0 +1,85 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestCurrentLimitRamps(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		cfg     Config
+		elapsed time.Duration
+		want    rate.Limit
+	}{
+		{"no ramp", Config{QPS: 100}, 0, 100},
+		{"linear halfway", Config{QPS: 100, Ramp: RampLinear, RampDuration: 10 * time.Second}, 5 * time.Second, 50},
+		{"linear floor", Config{QPS: 100, Ramp: RampLinear, RampDuration: 10 * time.Second}, 0, 1},
+		{"linear done", Config{QPS: 100, Ramp: RampLinear, RampDuration: 10 * time.Second}, time.Minute, 100},
+		{"first step", Config{QPS: 100, Ramp: RampStep, RampDuration: 8 * time.Second, RampSteps: 4}, 0, 25},
+		{"third step", Config{QPS: 100, Ramp: RampStep, RampDuration: 8 * time.Second, RampSteps: 4}, 5 * time.Second, 75},
+	} {
+		if got := NewEngine(tc.cfg).currentLimit(tc.elapsed); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestRunReportsEveryCall(t *testing.T) {
+	engine := NewEngine(Config{Concurrency: 4})
+
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	engine.Run(context.Background(), 50, func(context.Context, int) error {
+		return nil
+	}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[i] = true
+	})
+
+	if len(seen) != 50 {
+		t.Fatalf("expected 50 results, got %d", len(seen))
+	}
+}
+
+func TestRunRetriesAsDecided(t *testing.T) {
+	failure := errors.New("conflict")
+	engine := NewEngine(Config{Concurrency: 2, Retry: func(err error, attempt int) (time.Duration, bool) {
+		return time.Millisecond, attempt < 3
+	}})
+
+	var mu sync.Mutex
+	attempts := map[int]int{}
+	results := map[int]error{}
+	engine.Run(context.Background(), 4, func(_ context.Context, i int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[i]++
+		// Even calls succeed on their second attempt, odd calls never do
+		if i%2 == 0 && attempts[i] == 2 {
+			return nil
+		}
+		return failure
+	}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = err
+	})
+
+	for i := 0; i < 4; i++ {
+		wantAttempts, wantErr := 2, error(nil)
+		if i%2 == 1 {
+			wantAttempts, wantErr = 3, failure
+		}
+		if attempts[i] != wantAttempts || results[i] != wantErr {
+			t.Errorf("call %d: expected %d attempts and %v, got %d and %v",
+				i, wantAttempts, wantErr, attempts[i], results[i])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// Names of the files a report is written to
+const (
+	JSONFile       = "report.json"
+	CRDsFile       = "crds.csv"
+	ThroughputFile = "throughput.csv"
+	JUnitFile      = "junit.xml"
+)
+
+// Files encodes the report in every format of formats and returns the content of each file by name
+func (r *Report) Files(formats []examplev1alpha1.ReportFormat) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, format := range formats {
+		var err error
+		switch format {
+		case examplev1alpha1.ReportJSON:
+			files[JSONFile], err = r.JSON()
+		case examplev1alpha1.ReportCSV:
+			if files[CRDsFile], err = r.CRDsCSV(); err == nil {
+				files[ThroughputFile], err = r.ThroughputCSV()
+			}
+		case examplev1alpha1.ReportJUnit:
+			files[JUnitFile], err = r.JUnit()
+		default:
+			err = fmt.Errorf("unknown report format %q", format)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// JSON encodes the whole report
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// Read decodes a report encoded by JSON, gzipped or not
+func Read(r io.Reader) (*Report, error) {
+	buffered := bufio.NewReader(r)
+	// Every gzip stream starts with the same two magic bytes
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = buffered
+	}
+
+	decoded := &Report{}
+	if err := json.NewDecoder(r).Decode(decoded); err != nil {
+		return nil, fmt.Errorf("decoding report: %w", err)
+	}
+	return decoded, nil
+}
+
+// CRDsCSV encodes the timings of every CRD, one Create call per row
+func (r *Report) CRDsCSV() ([]byte, error) {
+	rows := [][]string{{"name", "created", "createSeconds", "namesAcceptedSeconds", "establishedSeconds", "recreated"}}
+	for _, crd := range r.CRDs {
+		rows = append(rows, []string{
+			crd.Name,
+			crd.Created.UTC().Format(time.RFC3339Nano),
+			formatSeconds(&crd.CreateSeconds),
+			formatSeconds(crd.NamesAcceptedSeconds),
+			formatSeconds(crd.EstablishedSeconds),
+			strconv.FormatBool(crd.Recreated),
+		})
+	}
+	return encodeCSV(rows)
+}
+
+// ThroughputCSV encodes the throughput of the run, one bucket per row
+func (r *Report) ThroughputCSV() ([]byte, error) {
+	rows := [][]string{{"start", "created", "established", "createdPerSecond", "establishedPerSecond"}}
+	width := BucketWidth.Seconds()
+	for _, bucket := range r.Throughput {
+		rows = append(rows, []string{
+			bucket.Start.UTC().Format(time.RFC3339Nano),
+			strconv.Itoa(bucket.Created),
+			strconv.Itoa(bucket.Established),
+			strconv.FormatFloat(float64(bucket.Created)/width, 'f', -1, 64),
+			strconv.FormatFloat(float64(bucket.Established)/width, 'f', -1, 64),
+		})
+	}
+	return encodeCSV(rows)
+}
+
+// encodeCSV writes rows as CSV
+func encodeCSV(rows [][]string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatSeconds formats an optional number of seconds, leaving it empty when unset
+func formatSeconds(s *float64) string {
+	if s == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*s, 'f', -1, 64)
+}
+
+// junitSuites is the root element of a JUnit XML report
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite is the test suite of a run
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+// junitProperty describes the environment of a test suite
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// junitCase is the test case of one check
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitFailure `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure is the failure or skipped element of a test case
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// JUnit encodes the checks of the run as a test suite with one test case per check. Checks
+// that cannot be decided yet are skipped.
+func (r *Report) JUnit() ([]byte, error) {
+	name := r.Run.Namespace + "/" + r.Run.Name
+	suite := junitSuite{
+		Name:      name,
+		Tests:     len(r.Checks),
+		Failures:  r.Failed(),
+		Timestamp: r.Run.Started.UTC().Format(time.RFC3339),
+		Time:      strconv.FormatFloat(r.Run.Generated.Sub(r.Run.Started).Seconds(), 'f', 3, 64),
+		Properties: []junitProperty{
+			{Name: "operatorVersion", Value: r.Run.OperatorVersion},
+			{Name: "clusterVersion", Value: r.Run.ClusterVersion},
+			{Name: "final", Value: strconv.FormatBool(r.Run.Final)},
+		},
+	}
+	for _, check := range r.Checks {
+		testCase := junitCase{Name: check.Name, ClassName: "recontest." + name, SystemOut: check.Message}
+		switch {
+		case check.Skipped:
+			testCase.Skipped = &junitFailure{Message: check.Message}
+			suite.Skipped++
+		case !check.Passed:
+			testCase.Failure = &junitFailure{Message: check.Message}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	raw, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), raw...), nil
+}
@@ -0,0 +1,184 @@
+// Package report builds the report of a ReconTest run and encodes it as JSON, CSV and
+// JUnit XML, so the results of a run can be archived and compared across operator and
+// cluster versions.
+package report
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// BucketWidth is the width of the time buckets the throughput of a run is counted in
+const BucketWidth = 10 * time.Second
+
+// Report holds everything known about a run
+type Report struct {
+	Run    Run                             `json:"run"`
+	Status examplev1alpha1.ReconTestStatus `json:"status"`
+	CRDs   []CRD                           `json:"crds"`
+	// Requests is the number of API requests of the run, Errors those that failed
+	Requests int64        `json:"requests"`
+	Errors   []ErrorCount `json:"errors"`
+	// Throughput counts the CRDs created and established in every BucketWidth of the run
+	Throughput []Bucket `json:"throughput"`
+	Checks     []Check  `json:"checks"`
+}
+
+// Run identifies a run and the versions and configuration it ran with
+type Run struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	UID       types.UID `json:"uid"`
+	Started   time.Time `json:"started"`
+	Generated time.Time `json:"generated"`
+	// Final is set on the report written when the ReconTest is deleted
+	Final           bool                          `json:"final,omitempty"`
+	OperatorVersion string                        `json:"operatorVersion"`
+	ClusterVersion  string                        `json:"clusterVersion"`
+	Spec            examplev1alpha1.ReconTestSpec `json:"spec"`
+}
+
+// CRD holds the timings of one Create call of a generated CRD. CRDs recreated after
+// churn have one entry per Create call.
+type CRD struct {
+	Name string `json:"name"`
+	// Created is the time the Create call was issued
+	Created       time.Time `json:"created"`
+	CreateSeconds float64   `json:"createSeconds"`
+	// NamesAcceptedSeconds and EstablishedSeconds are measured from Created, and unset
+	// until the condition turned True
+	NamesAcceptedSeconds *float64 `json:"namesAcceptedSeconds,omitempty"`
+	EstablishedSeconds   *float64 `json:"establishedSeconds,omitempty"`
+	Recreated            bool     `json:"recreated,omitempty"`
+}
+
+// ErrorCount is the number of API errors of an operation with the same status reason
+type ErrorCount struct {
+	Operation string `json:"operation"`
+	Reason    string `json:"reason"`
+	Count     int32  `json:"count"`
+}
+
+// Bucket counts the CRDs whose Create call returned, and the CRDs that were established,
+// within BucketWidth from Start
+type Bucket struct {
+	Start       time.Time `json:"start"`
+	Created     int       `json:"created"`
+	Established int       `json:"established"`
+}
+
+// Check is the outcome of checking the run against one of its expectations
+type Check struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Skipped is set on checks that cannot be decided yet
+	Skipped bool   `json:"skipped,omitempty"`
+	Message string `json:"message"`
+}
+
+// New returns the report of a run, computing its throughput and checks
+func New(run Run, status examplev1alpha1.ReconTestStatus, crds []CRD, requests int64, errors []ErrorCount) *Report {
+	return &Report{
+		Run:        run,
+		Status:     status,
+		CRDs:       crds,
+		Requests:   requests,
+		Errors:     errors,
+		Throughput: Throughput(crds, BucketWidth),
+		Checks:     Checks(status),
+	}
+}
+
+// Failed returns the number of checks that failed
+func (r *Report) Failed() int {
+	failed := 0
+	for _, check := range r.Checks {
+		if !check.Passed && !check.Skipped {
+			failed++
+		}
+	}
+	return failed
+}
+
+// OperatorVersion returns the version of the module the operator was built from
+func OperatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	return info.Main.Version
+}
+
+// Throughput counts crds into buckets of width, from the first Create call to the last event
+func Throughput(crds []CRD, width time.Duration) []Bucket {
+	if len(crds) == 0 {
+		return nil
+	}
+	start := crds[0].Created
+	for _, crd := range crds {
+		if crd.Created.Before(start) {
+			start = crd.Created
+		}
+	}
+
+	var buckets []Bucket
+	bucket := func(at time.Time) *Bucket {
+		index := int(at.Sub(start) / width)
+		for len(buckets) <= index {
+			buckets = append(buckets, Bucket{Start: start.Add(time.Duration(len(buckets)) * width)})
+		}
+		return &buckets[index]
+	}
+	for _, crd := range crds {
+		bucket(crd.Created.Add(seconds(crd.CreateSeconds))).Created++
+		if crd.EstablishedSeconds != nil {
+			bucket(crd.Created.Add(seconds(*crd.EstablishedSeconds))).Established++
+		}
+	}
+	return buckets
+}
+
+// BaselineCheck is the name of the check that a run did not regress against its baseline
+const BaselineCheck = "No regressions against baseline"
+
+// Checks returns the checks of a run: whether every desired CRD is present, followed by
+// the verdict of every assertion and the comparison with the baseline. Assertions that are
+// still pending, and baselines that cannot be read, are skipped.
+func Checks(status examplev1alpha1.ReconTestStatus) []Check {
+	present := status.Created + status.Existing
+	checks := []Check{{
+		Name:    "CRDs present",
+		Passed:  present >= status.Desired,
+		Message: fmt.Sprintf("%d of %d CRDs present", present, status.Desired),
+	}}
+	for _, assertion := range status.Assertions {
+		checks = append(checks, Check{
+			Name:    assertion.Name,
+			Passed:  assertion.State == examplev1alpha1.AssertionPassed,
+			Skipped: assertion.State == examplev1alpha1.AssertionPending,
+			Message: assertion.Message,
+		})
+	}
+	if baseline := status.Baseline; baseline != nil {
+		check := Check{Name: BaselineCheck, Passed: baseline.Error == "" && baseline.Regressions == 0}
+		if baseline.Error != "" {
+			check.Skipped = true
+			check.Message = baseline.Error
+		} else {
+			check.Message = fmt.Sprintf("%d of %d metrics regressed against %s",
+				baseline.Regressions, len(baseline.Metrics), baseline.Run)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// seconds converts a number of seconds into a duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
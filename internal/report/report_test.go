@@ -0,0 +1,110 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func testReport(assertions []examplev1alpha1.AssertionStatus) *Report {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	crds := []CRD{
+		{Name: "a", Created: start, CreateSeconds: 0.5, NamesAcceptedSeconds: float(1), EstablishedSeconds: float(2)},
+		{Name: "b", Created: start.Add(5 * time.Second), CreateSeconds: 0.5, EstablishedSeconds: float(6)},
+		{Name: "c", Created: start.Add(12 * time.Second), CreateSeconds: 1},
+	}
+	status := examplev1alpha1.ReconTestStatus{Desired: 3, Created: 3, Assertions: assertions}
+	errors := []ErrorCount{{Operation: "CreateCRD", Reason: "TooManyRequests", Count: 2}}
+	return New(Run{Name: "run", Namespace: "default", Started: start, Generated: start.Add(time.Minute)},
+		status, crds, 5, errors)
+}
+
+func TestThroughputCountsEventsInBuckets(t *testing.T) {
+	throughput := testReport(nil).Throughput
+	// a created at 0.5s and established at 2s, b at 5.5s and 11s, c created at 13s
+	want := []Bucket{{Created: 2, Established: 1}, {Created: 1, Established: 1}}
+	if len(throughput) != len(want) {
+		t.Fatalf("expected %d buckets, got %+v", len(want), throughput)
+	}
+	for i := range want {
+		if throughput[i].Created != want[i].Created || throughput[i].Established != want[i].Established {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], throughput[i])
+		}
+	}
+}
+
+func TestChecksFollowAssertions(t *testing.T) {
+	r := testReport([]examplev1alpha1.AssertionStatus{
+		{Name: "EstablishedLatencyP99", State: examplev1alpha1.AssertionPassed},
+		{Name: "ErrorRate", State: examplev1alpha1.AssertionFailed},
+		{Name: "AllEstablished", State: examplev1alpha1.AssertionPending},
+	})
+
+	want := []Check{
+		{Name: "CRDs present", Passed: true},
+		{Name: "EstablishedLatencyP99", Passed: true},
+		{Name: "ErrorRate"},
+		{Name: "AllEstablished", Skipped: true},
+	}
+	if len(r.Checks) != len(want) {
+		t.Fatalf("expected %d checks, got %+v", len(want), r.Checks)
+	}
+	for i := range want {
+		if r.Checks[i].Name != want[i].Name || r.Checks[i].Passed != want[i].Passed ||
+			r.Checks[i].Skipped != want[i].Skipped {
+			t.Errorf("check %d: expected %+v, got %+v", i, want[i], r.Checks[i])
+		}
+	}
+	if r.Failed() != 1 {
+		t.Errorf("expected 1 failed check, got %d", r.Failed())
+	}
+}
+
+func TestFilesEncodeEveryFormat(t *testing.T) {
+	r := testReport([]examplev1alpha1.AssertionStatus{
+		{Name: "ErrorRate", State: examplev1alpha1.AssertionFailed, Message: "2 of 5 requests failed"},
+	})
+	files, err := r.Files([]examplev1alpha1.ReportFormat{
+		examplev1alpha1.ReportJSON, examplev1alpha1.ReportCSV, examplev1alpha1.ReportJUnit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &Report{}
+	if err := json.Unmarshal(files[JSONFile], decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.CRDs) != 3 || decoded.CRDs[2].EstablishedSeconds != nil {
+		t.Errorf("unexpected CRDs in JSON report: %+v", decoded.CRDs)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(string(files[CRDsFile]))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[1][4] != "2" || rows[3][4] != "" {
+		t.Errorf("unexpected CRDs CSV: %v", rows)
+	}
+	if _, ok := files[ThroughputFile]; !ok {
+		t.Errorf("expected %s", ThroughputFile)
+	}
+
+	suites := &junitSuites{}
+	if err := xml.Unmarshal(files[JUnitFile], suites); err != nil {
+		t.Fatal(err)
+	}
+	suite := suites.Suites[0]
+	if suite.Tests != 2 || suite.Failures != 1 || suite.Cases[1].Failure == nil {
+		t.Errorf("unexpected JUnit suite: %+v", suite)
+	}
+}
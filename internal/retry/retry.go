@@ -0,0 +1,166 @@
+// Package retry classifies the errors returned by the API server and decides, by error
+// class, whether a failed request is retried, given up or aborts its run.
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// Defaults of the settings of a retry policy
+const (
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+	defaultRequeueAfter   = 30 * time.Second
+)
+
+// Classes lists every error class
+var Classes = []examplev1alpha1.ErrorClass{
+	examplev1alpha1.ErrorConflict,
+	examplev1alpha1.ErrorThrottled,
+	examplev1alpha1.ErrorTimeout,
+	examplev1alpha1.ErrorInvalid,
+	examplev1alpha1.ErrorForbidden,
+	examplev1alpha1.ErrorServerError,
+	examplev1alpha1.ErrorRequestTooLarge,
+	examplev1alpha1.ErrorOther,
+}
+
+// Classify returns the class of err
+func Classify(err error) examplev1alpha1.ErrorClass {
+	switch {
+	case apierrors.IsConflict(err):
+		return examplev1alpha1.ErrorConflict
+	case apierrors.IsTooManyRequests(err):
+		return examplev1alpha1.ErrorThrottled
+	// etcd rejects large objects with an internal error, test for it before server errors
+	case apierrors.IsRequestEntityTooLargeError(err) || strings.Contains(err.Error(), "request is too large"):
+		return examplev1alpha1.ErrorRequestTooLarge
+	case apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || errors.Is(err, context.DeadlineExceeded) ||
+		isNetTimeout(err):
+		return examplev1alpha1.ErrorTimeout
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		return examplev1alpha1.ErrorInvalid
+	case apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err):
+		return examplev1alpha1.ErrorForbidden
+	case apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err) || statusCode(err) >= http.StatusInternalServerError:
+		return examplev1alpha1.ErrorServerError
+	default:
+		return examplev1alpha1.ErrorOther
+	}
+}
+
+// isNetTimeout reports whether err is a network timeout
+func isNetTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// statusCode returns the HTTP status code of an API error, zero for other errors
+func statusCode(err error) int32 {
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return status.Status().Code
+	}
+	return 0
+}
+
+// Rule is the handling of the errors of a class
+type Rule struct {
+	Action         examplev1alpha1.RetryAction
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Backoff returns the delay after the attempt-th attempt of a request, doubling from
+// InitialBackoff up to MaxBackoff
+func (r Rule) Backoff(attempt int) time.Duration {
+	backoff := r.InitialBackoff
+	for i := 1; i < attempt && backoff < r.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.MaxBackoff {
+		backoff = r.MaxBackoff
+	}
+	return backoff
+}
+
+// Policy is the handling of every error class
+type Policy struct {
+	rules map[examplev1alpha1.ErrorClass]Rule
+	// RequeueAfter is the delay before the next pass after a pass that gave up on requests
+	RequeueAfter time.Duration
+}
+
+// NewPolicy returns the policy set in spec on top of the default policy, which retries
+// Conflict, Throttled, Timeout and ServerError errors and gives up on the others
+func NewPolicy(spec *examplev1alpha1.RetryPolicy) *Policy {
+	p := &Policy{rules: map[examplev1alpha1.ErrorClass]Rule{}, RequeueAfter: defaultRequeueAfter}
+	for _, class := range Classes {
+		rule := Rule{
+			Action:         examplev1alpha1.RetryGiveUp,
+			MaxAttempts:    defaultMaxAttempts,
+			InitialBackoff: defaultInitialBackoff,
+			MaxBackoff:     defaultMaxBackoff,
+		}
+		switch class {
+		case examplev1alpha1.ErrorConflict, examplev1alpha1.ErrorThrottled,
+			examplev1alpha1.ErrorTimeout, examplev1alpha1.ErrorServerError:
+			rule.Action = examplev1alpha1.RetryBackoff
+		}
+		p.rules[class] = rule
+	}
+	if spec == nil {
+		return p
+	}
+
+	if spec.RequeueAfter.Duration > 0 {
+		p.RequeueAfter = spec.RequeueAfter.Duration
+	}
+	for _, ruleSpec := range spec.Rules {
+		rule := p.rules[ruleSpec.Class]
+		rule.Action = ruleSpec.Action
+		if ruleSpec.MaxAttempts > 0 {
+			rule.MaxAttempts = int(ruleSpec.MaxAttempts)
+		}
+		if ruleSpec.InitialBackoff.Duration > 0 {
+			rule.InitialBackoff = ruleSpec.InitialBackoff.Duration
+		}
+		if ruleSpec.MaxBackoff.Duration > 0 {
+			rule.MaxBackoff = ruleSpec.MaxBackoff.Duration
+		}
+		p.rules[ruleSpec.Class] = rule
+	}
+	return p
+}
+
+// Rule returns the rule of class
+func (p *Policy) Rule(class examplev1alpha1.ErrorClass) Rule {
+	return p.rules[class]
+}
+
+// Retry returns whether a request that failed with err on its attempt-th attempt is
+// retried, and after how long
+func (p *Policy) Retry(err error, attempt int) (time.Duration, bool) {
+	rule := p.Rule(Classify(err))
+	if rule.Action != examplev1alpha1.RetryBackoff || attempt >= rule.MaxAttempts {
+		return 0, false
+	}
+	return rule.Backoff(attempt), true
+}
+
+// Aborts reports whether err aborts the run it was returned to
+func (p *Policy) Aborts(err error) bool {
+	return p.Rule(Classify(err)).Action == examplev1alpha1.RetryAbort
+}
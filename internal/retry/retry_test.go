@@ -0,0 +1,90 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func TestClassify(t *testing.T) {
+	resource := schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	for _, tc := range []struct {
+		err  error
+		want examplev1alpha1.ErrorClass
+	}{
+		{apierrors.NewConflict(resource, "a", errors.New("stale")), examplev1alpha1.ErrorConflict},
+		{apierrors.NewTooManyRequests("slow down", 1), examplev1alpha1.ErrorThrottled},
+		{apierrors.NewTimeoutError("timed out", 1), examplev1alpha1.ErrorTimeout},
+		{fmt.Errorf("creating: %w", context.DeadlineExceeded), examplev1alpha1.ErrorTimeout},
+		{apierrors.NewInvalid(schema.GroupKind{Kind: "CustomResourceDefinition"}, "a", nil),
+			examplev1alpha1.ErrorInvalid},
+		{apierrors.NewForbidden(resource, "a", errors.New("denied")), examplev1alpha1.ErrorForbidden},
+		{apierrors.NewInternalError(errors.New("etcdserver: request is too large")),
+			examplev1alpha1.ErrorRequestTooLarge},
+		{apierrors.NewRequestEntityTooLargeError("limit is 3145728"), examplev1alpha1.ErrorRequestTooLarge},
+		{apierrors.NewInternalError(errors.New("boom")), examplev1alpha1.ErrorServerError},
+		{apierrors.NewServiceUnavailable("unavailable"), examplev1alpha1.ErrorServerError},
+		{&apierrors.StatusError{ErrStatus: metav1.Status{Code: 502}}, examplev1alpha1.ErrorServerError},
+		{errors.New("connection refused"), examplev1alpha1.ErrorOther},
+	} {
+		if got := Classify(tc.err); got != tc.want {
+			t.Errorf("%v: expected %s, got %s", tc.err, tc.want, got)
+		}
+	}
+}
+
+func TestPolicyOverridesDefaults(t *testing.T) {
+	policy := NewPolicy(&examplev1alpha1.RetryPolicy{
+		Rules: []examplev1alpha1.RetryRule{
+			{
+				Class:          examplev1alpha1.ErrorConflict,
+				Action:         examplev1alpha1.RetryBackoff,
+				MaxAttempts:    3,
+				InitialBackoff: metav1.Duration{Duration: time.Second},
+				MaxBackoff:     metav1.Duration{Duration: 3 * time.Second},
+			},
+			{Class: examplev1alpha1.ErrorForbidden, Action: examplev1alpha1.RetryAbort},
+		},
+	})
+	conflict := apierrors.NewConflict(schema.GroupResource{}, "a", errors.New("stale"))
+
+	for _, tc := range []struct {
+		attempt int
+		backoff time.Duration
+		retry   bool
+	}{
+		{1, time.Second, true},
+		{2, 2 * time.Second, true},
+		{3, 0, false},
+	} {
+		backoff, retry := policy.Retry(conflict, tc.attempt)
+		if backoff != tc.backoff || retry != tc.retry {
+			t.Errorf("attempt %d: expected %v %t, got %v %t", tc.attempt, tc.backoff, tc.retry, backoff, retry)
+		}
+	}
+	if rule := policy.Rule(examplev1alpha1.ErrorConflict); rule.Backoff(5) != 3*time.Second {
+		t.Errorf("expected the backoff to be capped, got %v", rule.Backoff(5))
+	}
+
+	if !policy.Aborts(apierrors.NewForbidden(schema.GroupResource{}, "a", errors.New("denied"))) {
+		t.Errorf("expected Forbidden to abort")
+	}
+	// Classes without a rule keep their default
+	if _, retry := policy.Retry(apierrors.NewServiceUnavailable("unavailable"), 1); !retry {
+		t.Errorf("expected server errors to be retried by default")
+	}
+	if _, retry := policy.Retry(errors.New("connection refused"), 1); retry {
+		t.Errorf("expected other errors to be given up by default")
+	}
+	if policy.RequeueAfter != defaultRequeueAfter {
+		t.Errorf("expected the default requeue delay, got %v", policy.RequeueAfter)
+	}
+}
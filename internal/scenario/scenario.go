@@ -0,0 +1,175 @@
+// Package scenario runs a ReconTest as an ordered list of phases. It derives the spec every
+// phase runs with from the phases before it, and decides when a phase has met its exit
+// criteria or timed out.
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
+)
+
+// Name returns the name of the phase at index, defaulting to its type and position
+func Name(phase examplev1alpha1.Phase, index int) string {
+	if phase.Name != "" {
+		return phase.Name
+	}
+	return fmt.Sprintf("%s-%d", phase.Type, index+1)
+}
+
+// Validate checks that every phase of spec has a unique name and the parameters of its type,
+// and that the spec every phase runs with is valid
+func Validate(spec examplev1alpha1.ReconTestSpec) error {
+	names := map[string]bool{}
+	for i, phase := range spec.Phases {
+		name := Name(phase, i)
+		if names[name] {
+			return fmt.Errorf("phase %q is defined twice", name)
+		}
+		names[name] = true
+
+		var missing string
+		switch phase.Type {
+		case examplev1alpha1.ScenarioCreate:
+			if phase.Count == 0 {
+				missing = "count"
+			}
+		case examplev1alpha1.ScenarioHold, examplev1alpha1.ScenarioChurn:
+			if phase.Duration == nil {
+				missing = "duration"
+			}
+		case examplev1alpha1.ScenarioUpdateSchema:
+			if phase.Schema == nil && phase.Versions == nil {
+				missing = "schema or versions"
+			}
+		case examplev1alpha1.ScenarioCreateInstances:
+			if phase.Instances == nil {
+				missing = "instances"
+			}
+		}
+		if missing != "" {
+			return fmt.Errorf("phase %q of type %s needs %s", name, phase.Type, missing)
+		}
+
+		phaseSpec, _ := Spec(spec, i)
+		if err := crdgen.Validate(phaseSpec); err != nil {
+			return fmt.Errorf("phase %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Spec returns the spec the phase at index of spec.Phases runs with, and whether the run has
+// CRDs in that phase. Create phases set the number of CRDs, UpdateSchema phases replace their
+// schema or versions and update the CRDs to them, CreateInstances phases fill them with custom
+// resources from then on and Delete phases remove them until the next Create phase. Churn
+// phases only churn while they last.
+func Spec(spec examplev1alpha1.ReconTestSpec, index int) (examplev1alpha1.ReconTestSpec, bool) {
+	phaseSpec := *spec.DeepCopy()
+	phaseSpec.Churn = nil
+	phaseSpec.Instances = nil
+	hasCRDs := false
+	for _, phase := range spec.Phases[:index+1] {
+		phaseSpec.Churn = nil
+		switch phase.Type {
+		case examplev1alpha1.ScenarioCreate:
+			phaseSpec.Count = phase.Count
+			hasCRDs = true
+		case examplev1alpha1.ScenarioChurn:
+			phaseSpec.Churn = &examplev1alpha1.ChurnSpec{}
+			if phase.Churn != nil {
+				phaseSpec.Churn = phase.Churn.DeepCopy()
+			}
+		case examplev1alpha1.ScenarioUpdateSchema:
+			if phase.Schema != nil {
+				phaseSpec.Schema = phase.Schema.DeepCopy()
+			}
+			if phase.Versions != nil {
+				phaseSpec.Versions = phase.Versions.DeepCopy()
+			}
+			// The CRDs created so far drift from the new schema and must follow it
+			if phaseSpec.DriftPolicy == examplev1alpha1.DriftIgnore {
+				phaseSpec.DriftPolicy = examplev1alpha1.DriftUpdate
+			}
+		case examplev1alpha1.ScenarioCreateInstances:
+			phaseSpec.Instances = phase.Instances.DeepCopy()
+		case examplev1alpha1.ScenarioDelete:
+			hasCRDs = false
+		}
+	}
+	return crdgen.WithDefaults(phaseSpec), hasCRDs
+}
+
+// Progress is what a pass over the run observed during a phase
+type Progress struct {
+	// Elapsed is the time since the phase started
+	Elapsed time.Duration
+	// Desired and Present are the number of CRDs the run wants and has
+	Desired int32
+	Present int32
+	// Drifted is the number of CRDs that differed from their intended spec
+	Drifted int32
+	// InstancesDesired and InstancesCreated count the custom resources of the run
+	InstancesDesired int32
+	InstancesCreated int32
+	// Remaining is the number of CRDs of the run that still exist
+	Remaining int
+	// WithCondition is the number of CRDs of the run whose condition of a WaitForCondition
+	// phase is True
+	WithCondition int
+}
+
+// Evaluate returns the state of a running phase after a pass and a message explaining it
+func Evaluate(phase examplev1alpha1.Phase, progress Progress) (examplev1alpha1.PhaseState, string) {
+	done, message := exited(phase, progress)
+	switch {
+	case done:
+		return examplev1alpha1.PhaseStateSucceeded, message
+	case phase.Timeout == nil || progress.Elapsed < phase.Timeout.Duration:
+		return examplev1alpha1.PhaseStateRunning, message
+	case phase.OnTimeout == examplev1alpha1.PhaseTimeoutContinue:
+		return examplev1alpha1.PhaseStateTimedOut, timedOut(phase, message)
+	default:
+		return examplev1alpha1.PhaseStateFailed, timedOut(phase, message)
+	}
+}
+
+// timedOut explains that phase timed out with the progress described by message
+func timedOut(phase examplev1alpha1.Phase, message string) string {
+	return fmt.Sprintf("Timed out after %v: %s", phase.Timeout.Duration, message)
+}
+
+// exited returns whether the phase met its exit criteria and a message describing its progress
+func exited(phase examplev1alpha1.Phase, progress Progress) (bool, string) {
+	switch phase.Type {
+	case examplev1alpha1.ScenarioCreate:
+		return progress.Present >= phase.Count, fmt.Sprintf("%d of %d CRDs present", progress.Present, phase.Count)
+	case examplev1alpha1.ScenarioHold, examplev1alpha1.ScenarioChurn:
+		remaining := phase.Duration.Duration - progress.Elapsed
+		if remaining <= 0 {
+			return true, fmt.Sprintf("Ran for %v", phase.Duration.Duration)
+		}
+		return false, fmt.Sprintf("%v remaining", remaining.Round(time.Second))
+	case examplev1alpha1.ScenarioUpdateSchema:
+		return progress.Drifted == 0, fmt.Sprintf("%d CRDs not updated yet", progress.Drifted)
+	case examplev1alpha1.ScenarioCreateInstances:
+		return progress.InstancesCreated >= progress.InstancesDesired,
+			fmt.Sprintf("%d of %d custom resources created", progress.InstancesCreated, progress.InstancesDesired)
+	case examplev1alpha1.ScenarioDelete:
+		return progress.Remaining == 0, fmt.Sprintf("%d CRDs remaining", progress.Remaining)
+	case examplev1alpha1.ScenarioWaitForCondition:
+		return progress.WithCondition >= int(progress.Present),
+			fmt.Sprintf("%d of %d CRDs %s", progress.WithCondition, progress.Present, Condition(phase))
+	}
+	return true, ""
+}
+
+// Condition returns the CRD condition a WaitForCondition phase waits for
+func Condition(phase examplev1alpha1.Phase) string {
+	if phase.Condition == "" {
+		return "Established"
+	}
+	return phase.Condition
+}
@@ -0,0 +1,93 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func scenarioSpec() examplev1alpha1.ReconTestSpec {
+	return examplev1alpha1.ReconTestSpec{
+		Count:       1000,
+		DriftPolicy: examplev1alpha1.DriftIgnore,
+		Phases: []examplev1alpha1.Phase{
+			{Type: examplev1alpha1.ScenarioCreate, Count: 500},
+			{Type: examplev1alpha1.ScenarioChurn, Duration: &metav1.Duration{Duration: time.Minute},
+				Churn: &examplev1alpha1.ChurnSpec{Percent: 10}},
+			{Type: examplev1alpha1.ScenarioUpdateSchema, Versions: &examplev1alpha1.VersionsSpec{Count: 2}},
+			{Type: examplev1alpha1.ScenarioDelete},
+		},
+	}
+}
+
+func TestSpecFoldsPhases(t *testing.T) {
+	spec := scenarioSpec()
+
+	create, hasCRDs := Spec(spec, 0)
+	if create.Count != 500 || !hasCRDs || create.Churn != nil {
+		t.Errorf("create: expected 500 CRDs without churn, got %d CRDs, churn %v", create.Count, create.Churn)
+	}
+	if churn, _ := Spec(spec, 1); churn.Churn == nil || churn.Churn.Percent != 10 {
+		t.Errorf("churn: expected 10%% churn, got %v", churn.Churn)
+	}
+	update, _ := Spec(spec, 2)
+	if update.Churn != nil {
+		t.Errorf("update: churn must end with its phase, got %v", update.Churn)
+	}
+	if update.Versions == nil || update.Versions.Count != 2 || update.DriftPolicy != examplev1alpha1.DriftUpdate {
+		t.Errorf("update: expected 2 versions updated in place, got %v with drift policy %s",
+			update.Versions, update.DriftPolicy)
+	}
+	if _, hasCRDs := Spec(spec, 3); hasCRDs {
+		t.Errorf("delete: expected no CRDs")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(scenarioSpec()); err != nil {
+		t.Fatalf("expected a valid scenario, got %v", err)
+	}
+
+	spec := scenarioSpec()
+	spec.Phases = append(spec.Phases, examplev1alpha1.Phase{Type: examplev1alpha1.ScenarioHold})
+	if err := Validate(spec); err == nil {
+		t.Errorf("expected a Hold phase without duration to be rejected")
+	}
+
+	spec = scenarioSpec()
+	spec.Phases[1].Name, spec.Phases[2].Name = "steady", "steady"
+	if err := Validate(spec); err == nil {
+		t.Errorf("expected duplicate phase names to be rejected")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	create := examplev1alpha1.Phase{Type: examplev1alpha1.ScenarioCreate, Count: 500,
+		Timeout: &metav1.Duration{Duration: time.Minute}}
+	for _, tc := range []struct {
+		name      string
+		onTimeout examplev1alpha1.PhaseTimeoutAction
+		progress  Progress
+		want      examplev1alpha1.PhaseState
+	}{
+		{"done", "", Progress{Present: 500}, examplev1alpha1.PhaseStateSucceeded},
+		{"running", "", Progress{Present: 200, Elapsed: time.Second}, examplev1alpha1.PhaseStateRunning},
+		{"failed", "", Progress{Present: 200, Elapsed: time.Minute}, examplev1alpha1.PhaseStateFailed},
+		{"timed out", examplev1alpha1.PhaseTimeoutContinue, Progress{Present: 200, Elapsed: time.Minute},
+			examplev1alpha1.PhaseStateTimedOut},
+	} {
+		phase := create
+		phase.OnTimeout = tc.onTimeout
+		if got, message := Evaluate(phase, tc.progress); got != tc.want {
+			t.Errorf("%s: expected %s, got %s (%s)", tc.name, tc.want, got, message)
+		}
+	}
+
+	hold := examplev1alpha1.Phase{Type: examplev1alpha1.ScenarioHold, Duration: &metav1.Duration{Duration: time.Minute}}
+	if got, _ := Evaluate(hold, Progress{Elapsed: 2 * time.Minute}); got != examplev1alpha1.PhaseStateSucceeded {
+		t.Errorf("hold: expected %s after its duration, got %s", examplev1alpha1.PhaseStateSucceeded, got)
+	}
+}
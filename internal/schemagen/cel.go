@@ -0,0 +1,271 @@
+package schemagen
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// Cost classes of the generated CEL validation rules, from the cheapest to the most expensive
+const (
+	CELCostLow    = "Low"
+	CELCostMedium = "Medium"
+	CELCostHigh   = "High"
+)
+
+// celMaxLength bounds the strings checked by a rule, so that matching them has a bounded cost
+const celMaxLength = 64
+
+// CELParams controls the x-kubernetes-validations rules AddValidations adds to a schema
+type CELParams struct {
+	// Density is the percentage of the objects and arrays below spec that have candidate rules and carry them
+	Density int
+	// RulesPerNode is the maximum number of rules of every node that carries rules
+	RulesPerNode int
+	// Cost is the most expensive cost class of the rules
+	Cost string
+	// MaxItems bounds every array of the schema that is not bounded yet
+	MaxItems int
+}
+
+// Cost ranks of the candidate rules
+const (
+	celCostLow = iota
+	celCostMedium
+	celCostHigh
+)
+
+// costRank maps the cost classes to their ranks
+var costRank = map[string]int{CELCostLow: celCostLow, CELCostMedium: celCostMedium, CELCostHigh: celCostHigh}
+
+// AddValidations adds CEL validation rules to the objects and arrays below the spec of root
+// and returns the number of rules added. The spec object itself gets no rules, because the
+// conversion between generated versions renames its properties. Rules only constrain data
+// beyond the rest of the schema where a realistic rule would, such as an end date that must
+// not precede a start date or list items with unique names.
+func AddValidations(root *v1.JSONSchemaProps, p CELParams) (int, error) {
+	maxCost, ok := costRank[p.Cost]
+	if !ok {
+		return 0, fmt.Errorf("unknown CEL cost %q", p.Cost)
+	}
+	if p.Density < 1 || p.Density > 100 || p.RulesPerNode < 1 || p.MaxItems < 1 {
+		return 0, fmt.Errorf("density must be within 1-100, rulesPerNode and maxItems at least 1, got %d, %d and %d",
+			p.Density, p.RulesPerNode, p.MaxItems)
+	}
+
+	spec, ok := root.Properties["spec"]
+	if !ok {
+		return 0, nil
+	}
+	c := &celGenerator{params: p, maxCost: maxCost}
+	c.children(&spec)
+	root.Properties["spec"] = spec
+	return c.rules, nil
+}
+
+// celGenerator holds the counters that make rule placement deterministic
+type celGenerator struct {
+	params  CELParams
+	maxCost int
+	// nodes is the number of nodes that could carry rules so far
+	nodes int
+	rules int
+}
+
+// celRule is a candidate rule of a node
+type celRule struct {
+	cost int
+	rule string
+}
+
+// walk bounds the arrays of node and its children and adds rules to the selected ones
+func (c *celGenerator) walk(node *v1.JSONSchemaProps) {
+	if node.Type == "array" && node.MaxItems == nil {
+		maxItems := int64(c.params.MaxItems)
+		node.MaxItems = &maxItems
+	}
+	c.children(node)
+
+	var candidates []celRule
+	switch {
+	case node.Type == "object" && len(node.Properties) > 0:
+		candidates = objectRules(node)
+	case node.Type == "array" && node.Items != nil && node.Items.Schema != nil:
+		candidates = arrayRules(node)
+	default:
+		return
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Spread the selected nodes evenly rather than selecting the first ones
+	n := c.nodes
+	c.nodes++
+	if (n+1)*c.params.Density/100 == n*c.params.Density/100 {
+		return
+	}
+
+	// Prefer the most expensive rules the cost class allows, keeping the order of equal ones
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].cost > candidates[j].cost })
+	for _, candidate := range candidates {
+		if len(node.XValidations) == c.params.RulesPerNode {
+			break
+		}
+		if candidate.cost <= c.maxCost {
+			node.XValidations = append(node.XValidations, v1.ValidationRule{Rule: candidate.rule})
+			c.rules++
+		}
+	}
+}
+
+// children walks the properties, items and additional properties of node
+func (c *celGenerator) children(node *v1.JSONSchemaProps) {
+	for _, name := range sortedNames(node) {
+		prop := node.Properties[name]
+		c.walk(&prop)
+		node.Properties[name] = prop
+	}
+	if node.Items != nil && node.Items.Schema != nil {
+		c.walk(node.Items.Schema)
+	}
+	if node.AdditionalProperties != nil && node.AdditionalProperties.Schema != nil {
+		c.walk(node.AdditionalProperties.Schema)
+	}
+}
+
+// objectRules returns the candidate rules of an object, bounding the strings they check. Every rule checks
+// what the keywords of the schema cannot, so that breaking a keyword never breaks a rule as well.
+func objectRules(node *v1.JSONSchemaProps) []celRule {
+	var rules []celRule
+	var dates []string
+	for _, name := range sortedNames(node) {
+		prop := node.Properties[name]
+		field := "self." + name
+		if len(prop.Enum) > 0 {
+			continue
+		}
+
+		switch {
+		case prop.Type == "string" && (prop.Format == "date" || prop.Format == "date-time"):
+			dates = append(dates, name)
+		case prop.Type == "string" && prop.Pattern == "" && !typedFormat(prop.Format):
+			// A pattern already decides which characters the string may have
+			boundLength(&prop)
+			node.Properties[name] = prop
+			rules = append(rules,
+				celRule{celCostLow, guard(node, name, fmt.Sprintf("%s == %s.trim()", field, field))},
+				celRule{celCostMedium, guard(node, name, fmt.Sprintf("!%s.matches(%s)", field, strconv.Quote(`\s\s`)))})
+		}
+	}
+
+	// CEL sees dates as timestamps, so two of them can be ordered. The one named like an end goes last.
+	if len(dates) >= 2 {
+		first, last := dates[0], dates[1]
+		if strings.HasPrefix(strings.ToLower(first), "end") {
+			first, last = last, first
+		}
+		rule := fmt.Sprintf("self.%s > self.%s", last, first)
+		for _, name := range []string{first, last} {
+			rule = guard(node, name, rule)
+		}
+		// Prepend so it wins over the string checks of the same cost
+		rules = append([]celRule{{celCostLow, rule}}, rules...)
+	}
+	return rules
+}
+
+// arrayRules returns the candidate rules of an array, bounding the strings they check. Like objectRules,
+// it leaves out what the keywords of the schema check, such as the maxItems of the array or the size of
+// its items, and only requires the array not to be empty when it has no minItems.
+func arrayRules(node *v1.JSONSchemaProps) []celRule {
+	items := node.Items.Schema
+	var rules []celRule
+	if node.MinItems == nil {
+		rules = append(rules, celRule{celCostLow, "size(self) >= 1"})
+	}
+
+	switch {
+	case len(items.Enum) > 0 || items.Type == "boolean":
+		// Too few distinct values for a uniqueness check
+	case items.Type == "string" && !typedFormat(items.Format):
+		boundLength(items)
+		if items.Pattern == "" {
+			rules = append(rules, celRule{celCostMedium, "self.all(x, x == x.trim())"})
+		}
+		rules = append(rules, celRule{celCostHigh, "self.all(x, self.exists_one(y, y == x))"})
+	case items.Type == "object":
+		// Comparing whole objects is too expensive for the API server to accept below nested arrays
+		if key := itemKey(items); key != "" {
+			prop := items.Properties[key]
+			if !typedFormat(prop.Format) {
+				boundLength(&prop)
+				items.Properties[key] = prop
+			}
+			if prop.Pattern == "" && !typedFormat(prop.Format) {
+				rules = append(rules, celRule{celCostMedium, fmt.Sprintf("self.all(x, x.%s == x.%s.trim())", key, key)})
+			}
+			rules = append(rules,
+				celRule{celCostHigh, fmt.Sprintf("self.all(x, self.exists_one(y, y.%s == x.%s))", key, key)})
+		}
+	}
+	return rules
+}
+
+// itemKey returns the required string property identifying the items of an array, preferring name
+func itemKey(items *v1.JSONSchemaProps) string {
+	key := ""
+	for _, name := range items.Required {
+		prop := items.Properties[name]
+		if prop.Type != "string" || len(prop.Enum) > 0 {
+			continue
+		}
+		if name == "name" {
+			return name
+		}
+		if key == "" {
+			key = name
+		}
+	}
+	return key
+}
+
+// typedFormat reports whether CEL sees strings of format as values of another type
+func typedFormat(format string) bool {
+	switch format {
+	case "date", "date-time", "duration", "byte":
+		return true
+	}
+	return false
+}
+
+// guard returns rule evaluated only when the optional property name of node is set
+func guard(node *v1.JSONSchemaProps, name, rule string) string {
+	for _, required := range node.Required {
+		if required == name {
+			return rule
+		}
+	}
+	return fmt.Sprintf("!has(self.%s) || %s", name, rule)
+}
+
+// boundLength sets the maximum length of an unbounded string
+func boundLength(prop *v1.JSONSchemaProps) {
+	if prop.MaxLength == nil {
+		maxLength := int64(celMaxLength)
+		prop.MaxLength = &maxLength
+	}
+}
+
+// sortedNames returns the property names of node in order
+func sortedNames(node *v1.JSONSchemaProps) []string {
+	names := make([]string, 0, len(node.Properties))
+	for name := range node.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
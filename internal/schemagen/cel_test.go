@@ -0,0 +1,60 @@
+package schemagen
+
+import (
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func TestAddValidationsToComplexPreset(t *testing.T) {
+	for _, tc := range []struct {
+		cost, timeline, departments string
+	}{
+		{CELCostLow, "self.endDate > self.startDate", "size(self) >= 1"},
+		{CELCostMedium, "self.endDate > self.startDate", "self.all(x, x.name == x.name.trim())"},
+		{CELCostHigh, "self.endDate > self.startDate", "self.all(x, self.exists_one(y, y.name == x.name))"},
+	} {
+		props := Complex()
+		params := CELParams{Density: 100, RulesPerNode: 1, Cost: tc.cost, MaxItems: 16}
+		if _, err := AddValidations(props, params); err != nil {
+			t.Fatal(err)
+		}
+		requireStructural(t, props)
+
+		organization := props.Properties["spec"].Properties["organization"]
+		timeline := props.Properties["spec"].Properties["projectMetadata"].Properties["timeline"]
+		for _, node := range []struct {
+			schema v1.JSONSchemaProps
+			rule   string
+		}{
+			{timeline, tc.timeline},
+			{organization.Properties["departments"], tc.departments},
+		} {
+			if len(node.schema.XValidations) != 1 || node.schema.XValidations[0].Rule != node.rule {
+				t.Errorf("%s: expected rule %q, got %v", tc.cost, node.rule, node.schema.XValidations)
+			}
+		}
+		if props.Properties["spec"].XValidations != nil {
+			t.Errorf("%s: expected no rules on spec itself", tc.cost)
+		}
+	}
+}
+
+func TestAddValidationsHonorsDensity(t *testing.T) {
+	count := func(density int) int {
+		props, err := Generate(Params{Depth: 4, FanOut: 4, ArrayNesting: 1, Patterns: 4, Formats: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules, err := AddValidations(props, CELParams{Density: density, RulesPerNode: 1, Cost: CELCostMedium, MaxItems: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rules
+	}
+
+	all, half := count(100), count(50)
+	if half < all/2-1 || half > all/2+1 {
+		t.Fatalf("expected about half of %d rules at density 50, got %d", all, half)
+	}
+}
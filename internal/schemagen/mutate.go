@@ -0,0 +1,147 @@
+package schemagen
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// Changes Mutate applies to a schema. The first three are compatible: every object valid
+// before the change is still valid after it. The others are not.
+const (
+	ChangeAddOptionalProperty = "AddOptionalProperty"
+	ChangeLoosenMaximum       = "LoosenMaximum"
+	ChangeAddEnumValue        = "AddEnumValue"
+	ChangeAddRequiredProperty = "AddRequiredProperty"
+	ChangeTightenMaximum      = "TightenMaximum"
+	ChangeRemoveProperty      = "RemoveProperty"
+	ChangeChangePropertyType  = "ChangePropertyType"
+)
+
+// loosenBy is how much LoosenMaximum raises a maximum by
+const loosenBy = 100
+
+// revisionMark starts the mark of the revision in the description of the root of a schema
+const revisionMark = "Schema revision "
+
+// RevisionDescription returns the mark Mutate ends the description of the root of a schema
+// with at revision. It shows the revision in the OpenAPI documents the API server publishes.
+func RevisionDescription(revision int) string {
+	return fmt.Sprintf("%s%d", revisionMark, revision)
+}
+
+// Mutate applies change to the spec of root for the given revision and marks root with the
+// revision. Changes look for their target in a depth-first walk of spec in property name
+// order, so the same schema always changes the same way. A change that finds no target only
+// marks the revision. Incompatible changes may be rejected by the API server, for example
+// when a CEL rule refers to a removed or retyped property.
+func Mutate(root *v1.JSONSchemaProps, change string, revision int) error {
+	spec, ok := root.Properties["spec"]
+	if !ok {
+		return fmt.Errorf("schema has no spec to change")
+	}
+	added := fmt.Sprintf("revision%d", revision)
+
+	switch change {
+	case ChangeAddOptionalProperty, ChangeAddRequiredProperty:
+		if spec.Properties == nil {
+			spec.Properties = map[string]v1.JSONSchemaProps{}
+		}
+		spec.Properties[added] = v1.JSONSchemaProps{
+			Type:        "string",
+			Description: fmt.Sprintf("Added by schema revision %d", revision),
+		}
+		if change == ChangeAddRequiredProperty {
+			spec.Required = append(spec.Required, added)
+		}
+	case ChangeLoosenMaximum:
+		mutateFirst(&spec, func(prop *v1.JSONSchemaProps) bool {
+			if prop.Maximum == nil {
+				return false
+			}
+			maximum := *prop.Maximum + loosenBy
+			prop.Maximum = &maximum
+			return true
+		})
+	case ChangeTightenMaximum:
+		mutateFirst(&spec, func(prop *v1.JSONSchemaProps) bool {
+			if prop.Maximum == nil {
+				return false
+			}
+			minimum := 0.0
+			if prop.Minimum != nil {
+				minimum = *prop.Minimum
+			}
+			maximum := minimum + (*prop.Maximum-minimum)/2
+			if prop.Type == "integer" {
+				maximum = float64(int64(maximum))
+			}
+			prop.Maximum = &maximum
+			return true
+		})
+	case ChangeAddEnumValue:
+		mutateFirst(&spec, func(prop *v1.JSONSchemaProps) bool {
+			if prop.Type != "string" || len(prop.Enum) == 0 {
+				return false
+			}
+			prop.Enum = append(append([]v1.JSON(nil), prop.Enum...), v1.JSON{Raw: []byte(fmt.Sprintf("%q", added))})
+			return true
+		})
+	case ChangeRemoveProperty:
+		removeFirst(&spec)
+	case ChangeChangePropertyType:
+		mutateFirst(&spec, func(prop *v1.JSONSchemaProps) bool {
+			if prop.Type != "string" {
+				return false
+			}
+			*prop = v1.JSONSchemaProps{Type: "integer", Description: prop.Description}
+			return true
+		})
+	default:
+		return fmt.Errorf("unknown schema change %q", change)
+	}
+
+	root.Properties["spec"] = spec
+	// The mark of the previous revision is replaced
+	description := strings.TrimSpace(root.Description)
+	if i := strings.Index(description, revisionMark); i >= 0 {
+		description = strings.TrimSpace(description[:i])
+	}
+	if description != "" {
+		description += " "
+	}
+	root.Description = description + RevisionDescription(revision)
+	return nil
+}
+
+// mutateFirst calls fn on the properties below node in a depth-first walk in name order until
+// fn reports that it changed one, and reports whether it did
+func mutateFirst(node *v1.JSONSchemaProps, fn func(*v1.JSONSchemaProps) bool) bool {
+	for _, name := range sortedNames(node) {
+		// Map values are copies, a changed property is written back
+		prop := node.Properties[name]
+		if fn(&prop) || mutateFirst(&prop, fn) {
+			node.Properties[name] = prop
+			return true
+		}
+	}
+	if node.Items != nil && node.Items.Schema != nil {
+		return fn(node.Items.Schema) || mutateFirst(node.Items.Schema, fn)
+	}
+	return false
+}
+
+// removeFirst removes the first optional property of node in name order
+func removeFirst(node *v1.JSONSchemaProps) {
+	required := map[string]bool{}
+	for _, name := range node.Required {
+		required[name] = true
+	}
+	for _, name := range sortedNames(node) {
+		if !required[name] {
+			delete(node.Properties, name)
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package schemagen
+
+import (
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// mutable returns a small schema with a target for every change
+func mutable() *v1.JSONSchemaProps {
+	return wrapSpec(v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"count": {Type: "integer", Minimum: float64Ptr(0), Maximum: float64Ptr(10)},
+			"name":  {Type: "string", MaxLength: int64Ptr(8)},
+			"tier":  {Type: "string", Enum: enumValues(2)},
+		},
+		Required: []string{"name"},
+	})
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestMutate(t *testing.T) {
+	for _, tc := range []struct {
+		change string
+		check  func(spec v1.JSONSchemaProps) bool
+	}{
+		{ChangeAddOptionalProperty, func(spec v1.JSONSchemaProps) bool {
+			_, ok := spec.Properties["revision3"]
+			return ok && len(spec.Required) == 1
+		}},
+		{ChangeAddRequiredProperty, func(spec v1.JSONSchemaProps) bool {
+			return len(spec.Required) == 2 && spec.Required[1] == "revision3"
+		}},
+		{ChangeLoosenMaximum, func(spec v1.JSONSchemaProps) bool {
+			return *spec.Properties["count"].Maximum == 10+loosenBy
+		}},
+		{ChangeTightenMaximum, func(spec v1.JSONSchemaProps) bool {
+			return *spec.Properties["count"].Maximum == 5
+		}},
+		{ChangeAddEnumValue, func(spec v1.JSONSchemaProps) bool {
+			enum := spec.Properties["tier"].Enum
+			return len(enum) == 3 && string(enum[2].Raw) == `"revision3"`
+		}},
+		{ChangeRemoveProperty, func(spec v1.JSONSchemaProps) bool {
+			// The first property in name order that is not required
+			_, ok := spec.Properties["count"]
+			return !ok && len(spec.Properties) == 2
+		}},
+		{ChangeChangePropertyType, func(spec v1.JSONSchemaProps) bool {
+			name := spec.Properties["name"]
+			return name.Type == "integer" && name.MaxLength == nil
+		}},
+	} {
+		t.Run(tc.change, func(t *testing.T) {
+			root := mutable()
+			root.Description = "Generated. " + RevisionDescription(2)
+			if err := Mutate(root, tc.change, 3); err != nil {
+				t.Fatal(err)
+			}
+			if !tc.check(root.Properties["spec"]) {
+				t.Errorf("unexpected spec after %s: %+v", tc.change, root.Properties["spec"])
+			}
+			if root.Description != "Generated. "+RevisionDescription(3) {
+				t.Errorf("expected the revision mark to be replaced, got %q", root.Description)
+			}
+			requireStructural(t, root)
+		})
+	}
+
+	if err := Mutate(mutable(), "Unknown", 1); err == nil {
+		t.Error("expected an unknown change to be rejected")
+	}
+}
@@ -0,0 +1,209 @@
+package schemagen
+
+import (
+	"fmt"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// PresetComplex is the name of the organization/projectMetadata preset
+const PresetComplex = "Complex"
+
+// Preset returns the built-in schema with the given name
+func Preset(name string) (*v1.JSONSchemaProps, error) {
+	switch name {
+	case PresetComplex:
+		return Complex(), nil
+	default:
+		return nil, fmt.Errorf("unknown schema preset %q", name)
+	}
+}
+
+// Complex returns a highly nested schema describing an organization and its project metadata
+func Complex() *v1.JSONSchemaProps {
+	return &v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"spec": {
+				Type: "object",
+				Properties: map[string]v1.JSONSchemaProps{
+					"organization": {
+						Type: "object",
+						Properties: map[string]v1.JSONSchemaProps{
+							"name": {
+								Type: "string",
+							},
+							"foundedYear": {
+								Type:    "integer",
+								Minimum: float64Ptr(1800.0),
+								Maximum: float64Ptr(2100.0),
+							},
+							"address": {
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"street": {
+										Type: "string",
+									},
+									"city": {
+										Type: "string",
+									},
+									"state": {
+										Type: "string",
+									},
+									"postalCode": {
+										Type:    "string",
+										Pattern: `^\d{5}(-\d{4})?$`, // US Zip code pattern
+									},
+									"geoLocation": {
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"latitude": {
+												Type:    "number",
+												Minimum: float64Ptr(-90.0),
+												Maximum: float64Ptr(90.0),
+											},
+											"longitude": {
+												Type:    "number",
+												Minimum: float64Ptr(-180.0),
+												Maximum: float64Ptr(180.0),
+											},
+										},
+										Required: []string{"latitude", "longitude"},
+									},
+								},
+								Required: []string{"street", "city", "state"},
+							},
+							"departments": {
+								Type: "array",
+								Items: &v1.JSONSchemaPropsOrArray{
+									Schema: &v1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"name": {
+												Type: "string",
+											},
+											"headCount": {
+												Type:    "integer",
+												Minimum: float64Ptr(0.0),
+											},
+											"budget": {
+												Type: "object",
+												Properties: map[string]v1.JSONSchemaProps{
+													"annual": {
+														Type:    "number",
+														Minimum: float64Ptr(0.0),
+													},
+													"currency": {
+														Type: "string",
+														Enum: []v1.JSON{
+															{Raw: []byte(`"USD"`)},
+															{Raw: []byte(`"EUR"`)},
+															{Raw: []byte(`"GBP"`)},
+															{Raw: []byte(`"JPY"`)},
+														},
+													},
+												},
+												Required: []string{"annual", "currency"},
+											},
+										},
+										Required: []string{"name", "headCount"},
+									},
+								},
+							},
+						},
+						Required: []string{"name", "foundedYear"},
+					},
+					"projectMetadata": {
+						Type: "object",
+						Properties: map[string]v1.JSONSchemaProps{
+							"projectId": {
+								Type: "string",
+							},
+							"status": {
+								Type: "string",
+								Enum: []v1.JSON{
+									{Raw: []byte(`"planning"`)},
+									{Raw: []byte(`"in-progress"`)},
+									{Raw: []byte(`"completed"`)},
+									{Raw: []byte(`"on-hold"`)},
+								},
+							},
+							"resources": {
+								Type: "array",
+								Items: &v1.JSONSchemaPropsOrArray{
+									Schema: &v1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]v1.JSONSchemaProps{
+											"type": {
+												Type: "string",
+											},
+											"quantity": {
+												Type:    "integer",
+												Minimum: float64Ptr(0.0),
+											},
+											"details": {
+												Type: "object",
+												AdditionalProperties: &v1.JSONSchemaPropsOrBool{
+													Allows: true,
+												},
+											},
+										},
+										Required: []string{"type", "quantity"},
+									},
+								},
+							},
+							"timeline": {
+								Type: "object",
+								Properties: map[string]v1.JSONSchemaProps{
+									"startDate": {
+										Type:   "string",
+										Format: "date",
+									},
+									"endDate": {
+										Type:   "string",
+										Format: "date",
+									},
+									"milestones": {
+										Type: "array",
+										Items: &v1.JSONSchemaPropsOrArray{
+											Schema: &v1.JSONSchemaProps{
+												Type: "object",
+												Properties: map[string]v1.JSONSchemaProps{
+													"name": {
+														Type: "string",
+													},
+													"completionDate": {
+														Type:   "string",
+														Format: "date",
+													},
+													"dependencies": {
+														Type: "array",
+														Items: &v1.JSONSchemaPropsOrArray{
+															Schema: &v1.JSONSchemaProps{
+																Type: "string",
+															},
+														},
+													},
+												},
+												Required: []string{"name", "completionDate"},
+											},
+										},
+									},
+								},
+								Required: []string{"startDate", "endDate"},
+							},
+						},
+						Required: []string{"projectId", "status"},
+					},
+				},
+				Required: []string{"organization", "projectMetadata"},
+			},
+		},
+		Required: []string{"spec"},
+	}
+}
+
+// Helper function to create float64 pointers
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,230 @@
+// Package schemagen builds the OpenAPI v3 schemas of the CRDs generated by a
+// ReconTest run. Schemas are either a named preset or derived deterministically
+// from a set of size parameters, so that the same parameters always produce the
+// same schema.
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// MaxProperties bounds the number of properties Generate produces before padding
+const MaxProperties = 100000
+
+// MaxTargetBytes bounds the size Generate pads a schema to. It only leaves room under the 1.5MiB
+// request limit of etcd for a CRD with a single version: every served version holds a copy of the
+// schema, so the bound of a CRD with several versions is lower and checked by crdgen.Validate.
+const MaxTargetBytes = 1 << 20
+
+// Params controls the shape of a generated schema
+type Params struct {
+	// Depth is the nesting depth of objects under spec
+	Depth int
+	// FanOut is the number of properties of every object
+	FanOut int
+	// ArrayNesting is the number of arrays wrapped around the first nested object of every object
+	ArrayNesting int
+	// Enums is the maximum number of string properties restricted to an enum
+	Enums int
+	// EnumSize is the number of values of every enum
+	EnumSize int
+	// Patterns is the maximum number of string properties validated by a pattern
+	Patterns int
+	// Formats is the maximum number of string properties validated by a format
+	Formats int
+	// TargetBytes is the minimum serialized size of the schema; zero disables padding
+	TargetBytes int
+}
+
+// patterns are assigned to string properties in turn
+var patterns = []string{
+	`^\d{5}(-\d{4})?$`,
+	`^[a-z]([-a-z0-9]*[a-z0-9])?$`,
+	`^[A-Z]{3}-\d{4}$`,
+	`^#[0-9a-fA-F]{6}$`,
+}
+
+// formats are assigned to string properties in turn
+var formats = []string{"date", "date-time", "uuid", "ipv4", "email"}
+
+// leafTypes are assigned to leaf properties in turn
+var leafTypes = []string{"string", "integer", "number", "boolean"}
+
+// paddingDescription is the description of every padding property
+var paddingDescription = strings.Repeat("padding ", 32)
+
+// Generate returns a schema with a spec object shaped by p
+func Generate(p Params) (*v1.JSONSchemaProps, error) {
+	if p.Depth < 1 || p.FanOut < 1 {
+		return nil, fmt.Errorf("depth and fanOut must be at least 1, got %d and %d", p.Depth, p.FanOut)
+	}
+	if count := propertyCount(p.Depth, p.FanOut); count > MaxProperties {
+		return nil, fmt.Errorf("depth %d and fanOut %d produce more than %d properties", p.Depth, p.FanOut, MaxProperties)
+	}
+	if p.TargetBytes > MaxTargetBytes {
+		return nil, fmt.Errorf("targetBytes %d is more than the %d bytes a CRD schema can hold", p.TargetBytes,
+			MaxTargetBytes)
+	}
+	if p.EnumSize < 1 {
+		p.EnumSize = 1
+	}
+
+	g := &generator{params: p}
+	spec := g.object(p.Depth)
+	root := wrapSpec(spec)
+
+	if p.TargetBytes > 0 {
+		if err := pad(root, p.TargetBytes); err != nil {
+			return nil, err
+		}
+	}
+	return root, nil
+}
+
+// generator holds the counters that make leaf assignment deterministic
+type generator struct {
+	params  Params
+	leaves  int
+	strings int
+}
+
+// object returns an object with FanOut properties nested level objects deep
+func (g *generator) object(level int) v1.JSONSchemaProps {
+	obj := v1.JSONSchemaProps{
+		Type:       "object",
+		Properties: make(map[string]v1.JSONSchemaProps, g.params.FanOut),
+	}
+
+	for i := 0; i < g.params.FanOut; i++ {
+		name := fmt.Sprintf("level%dField%d", g.params.Depth-level+1, i)
+
+		var child v1.JSONSchemaProps
+		if level > 1 {
+			child = g.object(level - 1)
+			if i == 0 {
+				child = wrapArrays(child, g.params.ArrayNesting)
+			}
+		} else {
+			child = g.leaf()
+		}
+
+		obj.Properties[name] = child
+		if i == 0 {
+			obj.Required = []string{name}
+		}
+	}
+
+	return obj
+}
+
+// leaf returns the next scalar property. String properties get patterns,
+// then formats, then enums until each requested count is used up.
+func (g *generator) leaf() v1.JSONSchemaProps {
+	leafType := leafTypes[g.leaves%len(leafTypes)]
+	g.leaves++
+
+	prop := v1.JSONSchemaProps{Type: leafType}
+	switch leafType {
+	case "string":
+		n := g.strings
+		g.strings++
+		switch {
+		case n < g.params.Patterns:
+			prop.Pattern = patterns[n%len(patterns)]
+		case n < g.params.Patterns+g.params.Formats:
+			prop.Format = formats[(n-g.params.Patterns)%len(formats)]
+		case n < g.params.Patterns+g.params.Formats+g.params.Enums:
+			prop.Enum = enumValues(g.params.EnumSize)
+		}
+	case "integer":
+		prop.Minimum = float64Ptr(0)
+		prop.Maximum = float64Ptr(float64(1000 + g.leaves))
+	case "number":
+		prop.Minimum = float64Ptr(-1000)
+		prop.Maximum = float64Ptr(1000)
+	}
+	return prop
+}
+
+// enumValues returns size distinct JSON string values
+func enumValues(size int) []v1.JSON {
+	values := make([]v1.JSON, 0, size)
+	for i := 0; i < size; i++ {
+		values = append(values, v1.JSON{Raw: []byte(fmt.Sprintf(`"value%d"`, i))})
+	}
+	return values
+}
+
+// wrapArrays wraps prop in n nested arrays
+func wrapArrays(prop v1.JSONSchemaProps, n int) v1.JSONSchemaProps {
+	for i := 0; i < n; i++ {
+		items := prop
+		prop = v1.JSONSchemaProps{
+			Type:  "array",
+			Items: &v1.JSONSchemaPropsOrArray{Schema: &items},
+		}
+	}
+	return prop
+}
+
+// wrapSpec returns the root schema of a custom resource whose spec is described by spec
+func wrapSpec(spec v1.JSONSchemaProps) *v1.JSONSchemaProps {
+	return &v1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]v1.JSONSchemaProps{
+			"spec": spec,
+		},
+		Required: []string{"spec"},
+	}
+}
+
+// pad adds padding properties to the spec of root until root serializes to at least target bytes
+func pad(root *v1.JSONSchemaProps, target int) error {
+	// The properties map is shared with the copy held by root
+	spec := root.Properties["spec"]
+
+	entry, err := json.Marshal(map[string]v1.JSONSchemaProps{
+		"padding0": {Type: "string", Description: paddingDescription},
+	})
+	if err != nil {
+		return err
+	}
+	entrySize := len(entry)
+
+	added := 0
+	for {
+		raw, err := json.Marshal(root)
+		if err != nil {
+			return err
+		}
+		if len(raw) >= target {
+			return nil
+		}
+
+		for n := (target-len(raw))/entrySize + 1; n > 0; n-- {
+			spec.Properties[fmt.Sprintf("padding%d", added)] = v1.JSONSchemaProps{
+				Type:        "string",
+				Description: paddingDescription,
+			}
+			added++
+		}
+	}
+}
+
+// propertyCount returns the number of properties of a tree of the given depth and fan-out,
+// saturating just above MaxProperties
+func propertyCount(depth, fanOut int) int {
+	total, level := 0, 1
+	for i := 0; i < depth; i++ {
+		level *= fanOut
+		total += level
+		if total > MaxProperties {
+			return MaxProperties + 1
+		}
+	}
+	return total
+}
@@ -0,0 +1,80 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+)
+
+// requireStructural fails the test unless props is a valid structural schema
+func requireStructural(t *testing.T, props *v1.JSONSchemaProps) {
+	t.Helper()
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(props, internal, nil); err != nil {
+		t.Fatalf("converting schema: %v", err)
+	}
+	structural, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		t.Fatalf("schema is not structural: %v", err)
+	}
+	if errs := structuralschema.ValidateStructural(nil, structural); len(errs) > 0 {
+		t.Fatalf("schema is not structural: %v", errs.ToAggregate())
+	}
+}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	params := Params{Depth: 3, FanOut: 3, ArrayNesting: 2, Enums: 2, EnumSize: 5, Patterns: 2, Formats: 2}
+
+	first, err := Generate(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Generate(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("the same params produced different schemas")
+	}
+	requireStructural(t, first)
+}
+
+func TestGeneratePadsToTargetBytes(t *testing.T) {
+	props, err := Generate(Params{Depth: 2, FanOut: 2, TargetBytes: 64 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) < 64*1024 {
+		t.Fatalf("expected at least %d bytes, got %d", 64*1024, len(raw))
+	}
+	requireStructural(t, props)
+}
+
+func TestGenerateRejectsOversizedTargets(t *testing.T) {
+	if _, err := Generate(Params{Depth: 2, FanOut: 2, TargetBytes: MaxTargetBytes + 1}); err == nil {
+		t.Fatal("expected an error for a target above MaxTargetBytes")
+	}
+}
+
+func TestGenerateRejectsOversizedTrees(t *testing.T) {
+	if _, err := Generate(Params{Depth: 16, FanOut: 64}); err == nil {
+		t.Fatal("expected an error for a tree above MaxProperties")
+	}
+}
+
+func TestComplexPresetIsStructural(t *testing.T) {
+	props, err := Preset(PresetComplex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requireStructural(t, props)
+}
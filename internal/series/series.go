@@ -0,0 +1,54 @@
+// Package series deletes the Prometheus series of metric vectors that carry a given label value,
+// so the series of a finished run do not outlive it.
+package series
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Vec is a metric vector whose series can be deleted by their labels, such as a CounterVec,
+// GaugeVec or HistogramVec
+type Vec interface {
+	prometheus.Collector
+	Delete(labels prometheus.Labels) bool
+}
+
+// Delete deletes every series of vecs whose label name is set to value, whatever their other
+// labels, and returns how many it deleted
+func Delete(name, value string, vecs ...Vec) int {
+	deleted := 0
+	for _, vec := range vecs {
+		for _, labels := range matching(vec, name, value) {
+			if vec.Delete(labels) {
+				deleted++
+			}
+		}
+	}
+	return deleted
+}
+
+// matching returns the labels of the series of vec whose label name is set to value
+func matching(vec Vec, name, value string) []prometheus.Labels {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(metrics)
+		close(metrics)
+	}()
+
+	var matches []prometheus.Labels
+	for metric := range metrics {
+		var m dto.Metric
+		if err := metric.Write(&m); err != nil {
+			continue
+		}
+		labels := make(prometheus.Labels, len(m.Label))
+		for _, pair := range m.Label {
+			labels[pair.GetName()] = pair.GetValue()
+		}
+		if v, ok := labels[name]; ok && v == value {
+			matches = append(matches, labels)
+		}
+	}
+	return matches
+}
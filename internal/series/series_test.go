@@ -0,0 +1,33 @@
+package series
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDelete(t *testing.T) {
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total", Help: "Test."},
+		[]string{"recontest", "operation"})
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_seconds", Help: "Test."},
+		[]string{"recontest"})
+	counter.WithLabelValues("default/a", "create").Inc()
+	counter.WithLabelValues("default/a", "delete").Inc()
+	counter.WithLabelValues("default/b", "create").Inc()
+	histogram.WithLabelValues("default/a").Observe(1)
+	histogram.WithLabelValues("default/ab").Observe(1)
+
+	if deleted := Delete("recontest", "default/a", counter, histogram); deleted != 3 {
+		t.Errorf("expected 3 deleted series, got %d", deleted)
+	}
+	if count := testutil.CollectAndCount(counter); count != 1 {
+		t.Errorf("expected the series of other runs to be kept, got %d counters", count)
+	}
+	if count := testutil.CollectAndCount(histogram); count != 1 {
+		t.Errorf("expected the series of other runs to be kept, got %d histograms", count)
+	}
+	if deleted := Delete("recontest", "default/a", counter, histogram); deleted != 0 {
+		t.Errorf("expected nothing left to delete, got %d", deleted)
+	}
+}
@@ -0,0 +1,201 @@
+// Package slo evaluates the assertions of a ReconTest run against what was measured
+// during the run.
+package slo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/stats"
+)
+
+// defaultPercentile is used for latency assertions that leave Percentile unset
+const defaultPercentile = 99
+
+// Observations is what was measured during a run
+type Observations struct {
+	NamesAccepted  []time.Duration
+	Established    []time.Duration
+	InstanceCreate []time.Duration
+
+	// Requests and Errors count the API requests of the run and those that failed
+	Requests int64
+	Errors   int64
+
+	// Desired is the number of CRDs of the run, FirstCreate the time its first Create
+	// call was issued and EstablishedAt the time each CRD was first established
+	Desired       int
+	FirstCreate   time.Time
+	EstablishedAt []time.Time
+
+	Now time.Time
+	// Final is set once the run is over, so assertions that are still pending fail
+	Final bool
+}
+
+// Name returns the name of an assertion, defaulting it from its metric
+func Name(assertion examplev1alpha1.Assertion) string {
+	if assertion.Name != "" {
+		return assertion.Name
+	}
+	if isLatency(assertion.Metric) {
+		return fmt.Sprintf("%sP%d", assertion.Metric, percentile(assertion))
+	}
+	return string(assertion.Metric)
+}
+
+// Validate checks that every assertion has the threshold its metric needs and a unique name
+func Validate(assertions []examplev1alpha1.Assertion) error {
+	names := sets.NewString()
+	for _, assertion := range assertions {
+		name := Name(assertion)
+		if names.Has(name) {
+			return fmt.Errorf("assertions: duplicate name %q", name)
+		}
+		names.Insert(name)
+
+		switch {
+		case assertion.Metric == examplev1alpha1.AssertErrorRate && assertion.MaxPercent == nil:
+			return fmt.Errorf("assertions: %s needs maxPercent", name)
+		case assertion.Metric != examplev1alpha1.AssertErrorRate && assertion.Max == nil:
+			return fmt.Errorf("assertions: %s needs max", name)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the verdict of every assertion, in order
+func Evaluate(assertions []examplev1alpha1.Assertion, obs Observations) []examplev1alpha1.AssertionStatus {
+	results := make([]examplev1alpha1.AssertionStatus, 0, len(assertions))
+	for _, assertion := range assertions {
+		var result examplev1alpha1.AssertionStatus
+		switch assertion.Metric {
+		case examplev1alpha1.AssertNamesAcceptedLatency:
+			result = latency(assertion, obs.NamesAccepted)
+		case examplev1alpha1.AssertEstablishedLatency:
+			result = latency(assertion, obs.Established)
+		case examplev1alpha1.AssertInstanceCreateLatency:
+			result = latency(assertion, obs.InstanceCreate)
+		case examplev1alpha1.AssertErrorRate:
+			result = errorRate(assertion, obs)
+		case examplev1alpha1.AssertAllEstablished:
+			result = allEstablished(assertion, obs)
+		default:
+			result = examplev1alpha1.AssertionStatus{
+				State:   examplev1alpha1.AssertionFailed,
+				Message: fmt.Sprintf("unknown metric %q", assertion.Metric),
+			}
+		}
+		result.Name = Name(assertion)
+		if result.State == examplev1alpha1.AssertionPending && obs.Final {
+			result.State = examplev1alpha1.AssertionFailed
+			result.Message += ", and the run is over"
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Verdict summarises results into the status, reason and message of the Passed condition
+func Verdict(results []examplev1alpha1.AssertionStatus) (metav1.ConditionStatus, string, string) {
+	var failed, pending []string
+	for _, result := range results {
+		switch result.State {
+		case examplev1alpha1.AssertionFailed:
+			failed = append(failed, result.Name+": "+result.Message)
+		case examplev1alpha1.AssertionPending:
+			pending = append(pending, result.Name)
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		return metav1.ConditionFalse, "AssertionsFailed", strings.Join(failed, "; ")
+	case len(pending) > 0:
+		return metav1.ConditionUnknown, "AssertionsPending", "Waiting for " + strings.Join(pending, ", ")
+	default:
+		return metav1.ConditionTrue, "AssertionsPassed", fmt.Sprintf("All %d assertions passed", len(results))
+	}
+}
+
+// latency compares a percentile of samples against the Max of assertion
+func latency(assertion examplev1alpha1.Assertion, samples []time.Duration) examplev1alpha1.AssertionStatus {
+	p := percentile(assertion)
+	if len(samples) == 0 {
+		return examplev1alpha1.AssertionStatus{State: examplev1alpha1.AssertionPending, Message: "no samples"}
+	}
+	value := stats.Percentile(stats.Sorted(samples), float64(p))
+	return compare(value.String(), value <= assertion.Max.Duration,
+		fmt.Sprintf("p%d of %d samples is %s, at most %s allowed", p, len(samples), value, assertion.Max.Duration))
+}
+
+// errorRate compares the percentage of failed requests against the MaxPercent of assertion
+func errorRate(assertion examplev1alpha1.Assertion, obs Observations) examplev1alpha1.AssertionStatus {
+	if obs.Requests == 0 {
+		return examplev1alpha1.AssertionStatus{State: examplev1alpha1.AssertionPending, Message: "no requests"}
+	}
+	rate := float64(obs.Errors) * 100 / float64(obs.Requests)
+	limit := assertion.MaxPercent.AsApproximateFloat64()
+	value := fmt.Sprintf("%.3g%%", rate)
+	return compare(value, rate <= limit,
+		fmt.Sprintf("%d of %d requests failed (%s), at most %g%% allowed", obs.Errors, obs.Requests, value, limit))
+}
+
+// allEstablished checks that every desired CRD was established within the Max of assertion
+// from the first Create call. It fails as soon as the deadline passes.
+func allEstablished(assertion examplev1alpha1.Assertion, obs Observations) examplev1alpha1.AssertionStatus {
+	limit := assertion.Max.Duration
+	if obs.FirstCreate.IsZero() {
+		return examplev1alpha1.AssertionStatus{State: examplev1alpha1.AssertionPending, Message: "no CRD created"}
+	}
+	if len(obs.EstablishedAt) < obs.Desired {
+		elapsed := obs.Now.Sub(obs.FirstCreate)
+		message := fmt.Sprintf("%d of %d CRDs established after %s, all allowed within %s",
+			len(obs.EstablishedAt), obs.Desired, elapsed.Round(time.Millisecond), limit)
+		if elapsed > limit {
+			return examplev1alpha1.AssertionStatus{State: examplev1alpha1.AssertionFailed, Message: message}
+		}
+		return examplev1alpha1.AssertionStatus{State: examplev1alpha1.AssertionPending, Message: message}
+	}
+
+	last := obs.FirstCreate
+	for _, at := range obs.EstablishedAt {
+		if at.After(last) {
+			last = at
+		}
+	}
+	value := last.Sub(obs.FirstCreate).Round(time.Millisecond)
+	return compare(value.String(), value <= limit,
+		fmt.Sprintf("all %d CRDs established within %s, at most %s allowed", obs.Desired, value, limit))
+}
+
+// compare returns a Passed or Failed verdict with the observed value
+func compare(value string, passed bool, message string) examplev1alpha1.AssertionStatus {
+	state := examplev1alpha1.AssertionFailed
+	if passed {
+		state = examplev1alpha1.AssertionPassed
+	}
+	return examplev1alpha1.AssertionStatus{State: state, Value: value, Message: message}
+}
+
+// isLatency reports whether metric is a latency compared at a percentile
+func isLatency(metric examplev1alpha1.AssertionMetric) bool {
+	switch metric {
+	case examplev1alpha1.AssertNamesAcceptedLatency, examplev1alpha1.AssertEstablishedLatency,
+		examplev1alpha1.AssertInstanceCreateLatency:
+		return true
+	}
+	return false
+}
+
+// percentile returns the percentile of a latency assertion
+func percentile(assertion examplev1alpha1.Assertion) int32 {
+	if assertion.Percentile == 0 {
+		return defaultPercentile
+	}
+	return assertion.Percentile
+}
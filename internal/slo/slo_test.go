@@ -0,0 +1,93 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func seconds(s ...int) []time.Duration {
+	samples := make([]time.Duration, 0, len(s))
+	for _, n := range s {
+		samples = append(samples, time.Duration(n)*time.Second)
+	}
+	return samples
+}
+
+func TestEvaluate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxPercent := resource.MustParse("0.1")
+	assertions := []examplev1alpha1.Assertion{
+		{Metric: examplev1alpha1.AssertEstablishedLatency, Max: &metav1.Duration{Duration: 5 * time.Second}},
+		{Metric: examplev1alpha1.AssertEstablishedLatency, Percentile: 50, Max: &metav1.Duration{Duration: 5 * time.Second}},
+		{Metric: examplev1alpha1.AssertInstanceCreateLatency, Max: &metav1.Duration{Duration: time.Second}},
+		{Metric: examplev1alpha1.AssertErrorRate, MaxPercent: &maxPercent},
+		{Name: "fast", Metric: examplev1alpha1.AssertAllEstablished, Max: &metav1.Duration{Duration: time.Minute}},
+	}
+	obs := Observations{
+		Established:   seconds(1, 2, 3, 4, 10),
+		Requests:      2000,
+		Errors:        2,
+		Desired:       2,
+		FirstCreate:   start,
+		EstablishedAt: []time.Time{start.Add(10 * time.Second)},
+		Now:           start.Add(30 * time.Second),
+	}
+
+	want := map[string]examplev1alpha1.AssertionState{
+		"EstablishedLatencyP99":    examplev1alpha1.AssertionFailed,
+		"EstablishedLatencyP50":    examplev1alpha1.AssertionPassed,
+		"InstanceCreateLatencyP99": examplev1alpha1.AssertionPending,
+		"ErrorRate":                examplev1alpha1.AssertionPassed,
+		"fast":                     examplev1alpha1.AssertionPending,
+	}
+	check := func(results []examplev1alpha1.AssertionStatus) {
+		t.Helper()
+		if len(results) != len(want) {
+			t.Fatalf("expected %d results, got %+v", len(want), results)
+		}
+		for _, result := range results {
+			if result.State != want[result.Name] {
+				t.Errorf("%s: expected %s, got %s (%s)", result.Name, want[result.Name], result.State, result.Message)
+			}
+		}
+	}
+	if err := Validate(assertions); err != nil {
+		t.Fatal(err)
+	}
+	check(Evaluate(assertions, obs))
+
+	// The deadline of AllEstablished passes before the second CRD is established
+	obs.Now = start.Add(2 * time.Minute)
+	want["fast"] = examplev1alpha1.AssertionFailed
+	check(Evaluate(assertions, obs))
+
+	// Once the run is over nothing is pending anymore
+	obs.Final = true
+	want["InstanceCreateLatencyP99"] = examplev1alpha1.AssertionFailed
+	results := Evaluate(assertions, obs)
+	check(results)
+	if status, reason, _ := Verdict(results); status != metav1.ConditionFalse || reason != "AssertionsFailed" {
+		t.Errorf("expected a failed verdict, got %s %s", status, reason)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	limit := &metav1.Duration{Duration: time.Second}
+	for name, assertions := range map[string][]examplev1alpha1.Assertion{
+		"missing max":        {{Metric: examplev1alpha1.AssertEstablishedLatency}},
+		"missing maxPercent": {{Metric: examplev1alpha1.AssertErrorRate, Max: limit}},
+		"duplicate name": {
+			{Metric: examplev1alpha1.AssertEstablishedLatency, Max: limit},
+			{Metric: examplev1alpha1.AssertEstablishedLatency, Percentile: 99, Max: limit},
+		},
+	} {
+		if err := Validate(assertions); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// ReservoirSize is the number of values a Reservoir keeps
+const ReservoirSize = 1024
+
+// Reservoir keeps a uniform random sample of at most ReservoirSize of the values added to it,
+// along with their count and maximum, so that the samples of a run that keeps loading the
+// cluster take bounded memory and time to summarise. The zero value is an empty reservoir.
+type Reservoir[T ~int64] struct {
+	count  int64
+	max    T
+	values []T
+}
+
+// Add adds value to the reservoir. Once it is full, value replaces a kept value with the
+// probability that keeps every value added so far equally likely to be kept.
+func (r *Reservoir[T]) Add(value T) {
+	r.count++
+	if r.count == 1 || value > r.max {
+		r.max = value
+	}
+	if len(r.values) < ReservoirSize {
+		r.values = append(r.values, value)
+		return
+	}
+	if i := rand.Int63n(r.count); i < ReservoirSize {
+		r.values[i] = value
+	}
+}
+
+// Count returns the number of values added to the reservoir
+func (r *Reservoir[T]) Count() int64 {
+	return r.count
+}
+
+// Max returns the largest value added to the reservoir, or zero when it is empty
+func (r *Reservoir[T]) Max() T {
+	return r.max
+}
+
+// Sorted returns an ascending copy of the values kept by the reservoir
+func (r *Reservoir[T]) Sorted() []T {
+	sorted := append([]T(nil), r.values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// SummarizeReservoir returns the Summary of the durations kept by r. Its count is the number
+// of durations added to r, its percentiles are estimated from those kept.
+func SummarizeReservoir(r *Reservoir[time.Duration]) Summary {
+	summary := Summarize(r.values)
+	summary.Count = int(r.count)
+	return summary
+}
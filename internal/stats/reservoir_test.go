@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservoirKeepsABoundedSample(t *testing.T) {
+	var reservoir Reservoir[time.Duration]
+	if summary := SummarizeReservoir(&reservoir); summary.Count != 0 || summary.P99 != 0 {
+		t.Errorf("expected an empty summary, got %+v", summary)
+	}
+
+	const added = 100 * ReservoirSize
+	for i := 1; i <= added; i++ {
+		reservoir.Add(time.Duration(i) * time.Millisecond)
+	}
+	if reservoir.Count() != added {
+		t.Errorf("expected %d values, got %d", added, reservoir.Count())
+	}
+	if kept := len(reservoir.Sorted()); kept != ReservoirSize {
+		t.Errorf("expected %d values kept, got %d", ReservoirSize, kept)
+	}
+	if reservoir.Max() != added*time.Millisecond {
+		t.Errorf("expected a maximum of %v, got %v", added*time.Millisecond, reservoir.Max())
+	}
+
+	summary := SummarizeReservoir(&reservoir)
+	if summary.Count != added {
+		t.Errorf("expected a count of %d, got %d", added, summary.Count)
+	}
+	// The kept values are a uniform sample, so their median is close to that of every value
+	if median := added / 2 * time.Millisecond; summary.P50 < median*8/10 || summary.P50 > median*12/10 {
+		t.Errorf("expected a median close to %v, got %v", median, summary.P50)
+	}
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// MannWhitneyU returns the U statistic of x against y and the two-sided p-value of the
+// Mann-Whitney U test that both come from the same distribution. The p-value uses the
+// normal approximation with tie and continuity corrections, and is 1 when either sample
+// is empty or every value is the same.
+func MannWhitneyU(x, y []float64) (u, p float64) {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if len(x) == 0 || len(y) == 0 {
+		return 0, 1
+	}
+
+	type ranked struct {
+		value float64
+		fromX bool
+	}
+	all := make([]ranked, 0, len(x)+len(y))
+	for _, v := range x {
+		all = append(all, ranked{value: v, fromX: true})
+	}
+	for _, v := range y {
+		all = append(all, ranked{value: v})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Tied values share the average of their ranks
+	rankSumX, tieTerm := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromX {
+				rankSumX += rank
+			}
+		}
+		ties := float64(j - i)
+		tieTerm += ties*ties*ties - ties
+		i = j
+	}
+
+	n := n1 + n2
+	u = rankSumX - n1*(n1+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / math.Sqrt(variance)
+	return u, math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// TwoProportionP returns the two-sided p-value of the z-test that x1 of n1 and x2 of n2
+// trials succeeding come from the same proportion. It is 1 when either has no trials or
+// the pooled proportion is 0 or 1.
+func TwoProportionP(x1, n1, x2, n2 int64) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	pooled := float64(x1+x2) / float64(n1+n2)
+	variance := pooled * (1 - pooled) * (1/float64(n1) + 1/float64(n2))
+	if variance <= 0 {
+		return 1
+	}
+	z := math.Abs(float64(x1)/float64(n1)-float64(x2)/float64(n2)) / math.Sqrt(variance)
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -0,0 +1,50 @@
+// Package stats summarises the latency samples collected during ReconTest runs.
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Summary holds the sample count and common percentiles of a set of durations
+type Summary struct {
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+}
+
+// Summarize returns the Summary of samples. samples is not modified.
+func Summarize(samples []time.Duration) Summary {
+	sorted := Sorted(samples)
+	return Summary{
+		Count: len(sorted),
+		P50:   Percentile(sorted, 50),
+		P90:   Percentile(sorted, 90),
+		P99:   Percentile(sorted, 99),
+	}
+}
+
+// Sorted returns an ascending copy of samples
+func Sorted(samples []time.Duration) []time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// Percentile returns the p-th percentile (0 < p <= 100) of sorted using the
+// nearest-rank method, or zero when there are no samples
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	summary := Summarize(samples)
+	if summary.Count != 100 {
+		t.Fatalf("expected 100 samples, got %d", summary.Count)
+	}
+	for _, tc := range []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"p50", summary.P50, 50 * time.Millisecond},
+		{"p90", summary.P90, 90 * time.Millisecond},
+		{"p99", summary.P99, 99 * time.Millisecond},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, tc.got)
+		}
+	}
+
+	if samples[0] != 100*time.Millisecond {
+		t.Errorf("Summarize must not reorder its input")
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("expected zero percentile for no samples, got %v", got)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	// U=5 against a mean of 32 and a tie-corrected standard deviation of 9.515 gives z=2.785
+	x := []float64{1.1, 2.3, 2.3, 3.5, 4.2, 5.0, 5.1, 6.7}
+	y := []float64{4.9, 5.5, 6.1, 6.8, 7.2, 7.9, 8.4, 9.0}
+	u, p := MannWhitneyU(x, y)
+	if u != 5 {
+		t.Errorf("expected U=5, got %v", u)
+	}
+	if math.Abs(p-0.00535) > 1e-4 {
+		t.Errorf("expected p=0.00535, got %v", p)
+	}
+
+	if _, p := MannWhitneyU(x, x); p != 1 {
+		t.Errorf("expected p=1 for identical samples, got %v", p)
+	}
+	if _, p := MannWhitneyU(nil, y); p != 1 {
+		t.Errorf("expected p=1 without samples, got %v", p)
+	}
+}
+
+func TestTwoProportionP(t *testing.T) {
+	if p := TwoProportionP(10, 1000, 40, 1000); p > 0.001 {
+		t.Errorf("expected a significant difference, got p=%v", p)
+	}
+	if p := TwoProportionP(10, 1000, 11, 1000); p < 0.5 {
+		t.Errorf("expected no significant difference, got p=%v", p)
+	}
+	if p := TwoProportionP(0, 1000, 0, 1000); p != 1 {
+		t.Errorf("expected p=1 without failures, got %v", p)
+	}
+}
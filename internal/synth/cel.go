@@ -0,0 +1,92 @@
+package synth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+)
+
+// ruleAttempts bounds how often a value is regenerated until it satisfies the rules of its schema
+const ruleAttempts = 64
+
+// maxCachedRules bounds the number of compiled rule sets kept across calls
+const maxCachedRules = 1024
+
+// compiledRules caches the compiled rules by the serialized schema that declares them,
+// since every custom resource of a CRD is synthesized from the same schema
+var compiledRules = struct {
+	sync.Mutex
+	bySchema map[string]*rules
+}{bySchema: map[string]*rules{}}
+
+// rules evaluates the x-kubernetes-validations rules of a schema and its children the way the API server does
+type rules struct {
+	structural *structuralschema.Structural
+	validator  *cel.Validator
+}
+
+// rulesOf returns the compiled rules of schema
+func rulesOf(schema *v1.JSONSchemaProps) (*rules, error) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	key := string(raw)
+
+	compiledRules.Lock()
+	defer compiledRules.Unlock()
+	if cached, ok := compiledRules.bySchema[key]; ok {
+		return cached, nil
+	}
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internal, nil); err != nil {
+		return nil, fmt.Errorf("converting schema: %w", err)
+	}
+	structural, err := structuralschema.NewStructural(internal)
+	if err != nil {
+		return nil, fmt.Errorf("schema is not structural: %w", err)
+	}
+	compiled := &rules{structural: structural, validator: cel.NewValidator(structural, cel.PerCallLimit)}
+
+	if len(compiledRules.bySchema) >= maxCachedRules {
+		compiledRules.bySchema = map[string]*rules{}
+	}
+	compiledRules.bySchema[key] = compiled
+	return compiled, nil
+}
+
+// satisfied reports whether value satisfies the rules. Rules that fail to compile never are.
+func (r *rules) satisfied(value interface{}) bool {
+	errs, _ := r.validator.Validate(context.Background(), nil, r.structural, value, nil, cel.RuntimeCELCostBudget)
+	return len(errs) == 0
+}
+
+// failsOnlyOneOf reports whether value fails exactly one rule, with one of failures as its error
+func (r *rules) failsOnlyOneOf(value interface{}, failures []string) bool {
+	errs, _ := r.validator.Validate(context.Background(), nil, r.structural, value, nil, cel.RuntimeCELCostBudget)
+	if len(errs) != 1 {
+		return false
+	}
+	for _, failure := range failures {
+		if errs[0].Detail == failure {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleFailure returns the error the API server reports for a value that fails rule
+func ruleFailure(rule v1.ValidationRule) string {
+	if rule.Message != "" {
+		return rule.Message
+	}
+	return "failed rule: " + strings.TrimSpace(rule.Rule)
+}
@@ -0,0 +1,368 @@
+package synth
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// invalidFormats holds a value rejected by the API server for every format it checks
+var invalidFormats = map[string]string{
+	"date":      "not-a-date",
+	"date-time": "not-a-date-time",
+	"datetime":  "not-a-date-time",
+	"uuid":      "not-a-uuid",
+	"ipv4":      "not-an-ipv4",
+	"ipv6":      "not-an-ipv6",
+	"email":     "not an email",
+	"hostname":  "not a hostname",
+	"byte":      "not base64!",
+	"duration":  "not a duration",
+}
+
+// nonMatching are tried in turn as strings that break a pattern
+var nonMatching = []string{"", "!", "~!~", "0", "a", "A-!"}
+
+// Violation describes the single constraint an invalid object breaks
+type Violation struct {
+	// Path is the path of the offending value, e.g. .spec.organization.name
+	Path string
+	// Constraint is the schema keyword that is broken, e.g. required or pattern, or
+	// x-kubernetes-validations for a rule on the size of an array
+	Constraint string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at %s", v.Constraint, v.Path)
+}
+
+// candidate is a constraint that can be broken and the change that breaks it
+type candidate struct {
+	Violation
+	apply func()
+	// failures are the errors of the x-kubernetes-validations rules the change may break one of,
+	// empty when it breaks a keyword of the schema
+	failures []string
+}
+
+// InvalidObject returns a custom resource that conforms to schema except for exactly one
+// constraint, chosen deterministically from seed among all the constraints of schema that
+// can be broken without failing one of its x-kubernetes-validations rules as well. Emptying
+// an array without a minItems breaks only the rule on its size, when it has one.
+// Every value below the root has at least its type to break, so an error is only
+// returned for schemas Object cannot satisfy, whose root has no properties, or whose
+// rules fail on any change.
+func InvalidObject(schema *v1.JSONSchemaProps, seed int64) (*unstructured.Unstructured, Violation, error) {
+	s := newSynthesizer(seed)
+	obj, err := s.object(schema)
+	if err != nil {
+		return nil, Violation{}, err
+	}
+
+	// A change that fails a x-kubernetes-validations rule, or its evaluation, breaks a second constraint
+	var rules *rules
+	if hasRules(schema) {
+		if rules, err = rulesOf(schema); err != nil {
+			return nil, Violation{}, err
+		}
+	}
+
+	candidates := s.violations(schema, obj.Object, "", nil)
+	for _, i := range s.rand.Perm(len(candidates)) {
+		// Every candidate is tried on a copy, since the changes of the others cannot be undone
+		broken := runtime.DeepCopyJSON(obj.Object)
+		chosen := s.violations(schema, broken, "", nil)[i]
+		chosen.apply()
+		if len(chosen.failures) > 0 && rules.failsOnlyOneOf(broken, chosen.failures) ||
+			len(chosen.failures) == 0 && (rules == nil || rules.satisfied(broken)) {
+			return &unstructured.Unstructured{Object: broken}, chosen.Violation, nil
+		}
+	}
+	return nil, Violation{}, fmt.Errorf("schema has no constraint to break on its own")
+}
+
+// hasRules reports whether schema or any of its children has x-kubernetes-validations rules
+func hasRules(schema *v1.JSONSchemaProps) bool {
+	if len(schema.XValidations) > 0 {
+		return true
+	}
+	for name := range schema.Properties {
+		prop := schema.Properties[name]
+		if hasRules(&prop) {
+			return true
+		}
+	}
+	if schema.Items != nil && schema.Items.Schema != nil && hasRules(schema.Items.Schema) {
+		return true
+	}
+	return schema.AdditionalProperties != nil && schema.AdditionalProperties.Schema != nil &&
+		hasRules(schema.AdditionalProperties.Schema)
+}
+
+// violations returns every constraint of schema that value can be changed to break on its own.
+// set replaces value in its parent and is nil for the root.
+func (s *synthesizer) violations(schema *v1.JSONSchemaProps, value interface{}, path string,
+	set func(interface{})) []candidate {
+	var candidates []candidate
+	add := func(constraint string, apply func()) {
+		candidates = append(candidates, candidate{Violation: Violation{Path: path, Constraint: constraint}, apply: apply})
+	}
+
+	// A value of the wrong type is also outside any enum, so enums are only broken by value
+	if set != nil && len(schema.Enum) == 0 {
+		if wrong, ok := wrongType(schema); ok {
+			add("type", func() { set(wrong) })
+		}
+	}
+	if len(schema.Enum) > 0 {
+		if outside, ok := notInEnum(schema); ok {
+			add("enum", func() { set(outside) })
+		}
+		// Enum values are scalars, nothing below them can break
+		return candidates
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		candidates = append(candidates, s.objectViolations(schema, typed, path)...)
+	case []interface{}:
+		candidates = append(candidates, s.arrayViolations(schema, typed, path, set)...)
+	case string:
+		for _, c := range s.stringViolations(schema) {
+			broken := c.value
+			add(c.constraint, func() { set(broken) })
+		}
+	case int64, float64:
+		for _, c := range numberViolations(schema, value) {
+			broken := c.value
+			add(c.constraint, func() { set(broken) })
+		}
+	}
+	return candidates
+}
+
+// objectViolations returns the constraints of an object and of its properties
+func (s *synthesizer) objectViolations(schema *v1.JSONSchemaProps, obj map[string]interface{},
+	path string) []candidate {
+	var candidates []candidate
+
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+		name := name
+		candidates = append(candidates, candidate{
+			Violation: Violation{Path: path + "." + name, Constraint: "required"},
+			apply:     func() { delete(obj, name) },
+		})
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties == nil || schema.AdditionalProperties.Schema == nil {
+				continue
+			}
+			prop = *schema.AdditionalProperties.Schema
+		}
+		name := name
+		candidates = append(candidates, s.violations(&prop, obj[name], path+"."+name,
+			func(v interface{}) { obj[name] = v })...)
+	}
+	return candidates
+}
+
+// arrayViolations returns the constraints of an array and of its items
+func (s *synthesizer) arrayViolations(schema *v1.JSONSchemaProps, items []interface{}, path string,
+	set func(interface{})) []candidate {
+	var candidates []candidate
+	add := func(constraint string, apply func()) {
+		candidates = append(candidates, candidate{Violation: Violation{Path: path, Constraint: constraint}, apply: apply})
+	}
+
+	if schema.MinItems != nil && *schema.MinItems > 0 && int64(len(items)) >= *schema.MinItems {
+		add("minItems", func() { set(items[:*schema.MinItems-1]) })
+	}
+	// An empty array breaks no keyword, only a rule on the size of the array such as size(self) >= 1
+	if len(schema.XValidations) > 0 && schema.MinItems == nil && len(items) > 0 {
+		failures := make([]string, 0, len(schema.XValidations))
+		for _, rule := range schema.XValidations {
+			failures = append(failures, ruleFailure(rule))
+		}
+		candidates = append(candidates, candidate{
+			Violation: Violation{Path: path, Constraint: "x-kubernetes-validations"},
+			apply:     func() { set([]interface{}{}) },
+			failures:  failures,
+		})
+	}
+	// Repeating an item would also break the uniqueness of set and map lists
+	unique := schema.XListType != nil && *schema.XListType != "atomic"
+	if schema.MaxItems != nil && len(items) > 0 && !unique {
+		add("maxItems", func() {
+			longer := append([]interface{}{}, items...)
+			for int64(len(longer)) <= *schema.MaxItems {
+				longer = append(longer, items[0])
+			}
+			set(longer)
+		})
+	}
+
+	if schema.Items == nil || schema.Items.Schema == nil {
+		return candidates
+	}
+	for i := range items {
+		i := i
+		candidates = append(candidates, s.violations(schema.Items.Schema, items[i], fmt.Sprintf("%s[%d]", path, i),
+			func(v interface{}) { items[i] = v })...)
+	}
+	return candidates
+}
+
+// brokenValue is a replacement value that breaks constraint
+type brokenValue struct {
+	constraint string
+	value      interface{}
+}
+
+// stringViolations returns replacements that break exactly one constraint of a string
+func (s *synthesizer) stringViolations(schema *v1.JSONSchemaProps) []brokenValue {
+	var broken []brokenValue
+
+	fitsLength := func(value string) bool {
+		return (schema.MinLength == nil || int64(len(value)) >= *schema.MinLength) &&
+			(schema.MaxLength == nil || int64(len(value)) <= *schema.MaxLength)
+	}
+
+	if schema.Pattern != "" {
+		// A pattern the API server accepts always compiles
+		re, err := regexp.Compile(schema.Pattern)
+		if err == nil {
+			for _, value := range nonMatching {
+				if !re.MatchString(value) && fitsLength(value) {
+					broken = append(broken, brokenValue{constraint: "pattern", value: value})
+					break
+				}
+			}
+		}
+		// Any other change risks breaking the pattern as well
+		return broken
+	}
+
+	if invalid, ok := invalidFormats[schema.Format]; ok {
+		if fitsLength(invalid) {
+			broken = append(broken, brokenValue{constraint: "format", value: invalid})
+		}
+		return broken
+	}
+
+	if schema.MinLength != nil && *schema.MinLength > 0 {
+		shorter := s.word(int(*schema.MinLength - 1))
+		broken = append(broken, brokenValue{constraint: "minLength", value: shorter})
+	}
+	if schema.MaxLength != nil {
+		longer := s.word(int(*schema.MaxLength + 1))
+		broken = append(broken, brokenValue{constraint: "maxLength", value: longer})
+	}
+	return broken
+}
+
+// numberViolations returns replacements that break exactly one bound of an integer or number
+func numberViolations(schema *v1.JSONSchemaProps, value interface{}) []brokenValue {
+	var broken []brokenValue
+
+	// Moving past a bound could land on a value that is not a multiple either
+	if schema.MultipleOf != nil {
+		if number, ok := value.(float64); ok {
+			off := number + *schema.MultipleOf/2
+			if lo, hi := bounds(schema); off >= lo && off <= hi {
+				broken = append(broken, brokenValue{constraint: "multipleOf", value: off})
+			}
+		}
+		return broken
+	}
+
+	if schema.Minimum != nil {
+		below := *schema.Minimum - 1
+		if schema.ExclusiveMinimum {
+			below = *schema.Minimum
+		}
+		broken = append(broken, brokenValue{constraint: "minimum", value: asType(schema, below)})
+	}
+	if schema.Maximum != nil {
+		above := *schema.Maximum + 1
+		if schema.ExclusiveMaximum {
+			above = *schema.Maximum
+		}
+		broken = append(broken, brokenValue{constraint: "maximum", value: asType(schema, above)})
+	}
+	return broken
+}
+
+// asType returns number as an int64 for integer schemas
+func asType(schema *v1.JSONSchemaProps, number float64) interface{} {
+	if schema.Type == "integer" {
+		return int64(number)
+	}
+	return number
+}
+
+// wrongType returns a value whose type schema does not allow
+func wrongType(schema *v1.JSONSchemaProps) (interface{}, bool) {
+	switch schema.Type {
+	case "string":
+		return true, true
+	case "object", "array", "integer", "number", "boolean":
+		return "wrong-type", true
+	}
+	// Untyped values accept anything
+	return nil, false
+}
+
+// notInEnum returns a value of the type of schema that is not one of its enum values
+func notInEnum(schema *v1.JSONSchemaProps) (interface{}, bool) {
+	values := map[string]bool{}
+	for _, raw := range schema.Enum {
+		values[string(raw.Raw)] = true
+	}
+
+	var candidate interface{}
+	switch schema.Type {
+	case "string":
+		candidate = "not-in-enum"
+	case "integer":
+		candidate = int64(-1)
+	case "number":
+		candidate = float64(-1.5)
+	default:
+		return nil, false
+	}
+	for i := 0; i < len(schema.Enum)+1; i++ {
+		raw, err := json.Marshal(candidate)
+		if err != nil {
+			return nil, false
+		}
+		if !values[string(raw)] {
+			return candidate, true
+		}
+		switch typed := candidate.(type) {
+		case string:
+			candidate = typed + "x"
+		case int64:
+			candidate = typed - 1
+		case float64:
+			candidate = typed - 1
+		}
+	}
+	return nil, false
+}
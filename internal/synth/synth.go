@@ -0,0 +1,482 @@
+// Package synth synthesizes data that conforms to an OpenAPI v3 schema, so that
+// custom resources of the generated CRDs can be created with realistic content.
+// The same schema and seed always produce the same data. InvalidObject breaks
+// exactly one constraint of the schema to exercise the rejection paths of the
+// API server.
+package synth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Bounds used for properties whose schema leaves them open
+const (
+	defaultSpan        = 1000
+	defaultMaxItems    = 3
+	defaultStringLen   = 12
+	maxPatternRepeats  = 3
+	patternAttempts    = 32
+	additionalPropKeys = 2
+)
+
+// epoch and dateSpan bound the dates and times that are generated
+var (
+	epoch    = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dateSpan = int64(30 * 365 * 24 * time.Hour / time.Second)
+)
+
+const letters = "abcdefghijklmnopqrstuvwxyz"
+
+// Value returns a value conforming to schema, derived deterministically from seed.
+// Objects are map[string]interface{}, arrays []interface{}, and scalars string,
+// int64, float64 or bool, so the result can be used as unstructured content.
+func Value(schema *v1.JSONSchemaProps, seed int64) (interface{}, error) {
+	return newSynthesizer(seed).value(schema)
+}
+
+// Object returns a custom resource whose content conforms to schema, derived
+// deterministically from seed. The caller sets its apiVersion, kind and metadata.
+func Object(schema *v1.JSONSchemaProps, seed int64) (*unstructured.Unstructured, error) {
+	return newSynthesizer(seed).object(schema)
+}
+
+func newSynthesizer(seed int64) *synthesizer {
+	return &synthesizer{
+		rand:     rand.New(rand.NewSource(seed)),
+		patterns: map[string]*syntax.Regexp{},
+		repeats:  maxPatternRepeats,
+	}
+}
+
+// synthesizer holds the random source and parsed patterns of one Value call
+type synthesizer struct {
+	rand     *rand.Rand
+	patterns map[string]*syntax.Regexp
+	// repeats bounds the repetitions of the unbounded parts of the pattern being matched
+	repeats int
+}
+
+// value returns a value for schema, regenerating it until it satisfies the x-kubernetes-validations rules of schema
+func (s *synthesizer) value(schema *v1.JSONSchemaProps) (interface{}, error) {
+	if len(schema.XValidations) == 0 {
+		return s.generate(schema)
+	}
+
+	rules, err := rulesOf(schema)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < ruleAttempts; attempt++ {
+		value, err := s.generate(schema)
+		if err != nil {
+			return nil, err
+		}
+		if rules.satisfied(value) {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("no value satisfies the x-kubernetes-validations rules after %d attempts", ruleAttempts)
+}
+
+// generate returns a value for schema, ignoring its own x-kubernetes-validations rules
+func (s *synthesizer) generate(schema *v1.JSONSchemaProps) (interface{}, error) {
+	if len(schema.Enum) > 0 {
+		var value interface{}
+		if err := json.Unmarshal(schema.Enum[s.rand.Intn(len(schema.Enum))].Raw, &value); err != nil {
+			return nil, fmt.Errorf("invalid enum value: %w", err)
+		}
+		if number, ok := value.(float64); ok && schema.Type == "integer" {
+			return int64(number), nil
+		}
+		return value, nil
+	}
+
+	switch schema.Type {
+	case "object":
+		return s.properties(schema)
+	case "array":
+		return s.array(schema)
+	case "string":
+		return s.string(schema)
+	case "integer":
+		return s.integer(schema), nil
+	case "number":
+		return s.number(schema), nil
+	case "boolean":
+		return s.rand.Intn(2) == 1, nil
+	}
+
+	switch {
+	case schema.XIntOrString:
+		return s.integer(schema), nil
+	case schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields:
+		return map[string]interface{}{}, nil
+	}
+	return nil, fmt.Errorf("unsupported schema type %q", schema.Type)
+}
+
+// object returns the root schema content as an unstructured object
+func (s *synthesizer) object(schema *v1.JSONSchemaProps) (*unstructured.Unstructured, error) {
+	if schema.Type != "object" {
+		return nil, fmt.Errorf("root schema must be of type object, got %q", schema.Type)
+	}
+	content, err := s.properties(schema)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+// properties returns an object with every declared property and a few additional ones when allowed
+func (s *synthesizer) properties(schema *v1.JSONSchemaProps) (map[string]interface{}, error) {
+	obj := make(map[string]interface{}, len(schema.Properties))
+
+	// Map iteration order is random, walking the sorted names keeps the output deterministic
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		value, err := s.value(&prop)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		obj[name] = value
+	}
+
+	if additional := schema.AdditionalProperties; additional != nil && (additional.Schema != nil || additional.Allows) {
+		for i := 0; i < additionalPropKeys; i++ {
+			var value interface{} = s.word(defaultStringLen)
+			if additional.Schema != nil {
+				var err error
+				if value, err = s.value(additional.Schema); err != nil {
+					return nil, fmt.Errorf("additionalProperties: %w", err)
+				}
+			}
+			obj[fmt.Sprintf("key%d", i)] = value
+		}
+	}
+	return obj, nil
+}
+
+// array returns an array of between MinItems and MaxItems items
+func (s *synthesizer) array(schema *v1.JSONSchemaProps) ([]interface{}, error) {
+	if schema.Items == nil || schema.Items.Schema == nil {
+		return []interface{}{}, nil
+	}
+
+	minItems, maxItems := int64(1), int64(defaultMaxItems)
+	if schema.MinItems != nil {
+		minItems = *schema.MinItems
+		maxItems = minItems + defaultMaxItems - 1
+	}
+	if schema.MaxItems != nil && *schema.MaxItems < maxItems {
+		maxItems = *schema.MaxItems
+	}
+	if minItems > maxItems {
+		minItems = maxItems
+	}
+
+	n := minItems + s.rand.Int63n(maxItems-minItems+1)
+	items := make([]interface{}, 0, n)
+	for i := int64(0); i < n; i++ {
+		item, err := s.value(schema.Items.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// string returns a string matching the pattern or format of schema, or a word, within its length bounds
+func (s *synthesizer) string(schema *v1.JSONSchemaProps) (string, error) {
+	minLength, maxLength := int64(0), int64(math.MaxInt64)
+	if schema.MinLength != nil {
+		minLength = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		maxLength = *schema.MaxLength
+	}
+	if schema.Pattern != "" {
+		return s.matching(schema.Pattern, minLength, maxLength)
+	}
+	if schema.Format != "" {
+		if value, ok := s.formatted(schema.Format); ok {
+			if !withinLength(value, minLength, maxLength) {
+				return "", fmt.Errorf("%s values do not fit in %d to %d characters",
+					schema.Format, minLength, maxLength)
+			}
+			return value, nil
+		}
+	}
+
+	length := int64(defaultStringLen)
+	if schema.MaxLength != nil && *schema.MaxLength < length {
+		length = *schema.MaxLength
+	}
+	if schema.MinLength != nil && *schema.MinLength > length {
+		length = *schema.MinLength
+	}
+	return s.word(int(length)), nil
+}
+
+// formatted returns a string in the given format, and false for formats it does not know
+func (s *synthesizer) formatted(format string) (string, bool) {
+	at := epoch.Add(time.Duration(s.rand.Int63n(dateSpan)) * time.Second)
+	switch format {
+	case "date":
+		return at.Format("2006-01-02"), true
+	case "date-time", "datetime":
+		return at.Format(time.RFC3339), true
+	case "uuid":
+		b := make([]byte, 16)
+		s.rand.Read(b)
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), true
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", s.rand.Intn(256), s.rand.Intn(256), 1+s.rand.Intn(254)), true
+	case "ipv6":
+		return fmt.Sprintf("fd00::%x:%x", s.rand.Intn(0x10000), s.rand.Intn(0x10000)), true
+	case "email":
+		return fmt.Sprintf("%s@%s.example.com", s.word(8), s.word(6)), true
+	case "hostname":
+		return fmt.Sprintf("%s.example.com", s.word(8)), true
+	case "uri":
+		return fmt.Sprintf("https://%s.example.com/%s", s.word(8), s.word(6)), true
+	case "byte":
+		return "c3ludGg=", true
+	case "duration":
+		return fmt.Sprintf("%ds", 1+s.rand.Intn(3600)), true
+	}
+	return "", false
+}
+
+// withinLength reports whether value has between minLength and maxLength characters
+func withinLength(value string, minLength, maxLength int64) bool {
+	length := int64(utf8.RuneCountInString(value))
+	return length >= minLength && length <= maxLength
+}
+
+// matching returns a string matched by pattern with between minLength and maxLength characters.
+// Matches of the wrong length are drawn again with more or fewer repetitions of the unbounded
+// parts of the pattern.
+func (s *synthesizer) matching(pattern string, minLength, maxLength int64) (string, error) {
+	re, ok := s.patterns[pattern]
+	if !ok {
+		parsed, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		re = parsed.Simplify()
+		s.patterns[pattern] = re
+	}
+
+	s.repeats = maxPatternRepeats
+	for attempt := 0; attempt < patternAttempts; attempt++ {
+		b := &strings.Builder{}
+		s.match(re, b)
+		value := b.String()
+		if withinLength(value, minLength, maxLength) {
+			return value, nil
+		}
+
+		// More repetitions than there are characters to fill are never needed
+		if length := int64(utf8.RuneCountInString(value)); length < minLength && int64(s.repeats) < minLength {
+			s.repeats *= 2
+		} else if length > maxLength && s.repeats > 1 {
+			s.repeats /= 2
+		}
+	}
+	return "", fmt.Errorf("no string of %d to %d characters matching pattern %q found after %d attempts",
+		minLength, maxLength, pattern, patternAttempts)
+}
+
+// match appends to b a string matched by re
+func (s *synthesizer) match(re *syntax.Regexp, b *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(s.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(letters[s.rand.Intn(len(letters))])
+	case syntax.OpCapture:
+		s.match(re.Sub[0], b)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			s.match(sub, b)
+		}
+	case syntax.OpAlternate:
+		s.match(re.Sub[s.rand.Intn(len(re.Sub))], b)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		minRepeats, maxRepeats := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			minRepeats, maxRepeats = 0, s.repeats
+		case syntax.OpPlus:
+			minRepeats, maxRepeats = 1, s.repeats
+		case syntax.OpQuest:
+			minRepeats, maxRepeats = 0, 1
+		}
+		if maxRepeats < 0 {
+			maxRepeats = minRepeats + s.repeats
+		}
+		if maxRepeats < minRepeats {
+			maxRepeats = minRepeats
+		}
+		for n := minRepeats + s.rand.Intn(maxRepeats-minRepeats+1); n > 0; n-- {
+			s.match(re.Sub[0], b)
+		}
+	}
+	// Anchors, word boundaries and empty matches produce no characters
+}
+
+// classRune returns a rune from the [lo, hi] pairs of a character class, preferring printable ASCII
+func (s *synthesizer) classRune(ranges []rune) rune {
+	printable := make([]rune, 0, len(ranges))
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if lo < ' ' {
+			lo = ' '
+		}
+		if hi > '~' {
+			hi = '~'
+		}
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) == 0 {
+		return ranges[0]
+	}
+
+	pair := s.rand.Intn(len(printable)/2) * 2
+	lo, hi := printable[pair], printable[pair+1]
+	return lo + rune(s.rand.Intn(int(hi-lo)+1))
+}
+
+// integer returns an integer within the bounds of schema that is a multiple of MultipleOf
+func (s *synthesizer) integer(schema *v1.JSONSchemaProps) int64 {
+	lo, hi := bounds(schema)
+	min, max := toInt64(math.Ceil(lo)), toInt64(math.Floor(hi))
+	if schema.ExclusiveMinimum && float64(min) == lo && min < math.MaxInt64 {
+		min++
+	}
+	if schema.ExclusiveMaximum && float64(max) == hi && max > math.MinInt64 {
+		max--
+	}
+	if max < min {
+		max = min
+	}
+
+	// Spans are unsigned so that bounds far apart do not overflow
+	if schema.MultipleOf != nil && *schema.MultipleOf >= 1 {
+		step := toInt64(*schema.MultipleOf)
+		first := min
+		if remainder := min % step; remainder < 0 {
+			first -= remainder
+		} else if remainder > 0 {
+			if min > math.MaxInt64-(step-remainder) {
+				return min
+			}
+			first += step - remainder
+		}
+		if first > max {
+			return first
+		}
+		steps := uint64(max-first) / uint64(step)
+		return int64(uint64(first) + s.offset(steps)*uint64(step))
+	}
+	return int64(uint64(min) + s.offset(uint64(max-min)))
+}
+
+// offset returns a random integer in [0, span]
+func (s *synthesizer) offset(span uint64) uint64 {
+	if span < math.MaxInt64 {
+		return uint64(s.rand.Int63n(int64(span) + 1))
+	}
+	// At least half of the draws fall within the span
+	for {
+		if value := s.rand.Uint64(); value <= span {
+			return value
+		}
+	}
+}
+
+// toInt64 converts f to the nearest int64, saturating at the bounds of int64
+func toInt64(f float64) int64 {
+	switch {
+	case f <= math.MinInt64:
+		return math.MinInt64
+	case f >= math.MaxInt64:
+		// float64(math.MaxInt64) rounds up to 2^63, which int64 cannot hold
+		return math.MaxInt64
+	}
+	return int64(f)
+}
+
+// number returns a number within the bounds of schema that is a multiple of MultipleOf
+func (s *synthesizer) number(schema *v1.JSONSchemaProps) float64 {
+	lo, hi := bounds(schema)
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step := *schema.MultipleOf
+		first := math.Ceil(lo/step) * step
+		if schema.ExclusiveMinimum && first == lo {
+			first += step
+		}
+		steps := toInt64(math.Floor((hi - first) / step))
+		if schema.ExclusiveMaximum && first+float64(steps)*step == hi {
+			steps--
+		}
+		if steps < 0 {
+			steps = 0
+		}
+		return first + float64(s.offset(uint64(steps)))*step
+	}
+
+	// Float64 is in [0, 1) so hi is never reached, and an exclusive minimum only needs lo skipped
+	value := lo + s.rand.Float64()*(hi-lo)
+	if schema.ExclusiveMinimum && value == lo {
+		value = lo + (hi-lo)/2
+	}
+	return value
+}
+
+// bounds returns the inclusive range a number of schema is drawn from
+func bounds(schema *v1.JSONSchemaProps) (float64, float64) {
+	switch {
+	case schema.Minimum != nil && schema.Maximum != nil:
+		return *schema.Minimum, *schema.Maximum
+	case schema.Minimum != nil:
+		return *schema.Minimum, *schema.Minimum + defaultSpan
+	case schema.Maximum != nil:
+		return *schema.Maximum - defaultSpan, *schema.Maximum
+	default:
+		return 0, defaultSpan
+	}
+}
+
+// word returns n random lowercase letters
+func (s *synthesizer) word(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = letters[s.rand.Intn(len(letters))]
+	}
+	return string(b)
+}
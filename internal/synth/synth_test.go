@@ -0,0 +1,200 @@
+package synth
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/cel"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/schemagen"
+)
+
+// validate returns the errors the API server reports for value under schema
+func validate(t *testing.T, schema *v1.JSONSchemaProps, value interface{}) field.ErrorList {
+	t.Helper()
+
+	internal := &apiextensions.JSONSchemaProps{}
+	if err := v1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(schema, internal, nil); err != nil {
+		t.Fatalf("converting schema: %v", err)
+	}
+	validator, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: internal})
+	if err != nil {
+		t.Fatalf("building validator: %v", err)
+	}
+	return validation.ValidateCustomResource(nil, value, validator)
+}
+
+// validateRules returns the errors the API server reports for the x-kubernetes-validations rules of schema
+func validateRules(t *testing.T, schema *v1.JSONSchemaProps, value interface{}) field.ErrorList {
+	t.Helper()
+
+	compiled, err := rulesOf(schema)
+	if err != nil {
+		t.Fatalf("compiling rules: %v", err)
+	}
+	errs, _ := compiled.validator.Validate(context.Background(), nil, compiled.structural, value, nil,
+		cel.RuntimeCELCostBudget)
+	return errs
+}
+
+// requireValid fails the test unless value passes the validation the API server applies for schema
+func requireValid(t *testing.T, schema *v1.JSONSchemaProps, value interface{}) {
+	t.Helper()
+
+	if errs := validate(t, schema, value); len(errs) > 0 {
+		t.Fatalf("value does not conform to the schema: %v", errs.ToAggregate())
+	}
+}
+
+func TestObjectConformsToComplexPreset(t *testing.T) {
+	schema := schemagen.Complex()
+	for seed := int64(0); seed < 50; seed++ {
+		obj, err := Object(schema, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requireValid(t, schema, obj.Object)
+	}
+}
+
+func TestValueConformsToGeneratedSchema(t *testing.T) {
+	schema, err := schemagen.Generate(schemagen.Params{
+		Depth: 3, FanOut: 5, ArrayNesting: 2, Enums: 3, EnumSize: 4, Patterns: 4, Formats: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for seed := int64(0); seed < 20; seed++ {
+		value, err := Value(schema, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requireValid(t, schema, value)
+	}
+}
+
+func TestValueIsDeterministic(t *testing.T) {
+	schema := schemagen.Complex()
+	first, err := Value(schema, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Value(schema, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("the same seed produced different values")
+	}
+}
+
+func TestValueWithinExtremeIntegerBounds(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+	for _, tc := range []struct {
+		name   string
+		schema v1.JSONSchemaProps
+	}{
+		{"wider than int64", v1.JSONSchemaProps{Type: "integer", Minimum: float(-8e18), Maximum: float(8e18)}},
+		{"no minimum", v1.JSONSchemaProps{Type: "integer", Maximum: float(8e18)}},
+		{"multiple of", v1.JSONSchemaProps{Type: "integer", Minimum: float(-8e18), Maximum: float(8e18),
+			MultipleOf: float(3)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				value, err := Value(&tc.schema, seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				requireValid(t, &tc.schema, value)
+			}
+		})
+	}
+}
+
+func TestValueWithinNumberBoundsOfTooManySteps(t *testing.T) {
+	float := func(f float64) *float64 { return &f }
+	// The API server cannot check multiples this large, so only the bounds are checked
+	schema := &v1.JSONSchemaProps{Type: "number", Minimum: float(-4e18), Maximum: float(4e18), MultipleOf: float(0.5)}
+	for seed := int64(0); seed < 20; seed++ {
+		value, err := Value(schema, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if number, ok := value.(float64); !ok || number < -4e18 || number > 4e18 {
+			t.Errorf("expected a number within the bounds, got %v", value)
+		}
+	}
+}
+
+func TestValueMatchesPatternWithinLength(t *testing.T) {
+	length := func(l int64) *int64 { return &l }
+	for _, tc := range []struct {
+		name   string
+		schema v1.JSONSchemaProps
+	}{
+		{"long minimum", v1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]+$", MinLength: length(20)}},
+		{"short maximum", v1.JSONSchemaProps{Type: "string", Pattern: "^[a-z]*[0-9]*$", MaxLength: length(2)}},
+		{"both", v1.JSONSchemaProps{Type: "string", Pattern: "^(ab)+$", MinLength: length(6), MaxLength: length(8)}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				value, err := Value(&tc.schema, seed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				requireValid(t, &tc.schema, value)
+			}
+		})
+	}
+
+	for _, impossible := range []*v1.JSONSchemaProps{
+		{Type: "string", Pattern: "^abc$", MaxLength: length(2)},
+		{Type: "string", Format: "uuid", MaxLength: length(10)},
+	} {
+		if _, err := Value(impossible, 1); err == nil {
+			t.Errorf("expected an error for %+v, which no string within the length bounds matches", impossible)
+		}
+	}
+}
+
+func TestInvalidObjectBreaksExactlyOneConstraint(t *testing.T) {
+	generated, err := schemagen.Generate(schemagen.Params{
+		Depth: 3, FanOut: 4, ArrayNesting: 1, Enums: 2, EnumSize: 3, Patterns: 2, Formats: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withRules := schemagen.Complex()
+	if _, err := schemagen.AddValidations(withRules, schemagen.CELParams{
+		Density: 100, RulesPerNode: 4, Cost: schemagen.CELCostHigh, MaxItems: 16,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	constraints := map[string]bool{}
+	for _, schema := range []*v1.JSONSchemaProps{schemagen.Complex(), generated, withRules} {
+		for seed := int64(0); seed < 200; seed++ {
+			obj, violation, err := InvalidObject(schema, seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			errs := append(validate(t, schema, obj.Object), validateRules(t, schema, obj.Object)...)
+			if len(errs) != 1 {
+				t.Fatalf("breaking %s: expected exactly one error, got %v", violation, errs.ToAggregate())
+			}
+			constraints[violation.Constraint] = true
+		}
+	}
+
+	for _, constraint := range []string{"type", "required", "enum", "pattern", "format", "minimum", "maximum",
+		"x-kubernetes-validations"} {
+		if !constraints[constraint] {
+			t.Errorf("no seed broke the %s constraint", constraint)
+		}
+	}
+}
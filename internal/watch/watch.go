@@ -0,0 +1,177 @@
+// Package watch follows a kind the way the controllers of a cluster do, through raw
+// watches or informers, and reports every event they deliver and every watch that ends.
+// Each CRD adds a watch cache to the API server, and every controller watching a kind
+// adds to its fan-out, so many watchers per kind reproduce the load of busy clusters.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+// RetryDelay is how long a watcher waits before reopening a watch that failed
+var RetryDelay = time.Second
+
+// Reason is why a watch ended
+type Reason string
+
+// Reasons a watch ends for
+const (
+	// Closed watches were closed by the API server, usually at the end of their timeout
+	Closed Reason = "Closed"
+	// Expired watches asked for a resource version the API server no longer has, "too old
+	// resource version", and must relist
+	Expired Reason = "Expired"
+	// Failed watches could not be opened or ended with another error
+	Failed Reason = "Failed"
+)
+
+// Handler receives what a watcher sees. Its functions are called from one goroutine per watcher.
+type Handler struct {
+	// Event is called for every object delivered, deleted or not
+	Event func(obj *unstructured.Unstructured, deleted bool)
+	// Ended is called for every watch that ended before the watcher stopped, and is reopened
+	Ended func(reason Reason, err error)
+}
+
+// Run follows the objects of gvr in every namespace in mode until ctx is done
+func Run(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource,
+	mode examplev1alpha1.WatchMode, handler Handler) {
+	if mode == examplev1alpha1.WatchInformer {
+		runInformer(ctx, client, gvr, handler)
+		return
+	}
+	runRaw(ctx, client.Resource(gvr), handler)
+}
+
+// runRaw lists the objects of resource once and watches them from the resource version of the
+// list, resuming from the last resource version seen whenever the watch ends. It only lists
+// again when that resource version expired.
+func runRaw(ctx context.Context, resource dynamic.NamespaceableResourceInterface, handler Handler) {
+	resourceVersion := ""
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			list, err := resource.List(ctx, metav1.ListOptions{Limit: 1})
+			if err != nil {
+				if ctx.Err() == nil {
+					handler.Ended(Failed, err)
+					sleep(ctx, RetryDelay)
+				}
+				continue
+			}
+			resourceVersion = list.GetResourceVersion()
+		}
+
+		w, err := resource.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion, AllowWatchBookmarks: true})
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if isExpired(err) {
+				handler.Ended(Expired, err)
+				resourceVersion = ""
+				continue
+			}
+			handler.Ended(Failed, err)
+			sleep(ctx, RetryDelay)
+			continue
+		}
+
+		var reason Reason
+		reason, resourceVersion, err = drain(w, resourceVersion, handler)
+		if ctx.Err() == nil {
+			handler.Ended(reason, err)
+		}
+		if reason == Failed {
+			sleep(ctx, RetryDelay)
+		}
+	}
+}
+
+// drain hands the events of w to handler until w ends, and returns why it ended and the last
+// resource version it saw, empty when that resource version expired
+func drain(w apiwatch.Interface, resourceVersion string, handler Handler) (Reason, string, error) {
+	defer w.Stop()
+
+	for event := range w.ResultChan() {
+		if event.Type == apiwatch.Error {
+			err := apierrors.FromObject(event.Object)
+			if isExpired(err) {
+				return Expired, "", err
+			}
+			return Failed, resourceVersion, err
+		}
+
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		resourceVersion = obj.GetResourceVersion()
+		if event.Type != apiwatch.Bookmark {
+			handler.Event(obj, event.Type == apiwatch.Deleted)
+		}
+	}
+	return Closed, resourceVersion, nil
+}
+
+// runInformer runs a dynamic informer with its own cache on gvr. Informers reopen the watches
+// the API server closes on their own, so only expired and failed watches are reported.
+func runInformer(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, handler Handler) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(client, gvr, metav1.NamespaceAll, 0,
+		cache.Indexers{}, nil).Informer()
+	_ = informer.SetWatchErrorHandler(func(_ *cache.Reflector, err error) {
+		switch {
+		case isExpired(err):
+			handler.Ended(Expired, err)
+		case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+			handler.Ended(Closed, err)
+		default:
+			handler.Ended(Failed, err)
+		}
+	})
+
+	event := func(obj interface{}, deleted bool) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			handler.Event(u, deleted)
+		}
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { event(obj, false) },
+		UpdateFunc: func(_, obj interface{}) { event(obj, false) },
+		DeleteFunc: func(obj interface{}) { event(obj, true) },
+	})
+	informer.Run(ctx.Done())
+}
+
+// isExpired reports whether err tells that a resource version is too old to watch from
+func isExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
+)
+
+func TestRawWatchRelistsWhenExpired(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "example.anirudh.io", Version: "v1alpha1", Resource: "complexrecontests1"}
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "ComplexRecontest1List"})
+
+	// The first watch expires at once, the second delivers an object and is closed
+	var mu sync.Mutex
+	watches := 0
+	client.PrependWatchReactor("*", func(clienttesting.Action) (bool, apiwatch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		watches++
+		w := apiwatch.NewFake()
+		go func(n int) {
+			if n == 1 {
+				status := apierrors.NewResourceExpired("too old resource version").Status()
+				w.Error(&status)
+				return
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetName("probe")
+			obj.SetResourceVersion("7")
+			w.Add(obj)
+			w.Stop()
+		}(watches)
+		return true, w, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var events []string
+	var reasons []Reason
+	Run(ctx, client, gvr, examplev1alpha1.WatchRaw, Handler{
+		Event: func(obj *unstructured.Unstructured, deleted bool) {
+			events = append(events, obj.GetName())
+		},
+		Ended: func(reason Reason, err error) {
+			reasons = append(reasons, reason)
+			if len(reasons) == 2 {
+				cancel()
+			}
+		},
+	})
+
+	if len(events) != 1 || events[0] != "probe" {
+		t.Errorf("expected the probe event, got %v", events)
+	}
+	if len(reasons) != 2 || reasons[0] != Expired || reasons[1] != Closed {
+		t.Errorf("expected an expired then a closed watch, got %v", reasons)
+	}
+
+	lists := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" {
+			lists++
+		}
+	}
+	if lists != 2 {
+		t.Errorf("expected a relist after the expired watch, got %d lists", lists)
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	if !isExpired(apierrors.NewResourceExpired("too old resource version")) {
+		t.Errorf("expected an expired resource version to be recognised")
+	}
+	if !isExpired(apierrors.NewGone("gone")) {
+		t.Errorf("expected a gone resource version to be recognised")
+	}
+	if isExpired(apierrors.NewNotFound(schema.GroupResource{}, "a")) {
+		t.Errorf("expected not found not to be an expired resource version")
+	}
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"os"
+	"strings"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -27,6 +30,9 @@ import (
 
 	examplev1alpha1 "github.com/anirudhAgniRedhat/recon-test-operator/api/v1alpha1"
 	"github.com/anirudhAgniRedhat/recon-test-operator/controllers"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/cli"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/conversion"
+	"github.com/anirudhAgniRedhat/recon-test-operator/internal/crdgen"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -49,14 +55,57 @@ func init() {
 }
 
 func main() {
+	// The generate subcommand writes the CRDs of a run to disk without starting the manager
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := cli.Generate(os.Args[2:], os.Stdout); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	// The compare subcommand compares the reports of two runs and fails when the second regressed
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		if err := cli.Compare(os.Args[2:], os.Stdout); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(0)
+			}
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
+	var conversionService string
+	var conversionURL string
+	var conversionCABundle string
+	var reportDir string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 20,
+		"The QPS of the client talking to the API server. ReconTest rates above it are capped by it.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 30, "The burst of the client talking to the API server.")
+	flag.StringVar(&conversionService, "conversion-webhook-service", "",
+		"The <namespace>/<name> of the service in front of the webhook server. "+
+			"Enables the conversion webhook of the generated CRDs.")
+	flag.StringVar(&conversionURL, "conversion-webhook-url", "",
+		"The base URL the API server reaches the webhook server on, when the operator runs outside the cluster. "+
+			"Enables the conversion webhook of the generated CRDs.")
+	flag.StringVar(&conversionCABundle, "conversion-ca-bundle", "/tmp/k8s-webhook-server/serving-certs/ca.crt",
+		"A PEM file with the CA bundle that signed the serving certificate of the webhook server. "+
+			"It is read again on every pass of a run, so a rotated bundle reaches the existing CRDs.")
+	flag.StringVar(&reportDir, "report-dir", "",
+		"The directory the reports of ReconTests are written to when they ask for a Directory destination.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -65,7 +114,11 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = float32(kubeAPIQPS)
+	restConfig.Burst = kubeAPIBurst
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
@@ -94,9 +147,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	var conversionWebhook *crdgen.WebhookConfig
+	if conversionService != "" || conversionURL != "" {
+		conversionWebhook, err = webhookConfig(conversionService, conversionURL, conversionCABundle)
+		if err != nil {
+			setupLog.Error(err, "unable to configure the conversion webhook")
+			os.Exit(1)
+		}
+		handler := conversion.NewHandler(mgr.GetClient(), ctrl.Log.WithName("conversion"))
+		mgr.GetWebhookServer().Register(crdgen.ConversionPath, handler)
+		mgr.GetWebhookServer().Register(crdgen.ConversionPath+"/", handler)
+	}
+
 	if err = (&controllers.ReconTestReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		ConversionWebhook:      conversionWebhook,
+		ConversionCABundleFile: conversionCABundle,
+		ReportDir:              reportDir,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ReconTest")
 		os.Exit(1)
@@ -118,3 +186,24 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// webhookConfig returns where the API server reaches the conversion webhook, either through
+// the <namespace>/<name> of a service or through a URL
+func webhookConfig(service, url, caBundleFile string) (*crdgen.WebhookConfig, error) {
+	caBundle, err := os.ReadFile(caBundleFile)
+	if err != nil {
+		return nil, err
+	}
+	if service == "" {
+		return &crdgen.WebhookConfig{URL: url, CABundle: caBundle}, nil
+	}
+
+	namespace, name, ok := strings.Cut(service, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("--conversion-webhook-service must be <namespace>/<name>, got %q", service)
+	}
+	return &crdgen.WebhookConfig{
+		Service:  &crdgen.ServiceReference{Namespace: namespace, Name: name, Port: 443},
+		CABundle: caBundle,
+	}, nil
+}